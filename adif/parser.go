@@ -0,0 +1,135 @@
+// The package adif parses ADIF logs and feeds the contained QSOs into a [godxmap.Server].
+package adif
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ftl/godxmap"
+)
+
+// Record is a single ADIF record. The field names are in upper case.
+type Record map[string]string
+
+// Parser parses ADIF data incrementally. Data can be fed in arbitrary chunks, complete records are returned
+// as soon as their end-of-record marker was received.
+type Parser struct {
+	buffer   string
+	record   Record
+	inHeader bool
+}
+
+// NewParser creates a new incremental ADIF parser. If the data may start with an ADIF header, the header is skipped.
+func NewParser() *Parser {
+	return &Parser{
+		record: make(Record),
+	}
+}
+
+// Feed adds the given data to the parser and returns all records that are complete now.
+func (p *Parser) Feed(data []byte) []Record {
+	p.buffer += string(data)
+	var result []Record
+	for {
+		start := strings.IndexByte(p.buffer, '<')
+		if start == -1 {
+			p.buffer = ""
+			return result
+		}
+		end := strings.IndexByte(p.buffer[start:], '>')
+		if end == -1 {
+			p.buffer = p.buffer[start:]
+			return result
+		}
+		end += start
+		specifier := p.buffer[start+1 : end]
+		name, lengthAndType, hasLength := strings.Cut(specifier, ":")
+		name = strings.ToUpper(strings.TrimSpace(name))
+
+		if !hasLength {
+			p.buffer = p.buffer[end+1:]
+			switch name {
+			case "EOH":
+				// everything before belongs to the header
+				p.record = make(Record)
+			case "EOR":
+				if len(p.record) > 0 {
+					result = append(result, p.record)
+				}
+				p.record = make(Record)
+			}
+			continue
+		}
+
+		lengthString, _, _ := strings.Cut(lengthAndType, ":")
+		length, err := strconv.Atoi(strings.TrimSpace(lengthString))
+		if err != nil || length < 0 {
+			// invalid field specifier, skip it
+			p.buffer = p.buffer[end+1:]
+			continue
+		}
+		value, complete := cutValue(p.buffer[end+1:], length)
+		if !complete {
+			p.buffer = p.buffer[start:]
+			return result
+		}
+		p.record[name] = value
+		p.buffer = p.buffer[end+1+len(value):]
+	}
+}
+
+// cutValue returns the first length characters of s. ADIF lengths are counted in bytes, but many loggers
+// count characters instead; in case of doubt, the characters are used.
+func cutValue(s string, length int) (string, bool) {
+	if len(s) < length {
+		return "", false
+	}
+	value := s[:length]
+	if utf8.ValidString(value) {
+		return value, true
+	}
+	// the byte length ended in the middle of a character, count characters
+	if utf8.RuneCountInString(s) < length {
+		return "", false
+	}
+	i := 0
+	for j := range s {
+		if i == length {
+			return s[:j], true
+		}
+		i++
+	}
+	return s, true
+}
+
+// QSO converts the given record into a QSO for [godxmap.Server.ShowLoggedQSO].
+func QSO(record Record) godxmap.QSO {
+	frequencyMHz, _ := strconv.ParseFloat(record["FREQ"], 64)
+	exchange := record["SRX_STRING"]
+	if exchange == "" {
+		exchange = record["SRX"]
+	}
+	return godxmap.QSO{
+		Call:         strings.ToUpper(record["CALL"]),
+		FrequencyKHz: frequencyMHz * 1000,
+		Band:         godxmap.Band(strings.ToLower(record["BAND"])),
+		Mode:         godxmap.Mode(strings.ToUpper(record["MODE"])),
+		Exchange:     exchange,
+		Operator:     strings.ToUpper(record["OPERATOR"]),
+		Time:         qsoTime(record["QSO_DATE"], record["TIME_ON"]),
+	}
+}
+
+func qsoTime(date string, timeOn string) time.Time {
+	layout := "20060102150405"
+	if len(timeOn) == 4 {
+		layout = "200601021504"
+	}
+	result, err := time.Parse(layout, date+timeOn)
+	if err != nil {
+		return time.Time{}
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+package adif
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/ftl/godxmap"
+)
+
+// Watcher watches an ADIF file for appended records and shows every new QSO on the map of a [godxmap.Server].
+// This lets any logger that writes ADIF in real time drive the map.
+type Watcher struct {
+	filename string
+	server   *godxmap.Server
+
+	offset int64
+	parser *Parser
+}
+
+// NewWatcher creates a new watcher for the given ADIF file. The QSOs that are already in the file are not shown,
+// only the records that are appended after the watcher was started.
+// To actually watch the file, use the Run method.
+func NewWatcher(filename string, server *godxmap.Server) *Watcher {
+	return &Watcher{
+		filename: filename,
+		server:   server,
+	}
+}
+
+// Run watches the file until the given context is done.
+func (w *Watcher) Run(ctx context.Context) error {
+	notifier, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("cannot watch %s: %v", w.filename, err)
+	}
+	defer notifier.Close()
+	// watch the directory, so the file is still watched after a logger replaced it with a new file
+	err = notifier.Add(filepath.Dir(w.filename))
+	if err != nil {
+		return fmt.Errorf("cannot watch %s: %v", w.filename, err)
+	}
+
+	info, err := os.Stat(w.filename)
+	if err != nil {
+		return fmt.Errorf("cannot watch %s: %v", w.filename, err)
+	}
+	w.offset = info.Size()
+	w.parser = NewParser()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-notifier.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) != filepath.Clean(w.filename) {
+				continue
+			}
+			if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) {
+				w.readAppended()
+			}
+		case err, ok := <-notifier.Errors:
+			if !ok {
+				return nil
+			}
+			w.server.Logger().Warn("error watching ADIF file", "file", w.filename, "error", err)
+		}
+	}
+}
+
+func (w *Watcher) readAppended() {
+	file, err := os.Open(w.filename)
+	if err != nil {
+		w.server.Logger().Warn("cannot open ADIF file", "file", w.filename, "error", err)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		w.server.Logger().Warn("cannot read ADIF file", "file", w.filename, "error", err)
+		return
+	}
+	if info.Size() < w.offset {
+		// the file was truncated or rewritten, the QSOs in it were already shown, so continue at its new end
+		w.offset = info.Size()
+		w.parser = NewParser()
+		return
+	}
+
+	_, err = file.Seek(w.offset, io.SeekStart)
+	if err != nil {
+		w.server.Logger().Warn("cannot read ADIF file", "file", w.filename, "error", err)
+		return
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		w.server.Logger().Warn("cannot read ADIF file", "file", w.filename, "error", err)
+		return
+	}
+	w.offset += int64(len(data))
+
+	for _, record := range w.parser.Feed(data) {
+		qso := QSO(record)
+		if qso.Call == "" {
+			continue
+		}
+		err := w.server.ShowLoggedQSO(qso)
+		if err != nil {
+			w.server.Logger().Warn("cannot show QSO", "call", qso.Call, "error", err)
+		}
+	}
+}
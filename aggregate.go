@@ -0,0 +1,145 @@
+package godxmap
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithSpotAggregation merges the spots of the same station from multiple spotters within the given time window,
+// if the frequency differs by less than the given tolerance in kHz. The merged spot is sent again with the ID of the
+// first spot, so the map clients update the existing marker. It carries the number of spotters and the best SNR
+// that was reported in the comments. Spots that add no new information are dropped.
+//
+// The aggregation supersedes the deduplication of spots (see [WithDeduplication]).
+func WithSpotAggregation(window time.Duration, toleranceKHz float64) Option {
+	return func(s *Server) {
+		s.aggregator = newAggregator(window, toleranceKHz)
+	}
+}
+
+var snrExpression = regexp.MustCompile(`(?i)(?:^|\s)([+-]?\d+)\s?dB\b`)
+
+// snrFromComments returns the signal to noise ratio that is mentioned in the comments of a spot, e.g. "CW 23 dB 25 WPM".
+func snrFromComments(comments string) (int, bool) {
+	matches := snrExpression.FindStringSubmatch(comments)
+	if matches == nil {
+		return 0, false
+	}
+	snr, err := strconv.Atoi(matches[1])
+	return snr, err == nil
+}
+
+type aggregator struct {
+	window       time.Duration
+	toleranceKHz float64
+
+	mutex      sync.Mutex
+	aggregates map[string][]*aggregate
+	lastPrune  time.Time
+}
+
+type aggregate struct {
+	id           string
+	frequencyKHz float64
+	first        time.Time
+	spotters     map[string]bool
+	snr          *int
+}
+
+func newAggregator(window time.Duration, toleranceKHz float64) *aggregator {
+	return &aggregator{
+		window:       window,
+		toleranceKHz: toleranceKHz,
+		aggregates:   make(map[string][]*aggregate),
+	}
+}
+
+// Merge merges the given spot into the matching aggregate, or starts a new aggregate. It returns false if the spot
+// does not add any new information and should be dropped.
+func (a *aggregator) Merge(f *DXSpotFrame) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	t := time.UnixMilli(f.DateTime)
+	call := strings.ToUpper(f.Spot)
+	spotter := strings.ToUpper(f.Spotter)
+	snr, hasSNR := snrFromComments(f.Comments)
+	a.prune(t)
+
+	var current *aggregate
+	for _, candidate := range a.aggregates[call] {
+		if t.Sub(candidate.first) < a.window && math.Abs(candidate.frequencyKHz-f.Frequency) <= a.toleranceKHz {
+			current = candidate
+			break
+		}
+	}
+	if current == nil {
+		current = &aggregate{
+			id:           f.ID,
+			frequencyKHz: f.Frequency,
+			first:        t,
+			spotters:     map[string]bool{spotter: true},
+		}
+		if hasSNR {
+			current.snr = &snr
+			f.SNR = &snr
+		}
+		a.aggregates[call] = append(a.aggregates[call], current)
+		f.Spotters = 1
+		return true
+	}
+
+	newSpotter := !current.spotters[spotter]
+	betterSNR := hasSNR && (current.snr == nil || snr > *current.snr)
+	if !newSpotter && !betterSNR {
+		return false
+	}
+	current.spotters[spotter] = true
+	if betterSNR {
+		current.snr = &snr
+	}
+	if current.id != "" {
+		f.ID = current.id
+	}
+	f.Spotters = len(current.spotters)
+	if current.snr != nil {
+		bestSNR := *current.snr
+		f.SNR = &bestSNR
+	}
+	return true
+}
+
+// prune removes the aggregates that are older than the time window. a.mutex must be held.
+func (a *aggregator) prune(now time.Time) {
+	if now.Sub(a.lastPrune) < a.window {
+		return
+	}
+	a.lastPrune = now
+	for call, aggregates := range a.aggregates {
+		active := aggregates[:0]
+		for _, aggregate := range aggregates {
+			if now.Sub(aggregate.first) < a.window {
+				active = append(active, aggregate)
+			}
+		}
+		if len(active) == 0 {
+			delete(a.aggregates, call)
+		} else {
+			a.aggregates[call] = active
+		}
+	}
+}
+
+func (a *aggregator) Shed(pressure MemoryPressure) {
+	if pressure < MemoryPressureCritical {
+		return
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.aggregates = make(map[string][]*aggregate)
+}
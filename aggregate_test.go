@@ -0,0 +1,79 @@
+package godxmap_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ftl/godxmap"
+	"github.com/ftl/godxmap/godxmaptest"
+)
+
+func TestSpotAggregation(t *testing.T) {
+	clock := godxmaptest.NewClock(time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC))
+	server := godxmaptest.NewServer(t, godxmap.WithSpotAggregation(10*time.Minute, 1), godxmap.WithClock(clock.Now))
+	recorder := godxmaptest.NewRecorder(t, server)
+
+	type expectedSpot struct {
+		aggregate int
+		spotters  int
+		snr       int // 0 means no SNR
+	}
+	// the updated spots of an aggregate are sent with the ID of its first spot
+	ids := make(map[int]string)
+	for _, step := range []struct {
+		advance   time.Duration
+		spotter   string
+		frequency float64
+		comments  string
+		expected  *expectedSpot // nil means dropped
+	}{
+		{0, "W1AW", 14025, "CW 12 dB 25 WPM", &expectedSpot{1, 1, 12}},
+		{time.Minute, "K1TTT", 14025.5, "CW 8 dB", &expectedSpot{1, 2, 12}},
+		{time.Minute, "w1aw", 14025, "CW 10 dB", nil},
+		{time.Minute, "W1AW", 14024.6, "CW 20 dB", &expectedSpot{1, 2, 20}},
+		{time.Minute, "DL0ABC", 14030, "", &expectedSpot{2, 1, 0}},
+		// the window starts with the first spot of an aggregate
+		{7 * time.Minute, "K1TTT", 14025, "", &expectedSpot{3, 1, 0}},
+	} {
+		clock.Advance(step.advance)
+		recorder.Reset()
+		err := server.ShowDXSpot("DL1ABC", step.spotter, step.frequency, step.comments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		server.ShowGab("W1AW", "", "done")
+
+		frames := recorder.Await(1)
+		if step.expected == nil {
+			if len(frames) != 1 || frames[0].FrameType() != godxmap.GabFrameType {
+				t.Errorf("%s: expected the spot to be dropped, got %v", step.spotter, frames)
+			}
+			continue
+		}
+		frames = recorder.Await(2)
+		spot, ok := frames[0].(*godxmap.DXSpotFrame)
+		if !ok {
+			t.Fatalf("%s: unexpected frame %v", step.spotter, frames[0])
+		}
+		if spot.Spotters != step.expected.spotters {
+			t.Errorf("%s: expected %d spotters, got %d", step.spotter, step.expected.spotters, spot.Spotters)
+		}
+		switch {
+		case step.expected.snr == 0 && spot.SNR != nil:
+			t.Errorf("%s: expected no SNR, got %d", step.spotter, *spot.SNR)
+		case step.expected.snr != 0 && (spot.SNR == nil || *spot.SNR != step.expected.snr):
+			t.Errorf("%s: expected SNR %d, got %v", step.spotter, step.expected.snr, spot.SNR)
+		}
+		id, ok := ids[step.expected.aggregate]
+		if !ok {
+			for _, other := range ids {
+				if spot.ID == other {
+					t.Errorf("%s: the ID %s of a new aggregate is already used", step.spotter, spot.ID)
+				}
+			}
+			ids[step.expected.aggregate] = spot.ID
+		} else if spot.ID != id {
+			t.Errorf("%s: expected the ID %s of the aggregate, got %s", step.spotter, id, spot.ID)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// The package aprs provides a client for APRS-IS that shows the positions of stations on the map of a [godxmap.Server],
+// e.g. to follow rover and portable stations during VHF contests.
+package aprs
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+// DefaultAddr is the address of the APRS-IS server pool, using the port that supports server side filters.
+const DefaultAddr = "rotate.aprs2.net:14580"
+
+const (
+	dialTimeout     = 10 * time.Second
+	readTimeout     = 2 * time.Minute
+	softwareName    = "godxmap"
+	softwareVersion = "1.0"
+	receiveOnly     = -1
+	locatorLength   = 6
+)
+
+// CallFilter returns an APRS-IS server side filter for the given callsigns. Wildcards are allowed, e.g. "DL1ABC*".
+func CallFilter(calls ...string) string {
+	return "b/" + strings.Join(calls, "/")
+}
+
+// RangeFilter returns an APRS-IS server side filter for all stations within the given distance in kilometers around
+// the given position in degrees.
+func RangeFilter(latitude float64, longitude float64, distanceKm float64) string {
+	return fmt.Sprintf("r/%.2f/%.2f/%.0f", latitude, longitude, distanceKm)
+}
+
+// Client connects to APRS-IS and shows the position reports that pass the server side filter as partial calls
+// at their exact position. Every new report of a station moves it on the map.
+type Client struct {
+	addr     string
+	call     string
+	filter   string
+	passcode int
+	server   *godxmap.Server
+}
+
+// Option configures a [Client] instance.
+type Option func(*Client)
+
+// WithPasscode logs in with the given APRS-IS passcode. By default, the client logs in receive-only.
+func WithPasscode(passcode int) Option {
+	return func(c *Client) {
+		c.passcode = passcode
+	}
+}
+
+// NewClient creates a new client for the APRS-IS server at the given address that logs in with the given callsign
+// and only receives the packets that pass the given server side filter, see [CallFilter] and [RangeFilter].
+// Multiple filters are separated by spaces. To actually connect to APRS-IS, use the Run method.
+func NewClient(addr string, call string, filter string, server *godxmap.Server, options ...Option) *Client {
+	result := &Client{
+		addr:     addr,
+		call:     call,
+		filter:   filter,
+		passcode: receiveOnly,
+		server:   server,
+	}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+// Run connects to APRS-IS and processes the received packets until the connection is closed or the given context is done.
+func (c *Client) Run(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return fmt.Errorf("cannot connect to APRS-IS %s: %v", c.addr, err)
+	}
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	login := fmt.Sprintf("user %s pass %d vers %s %s", c.call, c.passcode, softwareName, softwareVersion)
+	if c.filter != "" {
+		login += " filter " + c.filter
+	}
+	_, err = io.WriteString(conn, login+"\r\n")
+	if err != nil {
+		return fmt.Errorf("cannot log in to APRS-IS %s: %v", c.addr, err)
+	}
+
+	lines := bufio.NewReader(conn)
+	for {
+		// the server sends a keepalive comment every 20 seconds
+		conn.SetReadDeadline(time.Now().Add(readTimeout))
+		line, err := lines.ReadString('\n')
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("connection to APRS-IS %s lost: %v", c.addr, err)
+		}
+		if strings.HasPrefix(line, "# logresp") && strings.Contains(line, "unverified") && c.passcode != receiveOnly {
+			c.server.Logger().Warn("APRS-IS login not verified", "addr", c.addr, "response", strings.TrimSpace(line))
+		}
+		position, ok := ParsePacket(line)
+		if ok {
+			c.show(position)
+		}
+	}
+}
+
+func (c *Client) show(position Position) {
+	latLon := godxmap.LatLon{Latitude: position.Latitude, Longitude: position.Longitude}
+	err := c.server.ShowPartialCallInfo(position.Call, godxmap.CallInfo{
+		Locator:  latLon.Locator(locatorLength),
+		Position: &latLon,
+	})
+	if err != nil {
+		c.server.Logger().Warn("cannot show APRS position", "call", position.Call, "error", err)
+	}
+}
@@ -0,0 +1,207 @@
+package aprs
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Position is a position report received from APRS-IS.
+type Position struct {
+	Call      string
+	Latitude  float64
+	Longitude float64
+	// Symbol is the APRS symbol, consisting of the symbol table and the symbol code, e.g. "/>" for a car.
+	Symbol  string
+	Comment string
+}
+
+// ParsePacket parses a packet in the TNC2 format as it is sent by APRS-IS, e.g. "DL1ABC-9>APRS,TCPIP*:!5130.00N/00730.00E>",
+// and extracts the position report. Uncompressed, compressed and Mic-E positions are supported.
+// Objects, items, messages and other packets without the position of the sender are ignored.
+func ParsePacket(line string) (Position, bool) {
+	line = strings.TrimRight(line, "\r\n")
+	if strings.HasPrefix(line, "#") {
+		// server comment
+		return Position{}, false
+	}
+	header, info, found := strings.Cut(line, ":")
+	if !found || info == "" {
+		return Position{}, false
+	}
+	source, path, found := strings.Cut(header, ">")
+	if !found || source == "" {
+		return Position{}, false
+	}
+	destination, _, _ := strings.Cut(path, ",")
+
+	var result Position
+	var ok bool
+	switch info[0] {
+	case '!', '=':
+		result, ok = parsePosition(info[1:])
+	case '/', '@':
+		// position with a timestamp of seven characters
+		if len(info) < 8 {
+			return Position{}, false
+		}
+		result, ok = parsePosition(info[8:])
+	case '`', '\'':
+		result, ok = parseMicE(destination, info[1:])
+	}
+	if !ok {
+		return Position{}, false
+	}
+	result.Call = source
+	return result, true
+}
+
+func parsePosition(data string) (Position, bool) {
+	if len(data) > 0 && isDigitOrSpace(data[0]) {
+		// the symbol table of a compressed position is never a digit
+		if len(data) < 19 {
+			return Position{}, false
+		}
+		return parseUncompressed(data)
+	}
+	if len(data) >= 13 {
+		return parseCompressed(data)
+	}
+	return Position{}, false
+}
+
+// parseUncompressed parses a position like "5130.00N/00730.00E>comment".
+func parseUncompressed(data string) (Position, bool) {
+	latitude, ok := parseDegrees(data[0:2], data[2:7], data[7])
+	if !ok {
+		return Position{}, false
+	}
+	longitude, ok := parseDegrees(data[9:12], data[12:17], data[17])
+	if !ok || math.Abs(latitude) > 90 || math.Abs(longitude) > 180 {
+		return Position{}, false
+	}
+	return Position{
+		Latitude:  latitude,
+		Longitude: longitude,
+		Symbol:    string([]byte{data[8], data[18]}),
+		Comment:   strings.TrimSpace(data[19:]),
+	}, true
+}
+
+// parseDegrees parses degrees and minutes. Spaces for the position ambiguity are treated as zeros.
+func parseDegrees(degrees string, minutes string, hemisphere byte) (float64, bool) {
+	d, err := strconv.Atoi(strings.ReplaceAll(degrees, " ", "0"))
+	if err != nil {
+		return 0, false
+	}
+	m, err := strconv.ParseFloat(strings.ReplaceAll(minutes, " ", "0"), 64)
+	if err != nil {
+		return 0, false
+	}
+	result := float64(d) + m/60
+	switch hemisphere {
+	case 'N', 'n', 'E', 'e':
+		return result, true
+	case 'S', 's', 'W', 'w':
+		return -result, true
+	default:
+		return 0, false
+	}
+}
+
+// parseCompressed parses a compressed position: symbol table, four base91 digits latitude,
+// four base91 digits longitude, symbol code, course/speed and compression type.
+func parseCompressed(data string) (Position, bool) {
+	y, ok := base91(data[1:5])
+	if !ok {
+		return Position{}, false
+	}
+	x, ok := base91(data[5:9])
+	if !ok {
+		return Position{}, false
+	}
+	return Position{
+		Latitude:  90 - float64(y)/380926,
+		Longitude: -180 + float64(x)/190463,
+		Symbol:    string([]byte{data[0], data[9]}),
+		Comment:   strings.TrimSpace(data[13:]),
+	}, true
+}
+
+func base91(data string) (int, bool) {
+	result := 0
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if c < 33 || c > 124 {
+			return 0, false
+		}
+		result = result*91 + int(c-33)
+	}
+	return result, true
+}
+
+// parseMicE parses a Mic-E position. The latitude is encoded in the destination address, the longitude
+// in the first three bytes of the information field.
+func parseMicE(destination string, data string) (Position, bool) {
+	destination, _, _ = strings.Cut(destination, "-")
+	if len(destination) != 6 || len(data) < 8 {
+		return Position{}, false
+	}
+
+	digits := make([]byte, 6)
+	for i := 0; i < 6; i++ {
+		c := destination[i]
+		switch {
+		case c >= '0' && c <= '9':
+			digits[i] = c
+		case c >= 'A' && c <= 'J':
+			digits[i] = c - 'A' + '0'
+		case c >= 'P' && c <= 'Y':
+			digits[i] = c - 'P' + '0'
+		case c == 'K' || c == 'L' || c == 'Z':
+			digits[i] = '0'
+		default:
+			return Position{}, false
+		}
+	}
+	latitude, ok := parseDegrees(string(digits[0:2]), string(digits[2:4])+"."+string(digits[4:6]), 'N')
+	if !ok {
+		return Position{}, false
+	}
+	if destination[3] < 'P' {
+		latitude = -latitude
+	}
+
+	degrees := int(data[0]) - 28
+	if destination[4] >= 'P' {
+		degrees += 100
+	}
+	if degrees >= 180 && degrees <= 189 {
+		degrees -= 80
+	} else if degrees >= 190 && degrees <= 199 {
+		degrees -= 190
+	}
+	minutes := int(data[1]) - 28
+	if minutes >= 60 {
+		minutes -= 60
+	}
+	hundredths := int(data[2]) - 28
+	longitude := float64(degrees) + (float64(minutes)+float64(hundredths)/100)/60
+	if destination[5] >= 'P' {
+		longitude = -longitude
+	}
+	if math.Abs(latitude) > 90 || math.Abs(longitude) > 180 {
+		return Position{}, false
+	}
+
+	return Position{
+		Latitude:  latitude,
+		Longitude: longitude,
+		Symbol:    string([]byte{data[7], data[6]}),
+		Comment:   strings.TrimSpace(data[8:]),
+	}, true
+}
+
+func isDigitOrSpace(c byte) bool {
+	return c == ' ' || (c >= '0' && c <= '9')
+}
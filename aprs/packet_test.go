@@ -0,0 +1,97 @@
+package aprs
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParsePacket(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		line     string
+		expected Position
+	}{
+		{
+			name:     "uncompressed position",
+			line:     "DL1ABC-9>APRS,TCPIP*,qAC,T2ERFURT:!5130.00N/00730.00E>Test\r\n",
+			expected: Position{Call: "DL1ABC-9", Latitude: 51.5, Longitude: 7.5, Symbol: "/>", Comment: "Test"},
+		},
+		{
+			name:     "uncompressed position with messaging",
+			line:     "W1AW>APRS,TCPIP*:=4142.86N/07243.64W-PHG5130 ARRL HQ",
+			expected: Position{Call: "W1AW", Latitude: 41.714333, Longitude: -72.727333, Symbol: "/-", Comment: "PHG5130 ARRL HQ"},
+		},
+		{
+			name:     "position with timestamp",
+			line:     "K1ABC>APRS,TCPIP*:@092345z4903.50N/07201.75W>088/036",
+			expected: Position{Call: "K1ABC", Latitude: 49.058333, Longitude: -72.029167, Symbol: "/>", Comment: "088/036"},
+		},
+		{
+			name:     "position ambiguity",
+			line:     "DL2XYZ>APRS:!51  .  N\\007  .  E&",
+			expected: Position{Call: "DL2XYZ", Latitude: 51, Longitude: 7, Symbol: "\\&"},
+		},
+		{
+			name:     "oversized comment",
+			line:     "DL1ABC>APRS:!5130.00N/00730.00E>" + strings.Repeat("x", 100000),
+			expected: Position{Call: "DL1ABC", Latitude: 51.5, Longitude: 7.5, Symbol: "/>", Comment: strings.Repeat("x", 100000)},
+		},
+		{
+			name:     "compressed position",
+			line:     "K1ABC>APRS:=/5L!!<*e7>7P[",
+			expected: Position{Call: "K1ABC", Latitude: 49.5, Longitude: -72.75, Symbol: "/>"},
+		},
+		{
+			name:     "Mic-E position",
+			line:     "K1ABC-7>S32U6T,WIDE1-1,qAR,W1AW:`dI:l!!>/mobile",
+			expected: Position{Call: "K1ABC-7", Latitude: 33.427333, Longitude: -72.755, Symbol: "/>", Comment: "mobile"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, ok := ParsePacket(tc.line)
+			if !ok {
+				t.Fatal("the packet was not parsed")
+			}
+			if actual.Call != tc.expected.Call || actual.Symbol != tc.expected.Symbol || actual.Comment != tc.expected.Comment {
+				t.Errorf("expected\n%+v\ngot\n%+v", tc.expected, actual)
+			}
+			if math.Abs(actual.Latitude-tc.expected.Latitude) > 1e-4 || math.Abs(actual.Longitude-tc.expected.Longitude) > 1e-4 {
+				t.Errorf("expected position %f, %f, got %f, %f", tc.expected.Latitude, tc.expected.Longitude, actual.Latitude, actual.Longitude)
+			}
+		})
+	}
+}
+
+func TestParseIgnoredPacket(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		line string
+	}{
+		{"empty", ""},
+		{"server comment", "# aprsc 2.1.19-g730c5c0 25 Oct 2025 12:30:00 GMT T2ERFURT 1.2.3.4:14580"},
+		{"missing header", "!5130.00N/00730.00E>"},
+		{"missing source", ">APRS:!5130.00N/00730.00E>"},
+		{"empty information", "DL1ABC>APRS:"},
+		{"status", "DL1ABC>APRS:>on the air"},
+		{"message", "DL1ABC>APRS::W1AW     :hello{1"},
+		{"object", "DL1ABC>APRS:;LEADER   *092345z4903.50N/07201.75W>"},
+		{"truncated position", "DL1ABC>APRS:!5130.00N/0073"},
+		{"truncated timestamp", "DL1ABC>APRS:@0923"},
+		{"truncated compressed position", "DL1ABC>APRS:!/5L!!<*e"},
+		{"invalid hemisphere", "DL1ABC>APRS:!5130.00X/00730.00E>"},
+		{"invalid digits", "DL1ABC>APRS:!51A0.00N/00730.00E>"},
+		{"latitude out of range", "DL1ABC>APRS:!9130.00N/00730.00E>"},
+		{"longitude out of range", "DL1ABC>APRS:!5130.00N/18130.00E>"},
+		{"invalid base91 digit", "DL1ABC>APRS:!/5L!~<*e7>7P["},
+		{"truncated Mic-E", "K1ABC-7>S32U6T:`dI:l!"},
+		{"invalid Mic-E destination", "K1ABC-7>APRS:`dI:l!!>/"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, ok := ParsePacket(tc.line)
+			if ok {
+				t.Errorf("expected the packet to be ignored, got %+v", actual)
+			}
+		})
+	}
+}
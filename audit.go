@@ -0,0 +1,289 @@
+package godxmap
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditEventType classifies audit events.
+type AuditEventType string
+
+// The types of audit events.
+const (
+	AuditClientConnected    AuditEventType = "ClientConnected"
+	AuditClientDisconnected AuditEventType = "ClientDisconnected"
+	AuditAuthFailure        AuditEventType = "AuthFailure"
+	AuditAdminAction        AuditEventType = "AdminAction"
+	AuditConfigReload       AuditEventType = "ConfigReload"
+)
+
+// AuditEvent describes a security relevant event.
+type AuditEvent struct {
+	Time       time.Time
+	Type       AuditEventType
+	RemoteAddr string
+	Message    string
+	Fields     map[string]string
+}
+
+// Auditor records audit events.
+type Auditor interface {
+	Audit(AuditEvent)
+}
+
+// WithAuditor reports all audit events of the server to the given auditor.
+func WithAuditor(auditor Auditor) Option {
+	return func(s *Server) {
+		s.auditor = auditor
+	}
+}
+
+// Audit reports an audit event of the host application, e.g. an admin action or a config reload,
+// through the auditor of this server. If the server has no auditor, Audit does nothing.
+func (s *Server) Audit(eventType AuditEventType, message string, fields map[string]string) {
+	s.audit(AuditEvent{
+		Type:    eventType,
+		Message: message,
+		Fields:  fields,
+	})
+}
+
+func (s *Server) audit(event AuditEvent) {
+	if s.auditor == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = s.now()
+	}
+	s.auditor.Audit(event)
+}
+
+// auditAdminAction reports every request to the given handler as admin action, e.g. on the REST API or the debug page.
+func (s *Server) auditAdminAction(next http.Handler) http.Handler {
+	if s.auditor == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.audit(AuditEvent{
+			Type:       AuditAdminAction,
+			RemoteAddr: r.RemoteAddr,
+			Message:    r.Method + " " + r.URL.Path,
+			Fields:     map[string]string{"method": r.Method, "path": r.URL.Path},
+		})
+		next.ServeHTTP(w, r)
+	})
+}
+
+// SyslogConfig describes the remote syslog server for a [SyslogAuditor].
+type SyslogConfig struct {
+	// Network is either "udp" or "tcp".
+	Network string
+	// Addr is the address of the remote syslog server, e.g. "loghost:514".
+	Addr string
+	// Facility is the syslog facility code, e.g. 16 for local0.
+	Facility int
+	// AppName identifies the application in the syslog messages. It defaults to "godxmap".
+	AppName string
+}
+
+const (
+	syslogSeverityWarning = 4
+	syslogSeverityNotice  = 5
+	syslogSeverityInfo    = 6
+
+	// the private enterprise number 32473 is reserved for documentation purposes
+	syslogStructuredDataID = "audit@32473"
+)
+
+// SyslogAuditor ships audit events as RFC 5424 messages with structured data to a remote syslog server.
+// The events are queued and delivered in the background, so a slow or unreachable syslog server does not
+// block the server. If the queue is full, new events are dropped.
+type SyslogAuditor struct {
+	config   SyslogConfig
+	hostname string
+
+	queue   chan string
+	closing chan struct{}
+	closed  chan struct{}
+	once    sync.Once
+
+	// conn is only used by the delivery goroutine
+	conn net.Conn
+}
+
+const syslogQueueSize = 256
+
+// NewSyslogAuditor connects to the remote syslog server described by the given configuration.
+func NewSyslogAuditor(config SyslogConfig) (*SyslogAuditor, error) {
+	if config.AppName == "" {
+		config.AppName = "godxmap"
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+	result := &SyslogAuditor{
+		config:   config,
+		hostname: hostname,
+		queue:    make(chan string, syslogQueueSize),
+		closing:  make(chan struct{}),
+		closed:   make(chan struct{}),
+	}
+
+	err = result.connect()
+	if err != nil {
+		return nil, err
+	}
+	go result.run()
+	return result, nil
+}
+
+func (a *SyslogAuditor) connect() error {
+	conn, err := net.DialTimeout(a.config.Network, a.config.Addr, writeTimeout*10)
+	if err != nil {
+		return fmt.Errorf("cannot connect to syslog server: %v", err)
+	}
+	a.conn = conn
+	return nil
+}
+
+// Close delivers the queued events and closes the connection to the remote syslog server.
+func (a *SyslogAuditor) Close() error {
+	a.once.Do(func() {
+		close(a.closing)
+	})
+	<-a.closed
+
+	if a.conn == nil {
+		return nil
+	}
+	err := a.conn.Close()
+	a.conn = nil
+	return err
+}
+
+// Audit queues the given event for the remote syslog server. If the queue is full, the event is dropped.
+func (a *SyslogAuditor) Audit(event AuditEvent) {
+	message := a.format(event)
+	if a.config.Network != "udp" {
+		// octet counting framing as defined in RFC 6587
+		message = fmt.Sprintf("%d %s", len(message), message)
+	}
+
+	select {
+	case a.queue <- message:
+	default:
+	}
+}
+
+// run delivers the queued messages until the auditor is closed, then it delivers the remaining messages
+// as long as the syslog server is reachable.
+func (a *SyslogAuditor) run() {
+	defer close(a.closed)
+	for {
+		select {
+		case message := <-a.queue:
+			a.send(message)
+		case <-a.closing:
+			for {
+				select {
+				case message := <-a.queue:
+					if !a.send(message) {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// send writes the given message to the remote syslog server. If the connection is broken, send tries to reconnect once.
+func (a *SyslogAuditor) send(message string) bool {
+	for attempt := 0; attempt < 2; attempt++ {
+		if a.conn == nil && a.connect() != nil {
+			continue
+		}
+		a.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		_, err := a.conn.Write([]byte(message))
+		if err == nil {
+			return true
+		}
+		a.conn.Close()
+		a.conn = nil
+	}
+	return false
+}
+
+func (a *SyslogAuditor) format(event AuditEvent) string {
+	severity := syslogSeverityInfo
+	switch event.Type {
+	case AuditAuthFailure:
+		severity = syslogSeverityWarning
+	case AuditAdminAction, AuditConfigReload:
+		severity = syslogSeverityNotice
+	}
+	priority := a.config.Facility*8 + severity
+
+	params := map[string]string{"type": string(event.Type)}
+	if event.RemoteAddr != "" {
+		params["remote"] = event.RemoteAddr
+	}
+	for k, v := range event.Fields {
+		params[sdParamName(k)] = v
+	}
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	structuredData := new(strings.Builder)
+	structuredData.WriteString("[" + syslogStructuredDataID)
+	for _, k := range keys {
+		fmt.Fprintf(structuredData, ` %s="%s"`, k, escapeSDParam(params[k]))
+	}
+	structuredData.WriteString("]")
+
+	return fmt.Sprintf("<%d>1 %s %s %s %d %s %s %s",
+		priority,
+		event.Time.UTC().Format(time.RFC3339Nano),
+		a.hostname,
+		a.config.AppName,
+		os.Getpid(),
+		event.Type,
+		structuredData,
+		event.Message,
+	)
+}
+
+// sdParamName turns the given string into a valid PARAM-NAME as defined in RFC 5424: 1 to 32 printable
+// US-ASCII characters except '=', ' ', ']' and '"'. All other characters are replaced with '_'.
+func sdParamName(s string) string {
+	result := []byte(s)
+	if len(result) > 32 {
+		result = result[:32]
+	}
+	for i, c := range result {
+		if c < 33 || c > 126 || c == '=' || c == ']' || c == '"' {
+			result[i] = '_'
+		}
+	}
+	if len(result) == 0 {
+		return "_"
+	}
+	return string(result)
+}
+
+var sdParamEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+func escapeSDParam(s string) string {
+	return sdParamEscaper.Replace(s)
+}
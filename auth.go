@@ -0,0 +1,53 @@
+package godxmap
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// TokenParameter is the name of the query parameter that carries the access token during the websocket handshake.
+// Alternatively, the token can be presented in the Authorization header as bearer token.
+const TokenParameter = "token"
+
+// TokenValidator decides if the given token grants access to the map feed.
+// The handshake request is provided to allow decisions based on e.g. the remote address.
+type TokenValidator func(token string, r *http.Request) bool
+
+// WithTokenAuthentication requires every client to present a token during the websocket handshake
+// that is accepted by the given validator.
+func WithTokenAuthentication(validate TokenValidator) Option {
+	return func(s *Server) {
+		s.validateToken = validate
+	}
+}
+
+// SharedSecret returns a [TokenValidator] that only accepts the given secret.
+func SharedSecret(secret string) TokenValidator {
+	return func(token string, _ *http.Request) bool {
+		return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+	}
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	if s.validateToken == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := requestToken(r)
+		if token == "" || !s.validateToken(token, r) {
+			s.audit(AuditEvent{Type: AuditAuthFailure, RemoteAddr: r.RemoteAddr, Message: "invalid token"})
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func requestToken(r *http.Request) string {
+	authorization := r.Header.Get("Authorization")
+	if token, found := strings.CutPrefix(authorization, "Bearer "); found {
+		return strings.TrimSpace(token)
+	}
+	return r.URL.Query().Get(TokenParameter)
+}
@@ -0,0 +1,178 @@
+package godxmap
+
+import "strings"
+
+// Band identifies an amateur radio band by its common name, e.g. "20m".
+type Band string
+
+// The amateur radio bands known to godxmap.
+const (
+	NoBand   Band = ""
+	Band160m Band = "160m"
+	Band80m  Band = "80m"
+	Band60m  Band = "60m"
+	Band40m  Band = "40m"
+	Band30m  Band = "30m"
+	Band20m  Band = "20m"
+	Band17m  Band = "17m"
+	Band15m  Band = "15m"
+	Band12m  Band = "12m"
+	Band10m  Band = "10m"
+	Band6m   Band = "6m"
+	Band4m   Band = "4m"
+	Band2m   Band = "2m"
+	Band70cm Band = "70cm"
+	Band23cm Band = "23cm"
+)
+
+type bandRange struct {
+	band    Band
+	fromKHz float64
+	toKHz   float64
+}
+
+// the ranges are intentionally generous to cover the different IARU regions
+var bandRanges = []bandRange{
+	{Band160m, 1800, 2000},
+	{Band80m, 3500, 4000},
+	{Band60m, 5060, 5450},
+	{Band40m, 7000, 7300},
+	{Band30m, 10100, 10150},
+	{Band20m, 14000, 14350},
+	{Band17m, 18068, 18168},
+	{Band15m, 21000, 21450},
+	{Band12m, 24890, 24990},
+	{Band10m, 28000, 29700},
+	{Band6m, 50000, 54000},
+	{Band4m, 70000, 70500},
+	{Band2m, 144000, 148000},
+	{Band70cm, 420000, 450000},
+	{Band23cm, 1240000, 1300000},
+}
+
+// BandOf returns the band that contains the given frequency in kHz.
+// If the frequency is outside of all known bands, BandOf returns NoBand.
+func BandOf(frequencyKHz float64) Band {
+	for _, r := range bandRanges {
+		if frequencyKHz >= r.fromKHz && frequencyKHz <= r.toKHz {
+			return r.band
+		}
+	}
+	return NoBand
+}
+
+// Mode identifies an operating mode.
+type Mode string
+
+// The operating modes known to godxmap.
+const (
+	NoMode   Mode = ""
+	ModeCW   Mode = "CW"
+	ModeSSB  Mode = "SSB"
+	ModeFM   Mode = "FM"
+	ModeRTTY Mode = "RTTY"
+	ModeFT8  Mode = "FT8"
+	ModeFT4  Mode = "FT4"
+	ModePSK  Mode = "PSK"
+	ModeWSPR Mode = "WSPR"
+)
+
+var knownModes = []Mode{ModeCW, ModeSSB, ModeFM, ModeRTTY, ModeFT8, ModeFT4, ModePSK, ModeWSPR}
+
+type modeRange struct {
+	mode    Mode
+	fromKHz float64
+	toKHz   float64
+}
+
+// the mode ranges follow the IARU region 1 band plan; the more specific ranges come first
+var modeRanges = []modeRange{
+	{ModeFT8, 1840, 1843},
+	{ModeFT8, 3573, 3576},
+	{ModeFT8, 7074, 7077},
+	{ModeFT8, 10136, 10139},
+	{ModeFT8, 14074, 14077},
+	{ModeFT8, 18100, 18103},
+	{ModeFT8, 21074, 21077},
+	{ModeFT8, 24915, 24918},
+	{ModeFT8, 28074, 28077},
+	{ModeFT8, 50313, 50316},
+	{ModeFT4, 3575, 3578},
+	{ModeFT4, 7047, 7050},
+	{ModeFT4, 10140, 10143},
+	{ModeFT4, 14080, 14083},
+	{ModeFT4, 18104, 18107},
+	{ModeFT4, 21140, 21143},
+	{ModeFT4, 24919, 24922},
+	{ModeFT4, 28180, 28183},
+	{ModeRTTY, 3580, 3600},
+	{ModeRTTY, 7040, 7050},
+	{ModeRTTY, 14083, 14100},
+	{ModeRTTY, 21080, 21120},
+	{ModeRTTY, 28080, 28150},
+	{ModeCW, 1800, 1838},
+	{ModeCW, 3500, 3570},
+	{ModeCW, 7000, 7040},
+	{ModeCW, 10100, 10130},
+	{ModeCW, 14000, 14070},
+	{ModeCW, 18068, 18095},
+	{ModeCW, 21000, 21070},
+	{ModeCW, 24890, 24915},
+	{ModeCW, 28000, 28070},
+	{ModeCW, 50000, 50100},
+	{ModeCW, 144000, 144150},
+	{ModeFM, 29520, 29700},
+	{ModeFM, 51000, 54000},
+	{ModeFM, 145000, 148000},
+	{ModeSSB, 1843, 2000},
+	{ModeSSB, 3600, 4000},
+	{ModeSSB, 7050, 7300},
+	{ModeSSB, 14100, 14350},
+	{ModeSSB, 18110, 18168},
+	{ModeSSB, 21150, 21450},
+	{ModeSSB, 24930, 24990},
+	{ModeSSB, 28300, 29300},
+	{ModeSSB, 50100, 50500},
+	{ModeSSB, 144150, 144400},
+}
+
+// ModeOf returns the operating mode that is usually used on the given frequency in kHz, according to the band plan.
+// If the band plan does not define a usual mode for the frequency, ModeOf returns NoMode.
+func ModeOf(frequencyKHz float64) Mode {
+	for _, r := range modeRanges {
+		if frequencyKHz >= r.fromKHz && frequencyKHz < r.toKHz {
+			return r.mode
+		}
+	}
+	return NoMode
+}
+
+// inferMode infers the mode of a spot from its comments or, if not mentioned there, from the band plan.
+func inferMode(frequencyKHz float64, comments string) Mode {
+	result := ModeFromComments(comments)
+	if result == NoMode {
+		result = ModeOf(frequencyKHz)
+	}
+	return result
+}
+
+// ModeFromComments returns the first known operating mode that is mentioned in the comments of a spot.
+// If no known mode is mentioned, ModeFromComments returns NoMode.
+func ModeFromComments(comments string) Mode {
+	// this is called for every spot, the words are compared case-insensitively to avoid allocations
+	for _, word := range strings.Fields(comments) {
+		word = strings.Trim(word, ".,;:!?()[]")
+		for _, mode := range knownModes {
+			if strings.EqualFold(word, string(mode)) {
+				return mode
+			}
+		}
+		switch {
+		case strings.EqualFold(word, "USB"), strings.EqualFold(word, "LSB"):
+			return ModeSSB
+		case strings.EqualFold(word, "PSK31"), strings.EqualFold(word, "PSK63"), strings.EqualFold(word, "BPSK31"):
+			return ModePSK
+		}
+	}
+	return NoMode
+}
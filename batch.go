@@ -0,0 +1,193 @@
+package godxmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// message is the unit of transmission to the clients: either a single frame or a batch of frames
+// that is sent as JSON array in one websocket message.
+type message struct {
+	frames   []Frame
+	batch    bool
+	encoding *messageEncoding
+	cache    *encodingCache
+	// to is the only client that gets the message, see [Server.SendTo]. Zero means all clients.
+	to ClientID
+}
+
+// messageEncoding caches the JSON encoding of a broadcast message, so the message is serialized only once
+// and all clients get the same bytes.
+type messageEncoding struct {
+	once sync.Once
+	data []byte
+	err  error
+}
+
+// encodingCache caches the encodings of the messages that are derived from a broadcast message for the single
+// clients, e.g. by their filters or in the format of the original gateway. This way, all clients that get the same
+// frames also share the same bytes.
+type encodingCache struct {
+	// index maps the frames of the broadcast message to their position
+	index map[*FrameHeader]int
+
+	mutex   sync.Mutex
+	subsets map[string]*messageEncoding
+	compat  map[*FrameHeader]*messageEncoding
+}
+
+// encodeOnce returns the message with a cache for its encoding. The frames must not be modified afterwards.
+// The frames are not pooled, because the history, the state and the sinks retain them.
+func (m message) encodeOnce() message {
+	m.encoding = new(messageEncoding)
+	m.cache = &encodingCache{
+		index:   make(map[*FrameHeader]int, len(m.frames)),
+		subsets: make(map[string]*messageEncoding),
+		compat:  make(map[*FrameHeader]*messageEncoding),
+	}
+	for i, f := range m.frames {
+		m.cache.index[f.Header()] = i
+	}
+	return m
+}
+
+// derive returns a message with the given subset of the frames of this message. It shares the cached encodings
+// with all other messages that are derived from the same broadcast message.
+func (m message) derive(frames []Frame) message {
+	result := message{frames: frames, batch: m.batch, cache: m.cache}
+	if m.cache != nil {
+		result.encoding = m.cache.subset(frames)
+	}
+	return result
+}
+
+// subset returns the cached encoding of the given subset of the frames of the broadcast message.
+// If one of the frames is not part of the broadcast message, subset returns nil.
+func (c *encodingCache) subset(frames []Frame) *messageEncoding {
+	key := make([]byte, (len(c.index)+7)/8)
+	for _, f := range frames {
+		i, ok := c.index[f.Header()]
+		if !ok {
+			return nil
+		}
+		key[i/8] |= 1 << (i % 8)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	result, ok := c.subsets[string(key)]
+	if !ok {
+		result = new(messageEncoding)
+		c.subsets[string(key)] = result
+	}
+	return result
+}
+
+// compatFrame returns the cached encoding of the given frame in the format of the original gateway.
+func (c *encodingCache) compatFrame(f Frame) *messageEncoding {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	result, ok := c.compat[f.Header()]
+	if !ok {
+		result = new(messageEncoding)
+		c.compat[f.Header()] = result
+	}
+	return result
+}
+
+// encode returns the JSON encoding of the message.
+func (m message) encode() ([]byte, error) {
+	return m.encoding.marshal(m.payload)
+}
+
+// encodeCompat returns the JSON encoding of the given frame of the message in the format of the original gateway.
+func (m message) encodeCompat(f Frame) ([]byte, error) {
+	if m.cache == nil {
+		return json.Marshal(winTestFrame(f))
+	}
+	return m.cache.compatFrame(f).marshal(func() any { return winTestFrame(f) })
+}
+
+// marshal returns the JSON encoding of the given payload, it is only encoded once. Without encoding cache,
+// the payload is encoded every time.
+func (e *messageEncoding) marshal(payload func() any) ([]byte, error) {
+	if e == nil {
+		return json.Marshal(payload())
+	}
+	e.once.Do(func() {
+		e.data, e.err = json.Marshal(payload())
+	})
+	return e.data, e.err
+}
+
+func singleFrame(f Frame) message {
+	return message{frames: []Frame{f}}
+}
+
+func (m message) payload() any {
+	if m.batch {
+		return m.frames
+	}
+	return m.frames[0]
+}
+
+func (m message) String() string {
+	if m.batch {
+		return fmt.Sprintf("batch of %d frames", len(m.frames))
+	}
+	return "frame " + m.frames[0].Header().ID
+}
+
+// SendBatch sends the given frames in a single websocket message as JSON array. This reduces the overhead
+// when many frames are sent at once, e.g. when a log is replayed. Empty header fields are filled in automatically.
+//
+// If one of the frames is invalid, no frame is sent.
+func (s *Server) SendBatch(frames []Frame) error {
+	checked := make([]Frame, 0, len(frames))
+	for _, f := range frames {
+		s.fillHeader(f)
+		f, ok, err := s.check(f)
+		if err != nil {
+			return err
+		}
+		if ok {
+			checked = append(checked, f)
+		}
+	}
+	// the deduplication only remembers the spots of a batch that is actually sent
+	prepared := slices.DeleteFunc(checked, func(f Frame) bool {
+		return !s.admit(f)
+	})
+	if len(prepared) == 0 {
+		return nil
+	}
+	s.flushCoalesced()
+
+	sent, ok, err := s.broadcast(message{frames: prepared, batch: true})
+	if !ok {
+		return err
+	}
+
+	for _, f := range sent.frames {
+		if spot, ok := f.(*DXSpotFrame); ok {
+			s.detectOpening(spot)
+		}
+	}
+	return nil
+}
+
+func (s *Server) detectOpening(f *DXSpotFrame) {
+	if s.openings == nil {
+		return
+	}
+	// the spots are counted when they are received, their DateTime may be in the past, e.g. when a log is replayed
+	opening, detected := s.openings.Add(f.Spot, f.Spotter, f.Frequency, s.now())
+	if detected {
+		s.send(s.bandOpeningFrame(opening))
+		s.send(s.gabFrame(s.source, "", opening.String()))
+	}
+}
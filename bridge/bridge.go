@@ -0,0 +1,195 @@
+// The package bridge chains wtSock servers: a [Bridge] connects as client to a remote wtSock server, e.g. a Win-Test box
+// or another godxmap instance, and rebroadcasts everything it receives to the clients of a local [godxmap.Server].
+// A [Relay] does the inverse and forwards the frames of a local server to one or more upstream servers.
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/ftl/godxmap"
+)
+
+// DefaultOrigin is the origin that is sent during the websocket handshake.
+const DefaultOrigin = "http://localhost/"
+
+const (
+	dialTimeout       = 10 * time.Second
+	writeTimeout      = 5 * time.Second
+	defaultBufferSize = 1024
+)
+
+// Bridge connects to a remote wtSock server and rebroadcasts all received frames. The header fields of the frames,
+// like the ID and the SourceAddr, are kept, so the clients can still tell where a frame came from.
+type Bridge struct {
+	url        string
+	server     *godxmap.Server
+	connection connection
+}
+
+// Option configures a [Bridge] or a [Relay] instance.
+type Option func(*connection)
+
+// connection holds the settings to connect to a remote wtSock server.
+type connection struct {
+	origin     string
+	header     http.Header
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	bufferSize int
+	logger     *slog.Logger
+}
+
+// WithOrigin sends the given origin during the websocket handshake. The default is [DefaultOrigin].
+func WithOrigin(origin string) Option {
+	return func(c *connection) {
+		c.origin = origin
+	}
+}
+
+// WithToken presents the given access token as bearer token during the websocket handshake,
+// e.g. for a godxmap server that uses [godxmap.WithTokenAuthentication].
+func WithToken(token string) Option {
+	return WithHeader("Authorization", "Bearer "+token)
+}
+
+// WithHeader sends the given header during the websocket handshake.
+func WithHeader(key string, value string) Option {
+	return func(c *connection) {
+		c.header.Add(key, value)
+	}
+}
+
+// WithReconnect reconnects automatically when the connection is lost. The delay between the attempts starts with
+// the given minimum and is doubled after every failed attempt, up to the given maximum.
+func WithReconnect(minBackoff time.Duration, maxBackoff time.Duration) Option {
+	return func(c *connection) {
+		c.minBackoff = minBackoff
+		c.maxBackoff = max(minBackoff, maxBackoff)
+	}
+}
+
+// WithBufferSize sets the number of frames that a [Relay] buffers for each upstream server while it is not connected.
+// When the buffer is full, the oldest frames are dropped. The default is 1024 frames.
+func WithBufferSize(size int) Option {
+	return func(c *connection) {
+		c.bufferSize = size
+	}
+}
+
+func newConnection(logger *slog.Logger, options []Option) connection {
+	result := connection{
+		logger:     logger,
+		origin:     DefaultOrigin,
+		header:     make(http.Header),
+		bufferSize: defaultBufferSize,
+	}
+	for _, option := range options {
+		option(&result)
+	}
+	return result
+}
+
+func (c connection) dial(ctx context.Context, url string) (*websocket.Conn, error) {
+	config, err := websocket.NewConfig(url, c.origin)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to %s: %v", url, err)
+	}
+	config.Header = c.header
+	config.Dialer = &net.Dialer{Timeout: dialTimeout}
+	conn, err := config.DialContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to %s: %v", url, err)
+	}
+	return conn, nil
+}
+
+// run calls the given session until the context is done. If reconnection is enabled, a failed session is
+// restarted with exponential backoff, otherwise its error is returned.
+func (c connection) run(ctx context.Context, session func(context.Context) (bool, error)) error {
+	if c.minBackoff <= 0 {
+		_, err := session(ctx)
+		return err
+	}
+
+	backoff := c.minBackoff
+	for {
+		connected, err := session(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if connected {
+			backoff = c.minBackoff
+		}
+		c.logger.Info("reconnecting", "backoff", backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, c.maxBackoff)
+	}
+}
+
+// NewBridge creates a new bridge from the wtSock server at the given URL, e.g. "ws://wintest.local:8080/", to the given server.
+// To actually connect to the remote server, use the Run method.
+func NewBridge(url string, server *godxmap.Server, options ...Option) *Bridge {
+	return &Bridge{
+		url:        url,
+		server:     server,
+		connection: newConnection(server.Logger(), options),
+	}
+}
+
+// Run connects to the remote server and rebroadcasts the received frames until the connection is closed or the given
+// context is done. If reconnection is enabled with [WithReconnect], Run only returns when the given context is done.
+func (b *Bridge) Run(ctx context.Context) error {
+	return b.connection.run(ctx, b.session)
+}
+
+func (b *Bridge) session(ctx context.Context) (bool, error) {
+	conn, err := b.connection.dial(ctx, b.url)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	for {
+		var data []byte
+		err := websocket.Message.Receive(conn, &data)
+		if err != nil {
+			if ctx.Err() != nil {
+				return true, nil
+			}
+			return true, fmt.Errorf("connection to %s lost: %v", b.url, err)
+		}
+		err = b.rebroadcast(data)
+		if err != nil {
+			b.server.Logger().Warn("cannot rebroadcast message", "url", b.url, "error", err)
+		}
+	}
+}
+
+func (b *Bridge) rebroadcast(data []byte) error {
+	frames, batch, err := godxmap.DecodeMessage(data)
+	if err != nil || len(frames) == 0 {
+		return err
+	}
+	if batch {
+		return b.server.SendBatch(frames)
+	}
+	return b.server.Send(frames[0])
+}
@@ -0,0 +1,172 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/ftl/godxmap"
+)
+
+// Relay forwards every locally generated frame of a server to one or more upstream wtSock servers, e.g. to aggregate
+// the feeds of a multi-station setup on one central map. Frames that the server received from elsewhere,
+// e.g. through a [Bridge], have a different SourceAddr and are not forwarded.
+//
+// The upstream servers must broadcast the frames they receive from their clients. A godxmap server does this
+// with [godxmap.WithRelayedFrames].
+type Relay struct {
+	server     *godxmap.Server
+	upstreams  []*upstream
+	connection connection
+}
+
+// upstream buffers the frames for a single upstream server.
+type upstream struct {
+	url    string
+	size   int
+	logger *slog.Logger
+	mutex  sync.Mutex
+	queue  []godxmap.Frame
+	wake   chan struct{}
+}
+
+// NewRelay creates a new relay from the given server to the wtSock servers at the given URLs.
+// To actually connect to the upstream servers, use the Run method.
+func NewRelay(server *godxmap.Server, urls []string, options ...Option) *Relay {
+	result := &Relay{
+		server:     server,
+		connection: newConnection(server.Logger(), options),
+	}
+	for _, url := range urls {
+		result.upstreams = append(result.upstreams, &upstream{
+			url:    url,
+			size:   max(1, result.connection.bufferSize),
+			logger: server.Logger(),
+			wake:   make(chan struct{}, 1),
+		})
+	}
+	return result
+}
+
+// Run forwards the frames to all upstream servers until the given context is done. The frames are buffered
+// while an upstream server is not connected. Use [WithReconnect] to reconnect to the upstream servers, otherwise
+// Run returns with the first connection that is lost.
+func (r *Relay) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	source := r.server.Source()
+	unsubscribe := r.server.Subscribe(godxmap.SinkFunc(func(f godxmap.Frame) {
+		if f.Header().SourceAddr != source {
+			return
+		}
+		for _, u := range r.upstreams {
+			u.push(f)
+		}
+	}))
+	defer unsubscribe()
+
+	errs := make(chan error, len(r.upstreams))
+	for _, u := range r.upstreams {
+		go func() {
+			errs <- r.connection.run(ctx, func(ctx context.Context) (bool, error) {
+				return r.session(ctx, u)
+			})
+		}()
+	}
+
+	var result error
+	for range r.upstreams {
+		err := <-errs
+		if err != nil && result == nil {
+			result = err
+			cancel()
+		}
+	}
+	return result
+}
+
+func (r *Relay) session(ctx context.Context, u *upstream) (bool, error) {
+	conn, err := r.connection.dial(ctx, u.url)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	closed := make(chan struct{})
+	go func() {
+		// read to process the control messages and to notice when the upstream server closes the connection
+		defer close(closed)
+		var data []byte
+		for websocket.Message.Receive(conn, &data) == nil {
+		}
+	}()
+
+	for {
+		f, ok := u.take()
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return true, nil
+			case <-closed:
+				return true, fmt.Errorf("connection to %s closed", u.url)
+			case <-u.wake:
+				continue
+			}
+		}
+
+		err := conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err == nil {
+			err = websocket.JSON.Send(conn, f)
+		}
+		if err != nil {
+			// the frame is sent again after a reconnect
+			u.requeue(f)
+			return true, fmt.Errorf("connection to %s lost: %v", u.url, err)
+		}
+	}
+}
+
+func (u *upstream) push(f godxmap.Frame) {
+	u.mutex.Lock()
+	if len(u.queue) >= u.size {
+		u.logger.Warn("relay buffer is full, dropping frame", "url", u.url, "frame_type", u.queue[0].FrameType(), "frame_id", u.queue[0].Header().ID)
+		u.queue = u.queue[1:]
+	}
+	u.queue = append(u.queue, f)
+	u.mutex.Unlock()
+
+	select {
+	case u.wake <- struct{}{}:
+	default:
+	}
+}
+
+// take removes the oldest frame from the queue and returns it. It reports false if the queue is empty.
+func (u *upstream) take() (godxmap.Frame, bool) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if len(u.queue) == 0 {
+		return nil, false
+	}
+	result := u.queue[0]
+	u.queue[0] = nil
+	u.queue = u.queue[1:]
+	return result, true
+}
+
+// requeue puts the given frame that could not be sent back to the front of the queue. If the queue is full in the meantime,
+// the frame is the oldest one and is dropped.
+func (u *upstream) requeue(f godxmap.Frame) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if len(u.queue) >= u.size {
+		u.logger.Warn("relay buffer is full, dropping frame", "url", u.url, "frame_type", f.FrameType(), "frame_id", f.Header().ID)
+		return
+	}
+	u.queue = append([]godxmap.Frame{f}, u.queue...)
+}
@@ -0,0 +1,157 @@
+// The package bus mirrors the frames of a [godxmap.Server] onto a message bus, so multiple map servers and other consumers,
+// e.g. across a contest station LAN, can share a single spot stream. The package natsbus provides a [Bus] implementation for NATS.
+package bus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ftl/godxmap"
+)
+
+const (
+	queueSize     = 256
+	rememberedIDs = 1024
+)
+
+// Bus is a publish/subscribe message bus.
+type Bus interface {
+	// Publish sends the given data to all subscribers of the subject.
+	Publish(subject string, data []byte) error
+	// Subscribe calls the given handler for every message that is published to the subject until unsubscribe is called.
+	Subscribe(subject string, handler func(data []byte)) (unsubscribe func(), err error)
+}
+
+// Mirror publishes all frames that are broadcast by a server to a subject of the bus, and broadcasts all frames
+// that are received from this subject. Frames that were received from the bus are not published again.
+type Mirror struct {
+	bus       Bus
+	subject   string
+	server    *godxmap.Server
+	publish   bool
+	subscribe bool
+
+	mutex    sync.Mutex
+	received map[string]bool
+	order    []string
+}
+
+// Option configures a [Mirror] instance.
+type Option func(*Mirror)
+
+// PublishOnly only publishes the frames of the server to the bus.
+func PublishOnly() Option {
+	return func(m *Mirror) {
+		m.publish = true
+		m.subscribe = false
+	}
+}
+
+// SubscribeOnly only broadcasts the frames that are received from the bus.
+func SubscribeOnly() Option {
+	return func(m *Mirror) {
+		m.publish = false
+		m.subscribe = true
+	}
+}
+
+// NewMirror creates a new mirror between the given server and the given subject of the bus.
+// To actually mirror the frames, use the Run method.
+func NewMirror(bus Bus, subject string, server *godxmap.Server, options ...Option) *Mirror {
+	result := &Mirror{
+		bus:       bus,
+		subject:   subject,
+		server:    server,
+		publish:   true,
+		subscribe: true,
+		received:  make(map[string]bool),
+	}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+// Run mirrors the frames until the given context is done.
+func (m *Mirror) Run(ctx context.Context) error {
+	if m.subscribe {
+		unsubscribe, err := m.bus.Subscribe(m.subject, m.handle)
+		if err != nil {
+			return fmt.Errorf("cannot subscribe to %s: %v", m.subject, err)
+		}
+		defer unsubscribe()
+	}
+	if !m.publish {
+		<-ctx.Done()
+		return nil
+	}
+
+	frames := make(chan godxmap.Frame, queueSize)
+	unsubscribe := m.server.Subscribe(godxmap.SinkFunc(func(f godxmap.Frame) {
+		select {
+		case frames <- f:
+		default:
+			m.server.Logger().Warn("cannot publish frame, queue is full", "subject", m.subject, "frame_type", f.FrameType(), "frame_id", f.Header().ID)
+		}
+	}))
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case f := <-frames:
+			if m.wasReceived(f.Header().ID) {
+				continue
+			}
+			data, err := godxmap.EncodeFrame(f)
+			if err != nil {
+				m.server.Logger().Warn("cannot encode frame", "frame_type", f.FrameType(), "frame_id", f.Header().ID, "error", err)
+				continue
+			}
+			err = m.bus.Publish(m.subject, data)
+			if err != nil {
+				m.server.Logger().Warn("cannot publish frame", "subject", m.subject, "frame_type", f.FrameType(), "frame_id", f.Header().ID, "error", err)
+			}
+		}
+	}
+}
+
+func (m *Mirror) handle(data []byte) {
+	f, err := godxmap.DecodeFrame(data)
+	if err != nil {
+		m.server.Logger().Warn("invalid frame from bus", "subject", m.subject, "error", err)
+		return
+	}
+	if f.Header().ID != "" {
+		m.remember(f.Header().ID)
+	}
+	err = m.server.Send(f)
+	if err != nil {
+		m.server.Logger().Warn("cannot broadcast frame from bus", "subject", m.subject, "frame_type", f.FrameType(), "error", err)
+	}
+}
+
+// remember the IDs of the received frames, so they are not published again
+func (m *Mirror) remember(id string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.received[id] {
+		return
+	}
+	m.received[id] = true
+	m.order = append(m.order, id)
+	if len(m.order) > rememberedIDs {
+		delete(m.received, m.order[0])
+		m.order = m.order[1:]
+	}
+}
+
+func (m *Mirror) wasReceived(id string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.received[id]
+}
@@ -0,0 +1,47 @@
+// The package natsbus provides a [bus.Bus] implementation for NATS.
+package natsbus
+
+import (
+	"github.com/nats-io/nats.go"
+
+	"github.com/ftl/godxmap/bus"
+)
+
+// Bus connects to a NATS server.
+type Bus struct {
+	conn *nats.Conn
+}
+
+var _ bus.Bus = (*Bus)(nil)
+
+// Connect connects to the NATS server at the given URL, e.g. nats://localhost:4222.
+func Connect(url string, options ...nats.Option) (*Bus, error) {
+	conn, err := nats.Connect(url, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &Bus{conn: conn}, nil
+}
+
+// Publish implements [bus.Bus].
+func (b *Bus) Publish(subject string, data []byte) error {
+	return b.conn.Publish(subject, data)
+}
+
+// Subscribe implements [bus.Bus].
+func (b *Bus) Subscribe(subject string, handler func(data []byte)) (func(), error) {
+	subscription, err := b.conn.Subscribe(subject, func(message *nats.Msg) {
+		handler(message.Data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		subscription.Unsubscribe()
+	}, nil
+}
+
+// Close closes the connection to the NATS server.
+func (b *Bus) Close() {
+	b.conn.Close()
+}
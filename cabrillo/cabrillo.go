@@ -0,0 +1,138 @@
+// The package cabrillo reads Cabrillo logs and replays the contained QSOs on the map of a [godxmap.Server],
+// e.g. for an after-action review of a contest.
+package cabrillo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+// ReadQSOs reads all QSO lines of the given Cabrillo log.
+func ReadQSOs(r io.Reader) ([]godxmap.QSO, error) {
+	var result []godxmap.QSO
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		tag, value, found := strings.Cut(line, ":")
+		if !found || !strings.EqualFold(tag, "QSO") {
+			continue
+		}
+		qso, err := ParseQSO(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNumber, err)
+		}
+		result = append(result, qso)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ParseQSO parses the value of a Cabrillo QSO line, e.g. "3799 PH 2000-11-26 0711 N6TW 59 03 JT1Z 59 23 0".
+// The sent and the received exchange must have the same number of fields. An optional transmitter ID is ignored.
+func ParseQSO(value string) (godxmap.QSO, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 6 {
+		return godxmap.QSO{}, fmt.Errorf("too few fields in QSO line")
+	}
+
+	frequencyKHz := parseFrequency(fields[0])
+	mode := parseMode(fields[1])
+	qsoTime, err := time.Parse("2006-01-02 1504", fields[2]+" "+fields[3])
+	if err != nil {
+		return godxmap.QSO{}, fmt.Errorf("invalid time: %v", err)
+	}
+
+	// fields[4] is the own callsign, followed by the sent exchange, the callsign of the other station and the received exchange
+	rest := fields[4:]
+	if len(rest)%2 == 1 {
+		rest = rest[:len(rest)-1]
+	}
+	half := len(rest) / 2
+	received := rest[half:]
+
+	return godxmap.QSO{
+		Call:         strings.ToUpper(received[0]),
+		FrequencyKHz: frequencyKHz,
+		Mode:         mode,
+		Exchange:     strings.Join(received[1:], " "),
+		Time:         qsoTime,
+	}, nil
+}
+
+// the VHF and higher bands are given as band designators instead of frequencies
+var bandFrequencies = map[string]float64{
+	"50":   50000,
+	"70":   70000,
+	"144":  144000,
+	"222":  222000,
+	"432":  432000,
+	"902":  902000,
+	"1.2G": 1240000,
+	"2.3G": 2300000,
+}
+
+func parseFrequency(value string) float64 {
+	if frequency, ok := bandFrequencies[strings.ToUpper(value)]; ok {
+		return frequency
+	}
+	result, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return result
+}
+
+func parseMode(value string) godxmap.Mode {
+	switch strings.ToUpper(value) {
+	case "CW":
+		return godxmap.ModeCW
+	case "PH":
+		return godxmap.ModeSSB
+	case "FM":
+		return godxmap.ModeFM
+	case "RY":
+		return godxmap.ModeRTTY
+	default:
+		return godxmap.NoMode
+	}
+}
+
+// Replay shows the given QSOs as logged calls on the map. With a speed of zero, all QSOs are shown instantly.
+// Otherwise the QSOs are shown in contest time, scaled by the given speed, e.g. 60 replays one hour of the contest in one minute.
+// Replay returns when all QSOs are shown or the given context is done.
+func Replay(ctx context.Context, server *godxmap.Server, qsos []godxmap.QSO, speed float64) error {
+	if len(qsos) == 0 {
+		return nil
+	}
+	start := time.Now()
+	contestStart := qsos[0].Time
+	for _, qso := range qsos {
+		if speed > 0 {
+			offset := time.Duration(float64(qso.Time.Sub(contestStart)) / speed)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Until(start.Add(offset))):
+			}
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err := server.ShowLoggedQSO(qso)
+		if err != nil {
+			return fmt.Errorf("cannot show QSO with %s: %v", qso.Call, err)
+		}
+	}
+	return nil
+}
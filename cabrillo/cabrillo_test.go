@@ -0,0 +1,118 @@
+package cabrillo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+func TestParseQSO(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		value    string
+		expected godxmap.QSO
+	}{
+		{
+			name:     "HF phone",
+			value:    " 3799 PH 2000-11-26 0711 N6TW          59  03     JT1Z          59  23     0",
+			expected: godxmap.QSO{Call: "JT1Z", FrequencyKHz: 3799, Mode: godxmap.ModeSSB, Exchange: "59 23", Time: time.Date(2000, 11, 26, 7, 11, 0, 0, time.UTC)},
+		},
+		{
+			name:     "CW without transmitter ID",
+			value:    "14025 CW 2025-10-25 1215 DL1ABC 599 14 w1aw 599 05",
+			expected: godxmap.QSO{Call: "W1AW", FrequencyKHz: 14025, Mode: godxmap.ModeCW, Exchange: "599 05", Time: time.Date(2025, 10, 25, 12, 15, 0, 0, time.UTC)},
+		},
+		{
+			name:     "VHF band designator",
+			value:    "144 FM 2025-06-14 1800 DL1ABC 59 001 JO62 DL2XYZ 59 002 JO61",
+			expected: godxmap.QSO{Call: "DL2XYZ", FrequencyKHz: 144000, Mode: godxmap.ModeFM, Exchange: "59 002 JO61", Time: time.Date(2025, 6, 14, 18, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "microwave band designator",
+			value:    "1.2g DG 2025-06-14 1805 DL1ABC JO62 DL2XYZ JO61",
+			expected: godxmap.QSO{Call: "DL2XYZ", FrequencyKHz: 1240000, Mode: godxmap.NoMode, Exchange: "JO61", Time: time.Date(2025, 6, 14, 18, 5, 0, 0, time.UTC)},
+		},
+		{
+			name:     "RTTY with invalid frequency",
+			value:    "twenty RY 2025-10-25 1215 DL1ABC 599 W1AW 599",
+			expected: godxmap.QSO{Call: "W1AW", FrequencyKHz: 0, Mode: godxmap.ModeRTTY, Exchange: "599", Time: time.Date(2025, 10, 25, 12, 15, 0, 0, time.UTC)},
+		},
+		{
+			name:     "only the callsigns",
+			value:    "7025 CW 2025-10-25 1215 DL1ABC W1AW",
+			expected: godxmap.QSO{Call: "W1AW", FrequencyKHz: 7025, Mode: godxmap.ModeCW, Time: time.Date(2025, 10, 25, 12, 15, 0, 0, time.UTC)},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseQSO(tc.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("expected\n%+v\ngot\n%+v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseInvalidQSO(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		value string
+	}{
+		{"empty", ""},
+		{"truncated", "14025 CW 2025-10-25 1215 DL1ABC"},
+		{"invalid date", "14025 CW 25-10-2025 1215 DL1ABC 599 W1AW 599"},
+		{"invalid time", "14025 CW 2025-10-25 2515 DL1ABC 599 W1AW 599"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseQSO(tc.value)
+			if err == nil {
+				t.Errorf("expected an error, got %+v", actual)
+			}
+		})
+	}
+}
+
+func TestReadQSOs(t *testing.T) {
+	log := `START-OF-LOG: 3.0
+CONTEST: CQ-WW-CW
+CALLSIGN: DL1ABC
+SOAPBOX: QSO: this is not a QSO
+QSO: 14025 CW 2025-10-25 1215 DL1ABC 599 14 W1AW 599 05 0
+qso:  7025 CW 2025-10-25 1216 DL1ABC 599 14 K1TTT 599 05 0
+
+X-QSO: 7026 CW 2025-10-25 1217 DL1ABC 599 14 N1MM 599 05 0
+END-OF-LOG:
+`
+	qsos, err := ReadQSOs(strings.NewReader(log))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls []string
+	for _, qso := range qsos {
+		calls = append(calls, qso.Call)
+	}
+	if strings.Join(calls, ",") != "W1AW,K1TTT" {
+		t.Errorf("unexpected QSOs: %v", calls)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		log      string
+		expected string
+	}{
+		{"invalid QSO", "START-OF-LOG: 3.0\nQSO: 14025 CW 2025-10-25 1215 DL1ABC 599 14 W1AW 599 05\nQSO: 14025 CW\n", "line 3: "},
+		{"oversized line", "START-OF-LOG: 3.0\nSOAPBOX: " + strings.Repeat("x", 100000) + "\n", "token too long"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ReadQSOs(strings.NewReader(tc.log))
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("expected error %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
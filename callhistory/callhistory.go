@@ -0,0 +1,109 @@
+// The package callhistory loads call history files as they are used by N1MM Logger+ and other contest loggers,
+// and provides their information as [godxmap.Enricher].
+//
+// A call history file is a CSV file. The first line that starts with !!Order!! defines the columns, e.g.:
+//
+//	!!Order!!,Call,Name,GridSquare
+//	# comment
+//	DL1ABC,Hans,JO31
+//
+// Of all columns, Call, Name and GridSquare (or Grid) are used.
+package callhistory
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ftl/godxmap"
+)
+
+type entry struct {
+	name    string
+	locator string
+}
+
+// History contains the entries of a call history file.
+type History struct {
+	entries map[string]entry
+}
+
+// LoadFile loads the call history file with the given name.
+func LoadFile(filename string) (*History, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Load(file)
+}
+
+// Load loads a call history file from the given reader.
+func Load(r io.Reader) (*History, error) {
+	result := &History{entries: make(map[string]entry)}
+	scanner := bufio.NewScanner(r)
+	columns := map[string]int{"CALL": 0, "NAME": 1, "GRIDSQUARE": 2}
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if strings.EqualFold(fields[0], "!!Order!!") {
+			columns = make(map[string]int)
+			for i, column := range fields[1:] {
+				columns[strings.ToUpper(strings.TrimSpace(column))] = i
+			}
+			if _, ok := columns["CALL"]; !ok {
+				return nil, fmt.Errorf("line %d: missing Call column", lineNumber)
+			}
+			continue
+		}
+
+		call := strings.ToUpper(column(fields, columns, "CALL"))
+		if call == "" {
+			continue
+		}
+		locator := column(fields, columns, "GRIDSQUARE")
+		if locator == "" {
+			locator = column(fields, columns, "GRID")
+		}
+		result.entries[call] = entry{
+			name:    column(fields, columns, "NAME"),
+			locator: strings.ToUpper(locator),
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func column(fields []string, columns map[string]int, name string) string {
+	i, ok := columns[name]
+	if !ok || i >= len(fields) {
+		return ""
+	}
+	return strings.TrimSpace(fields[i])
+}
+
+// Len returns the number of callsigns in the history.
+func (h *History) Len() int {
+	return len(h.entries)
+}
+
+// Enrich implements [godxmap.Enricher].
+func (h *History) Enrich(call string) (godxmap.CallInfo, error) {
+	entry, ok := h.entries[strings.ToUpper(call)]
+	if !ok {
+		return godxmap.CallInfo{}, godxmap.ErrUnknownCall
+	}
+	return godxmap.CallInfo{
+		Name:    entry.name,
+		Locator: entry.locator,
+	}, nil
+}
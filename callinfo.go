@@ -0,0 +1,44 @@
+package godxmap
+
+// LatLon is a geographic position in degrees.
+type LatLon struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// CallInfo contains information about a callsign that helps the map to place the callsign correctly.
+type CallInfo struct {
+	Name string
+	// DXCC is the number of the DXCC entity as used in ADIF.
+	DXCC      int
+	Entity    string
+	Continent string
+	// Locator is the Maidenhead locator of the station, if known.
+	Locator string
+	// Position is the location of the station, if known. If empty, the center of the locator is used.
+	Position *LatLon
+}
+
+func (i CallInfo) applyTo(f *PartialCallFrame) {
+	f.Name = i.Name
+	f.DXCC = i.DXCC
+	f.Entity = i.Entity
+	f.Continent = i.Continent
+	if locator, latitude, longitude, err := located(i.Locator); err == nil {
+		f.Locator, f.Latitude, f.Longitude = locator, latitude, longitude
+	}
+	if i.Position != nil {
+		latitude, longitude := i.Position.Latitude, i.Position.Longitude
+		f.Latitude = &latitude
+		f.Longitude = &longitude
+	}
+}
+
+// ShowPartialCallInfo shows the position of a (partially) entered callsign on the map, using the given information
+// that the host application already resolved. This way, the map does not have to guess the position of unusual
+// prefixes or portable calls.
+func (s *Server) ShowPartialCallInfo(call string, info CallInfo) error {
+	f := s.partialCallFrame(call)
+	info.applyTo(f)
+	return s.send(f)
+}
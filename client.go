@@ -0,0 +1,343 @@
+package godxmap
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// DefaultClientOrigin is the origin that a [Client] sends during the websocket handshake.
+const DefaultClientOrigin = "http://localhost/"
+
+const (
+	clientDialTimeout  = 10 * time.Second
+	clientWriteTimeout = 5 * time.Second
+	clientQueueSize    = 64
+)
+
+// FrameHandler is called for every frame that a [Client] receives.
+type FrameHandler func(f Frame)
+
+// ClientState is the state of the connection of a [Client].
+type ClientState int
+
+// The states of the connection of a [Client].
+const (
+	ClientConnecting ClientState = iota
+	ClientConnected
+	ClientDisconnected
+)
+
+func (s ClientState) String() string {
+	switch s {
+	case ClientConnecting:
+		return "connecting"
+	case ClientConnected:
+		return "connected"
+	case ClientDisconnected:
+		return "disconnected"
+	default:
+		return "unknown"
+	}
+}
+
+// StateHandler is called whenever the state of the connection of a [Client] changes. With [ClientDisconnected],
+// the error tells why the connection was lost or could not be established, it is nil if the client was stopped.
+type StateHandler func(state ClientState, err error)
+
+// Client connects to a wtSock server, e.g. a Win-Test gateway or another godxmap instance, and receives its frames.
+// The frames are decoded into the typed frame structs and delivered to the [FrameHandler], if one is set with
+// [WithFrameHandler], otherwise on the channel returned by Frames.
+type Client struct {
+	url     string
+	origin  string
+	header  http.Header
+	handler FrameHandler
+	logger  *slog.Logger
+	frames  chan Frame
+
+	onStateChange StateHandler
+	minBackoff    time.Duration
+	maxBackoff    time.Duration
+	resume        bool
+	lastID        string
+	subscription  *SubscribeFrame
+
+	connLock sync.Mutex
+	conn     *websocket.Conn
+}
+
+// ClientOption configures a [Client] instance.
+type ClientOption func(*Client)
+
+// WithClientOrigin sends the given origin during the websocket handshake. The default is [DefaultClientOrigin].
+func WithClientOrigin(origin string) ClientOption {
+	return func(c *Client) {
+		c.origin = origin
+	}
+}
+
+// WithClientToken presents the given access token as bearer token during the websocket handshake,
+// e.g. for a godxmap server that uses [WithTokenAuthentication].
+func WithClientToken(token string) ClientOption {
+	return WithClientHeader("Authorization", "Bearer "+token)
+}
+
+// WithClientHeader sends the given header during the websocket handshake.
+func WithClientHeader(key string, value string) ClientOption {
+	return func(c *Client) {
+		c.header.Add(key, value)
+	}
+}
+
+// WithFrameHandler delivers the received frames to the given handler instead of the channel returned by [Client.Frames].
+// The handler is called from the receiving goroutine, one frame after another.
+func WithFrameHandler(handler FrameHandler) ClientOption {
+	return func(c *Client) {
+		c.handler = handler
+	}
+}
+
+// WithClientLogger writes the log messages of the client to the given logger. By default, [slog.Default] is used.
+func WithClientLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithClientReconnect reconnects automatically when the connection is lost. The delay between the attempts starts with
+// the given minimum and is doubled after every failed attempt, up to the given maximum.
+func WithClientReconnect(minBackoff time.Duration, maxBackoff time.Duration) ClientOption {
+	return func(c *Client) {
+		c.minBackoff = minBackoff
+		c.maxBackoff = max(minBackoff, maxBackoff)
+	}
+}
+
+// WithStateHandler calls the given handler whenever the state of the connection changes, e.g. to show the state
+// in the user interface, or to send subscriptions again after a reconnect.
+func WithStateHandler(handler StateHandler) ClientOption {
+	return func(c *Client) {
+		c.onStateChange = handler
+	}
+}
+
+// WithClientResume resumes the session when the client reconnects: the server re-sends the frames that the client
+// missed while it was disconnected, if the server retains them, see [WithResume].
+func WithClientResume() ClientOption {
+	return func(c *Client) {
+		c.resume = true
+	}
+}
+
+// NewClient creates a new client for the wtSock server at the given URL, e.g. "ws://localhost:8080/".
+// To actually connect to the server, use the Run method.
+func NewClient(url string, options ...ClientOption) *Client {
+	result := &Client{
+		url:    url,
+		origin: DefaultClientOrigin,
+		header: make(http.Header),
+		logger: slog.Default(),
+		frames: make(chan Frame, clientQueueSize),
+	}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+// Frames returns the channel on which the received frames are delivered, if no [FrameHandler] is set.
+// The channel is closed when Run returns.
+func (c *Client) Frames() <-chan Frame {
+	return c.frames
+}
+
+// Run connects to the server and receives frames until the connection is closed or the given context is done.
+// If reconnection is enabled with [WithClientReconnect], Run only returns when the given context is done.
+func (c *Client) Run(ctx context.Context) error {
+	defer close(c.frames)
+	if c.minBackoff <= 0 {
+		_, err := c.session(ctx)
+		return err
+	}
+
+	backoff := c.minBackoff
+	for {
+		connected, err := c.session(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if connected {
+			backoff = c.minBackoff
+		}
+		c.logger.Info("reconnecting", "url", c.url, "backoff", backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, c.maxBackoff)
+	}
+}
+
+func (c *Client) changeState(state ClientState, err error) {
+	if c.onStateChange != nil {
+		c.onStateChange(state, err)
+	}
+}
+
+// sessionURL returns the URL to connect to. When the session is resumed, it contains the ID of the last received frame.
+func (c *Client) sessionURL() (string, error) {
+	if !c.resume || c.lastID == "" {
+		return c.url, nil
+	}
+	u, err := url.Parse(c.url)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set(ResumeParameter, c.lastID)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+func (c *Client) dial(ctx context.Context) (*websocket.Conn, error) {
+	sessionURL, err := c.sessionURL()
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to %s: %v", c.url, err)
+	}
+	config, err := websocket.NewConfig(sessionURL, c.origin)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to %s: %v", c.url, err)
+	}
+	config.Header = c.header
+	config.Dialer = &net.Dialer{Timeout: clientDialTimeout}
+	conn, err := config.DialContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to %s: %v", c.url, err)
+	}
+	return conn, nil
+}
+
+// session connects to the server and receives frames until the connection is lost. It reports if the connection was established.
+func (c *Client) session(ctx context.Context) (connected bool, err error) {
+	c.changeState(ClientConnecting, nil)
+	defer func() {
+		c.changeState(ClientDisconnected, err)
+	}()
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return false, err
+	}
+	c.setConn(conn)
+	defer c.setConn(nil)
+	defer conn.Close()
+	err = c.resubscribe()
+	if err != nil {
+		return true, fmt.Errorf("cannot subscribe at %s: %v", c.url, err)
+	}
+	c.changeState(ClientConnected, nil)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	for {
+		var data []byte
+		err := websocket.Message.Receive(conn, &data)
+		if err != nil {
+			if ctx.Err() != nil {
+				return true, nil
+			}
+			return true, fmt.Errorf("connection to %s lost: %v", c.url, err)
+		}
+		frames, _, err := DecodeMessage(data)
+		if err != nil {
+			c.logger.Warn("invalid message", "url", c.url, "error", err)
+			continue
+		}
+		for _, f := range frames {
+			if id := f.Header().ID; id != "" {
+				c.lastID = id
+			}
+			if !c.deliver(ctx, f) {
+				return true, nil
+			}
+		}
+	}
+}
+
+// deliver passes the given frame to the handler or the channel. It reports false if the context is done.
+func (c *Client) deliver(ctx context.Context, f Frame) bool {
+	if c.handler != nil {
+		c.handler(f)
+		return true
+	}
+	select {
+	case c.frames <- f:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (c *Client) setConn(conn *websocket.Conn) {
+	c.connLock.Lock()
+	defer c.connLock.Unlock()
+	c.conn = conn
+}
+
+// Send sends the given frame to the server, e.g. a gab message. The frame type is filled in, if it is empty,
+// the other empty header fields are not.
+func (c *Client) Send(f Frame) error {
+	c.connLock.Lock()
+	defer c.connLock.Unlock()
+
+	if c.conn == nil {
+		return fmt.Errorf("not connected to %s", c.url)
+	}
+	if f.Header().Frame == "" {
+		f.Header().Frame = f.FrameType()
+	}
+	err := c.conn.SetWriteDeadline(time.Now().Add(clientWriteTimeout))
+	if err != nil {
+		return err
+	}
+	return websocket.JSON.Send(c.conn, f)
+}
+
+// Subscribe selects the frames that the server sends to this client, see [SubscribeFrame]. The subscription is sent
+// immediately if the client is connected, and again whenever the client reconnects.
+func (c *Client) Subscribe(subscription SubscribeFrame) error {
+	c.connLock.Lock()
+	c.subscription = &subscription
+	connected := c.conn != nil
+	c.connLock.Unlock()
+
+	if !connected {
+		return nil
+	}
+	return c.Send(&subscription)
+}
+
+func (c *Client) resubscribe() error {
+	c.connLock.Lock()
+	subscription := c.subscription
+	c.connLock.Unlock()
+
+	if subscription == nil {
+		return nil
+	}
+	return c.Send(subscription)
+}
@@ -0,0 +1,64 @@
+package godxmap
+
+// ClientID identifies the connection of a map client. Every connection gets a new ID, also if a client reconnects
+// from the same address.
+type ClientID uint64
+
+// ClientFrameHandler is called for every frame that a map client sends to the server, e.g. a gab message.
+// Use the ID of the client to respond only to this client, see [Server.SendTo].
+// The frames of a client are handled one after another in the order they are received.
+// Subscriptions, see [SubscribeFrame], are handled by the server and not passed to the handler.
+type ClientFrameHandler func(f Frame, client ClientID, remoteAddr string)
+
+// WithClientFrameHandler handles the frames that the map clients send to the server with the given handler.
+// Without a handler, these frames are ignored.
+func WithClientFrameHandler(handler ClientFrameHandler) Option {
+	return func(s *Server) {
+		s.handleClientFrame = handler
+	}
+}
+
+// WithRelayedFrames broadcasts the frames that the clients send to the server, e.g. the frames that are relayed
+// by a bridge.Relay from other stations. Relayed frames are handled before the [ClientFrameHandler] is called.
+// As every client can inject frames into the feed, this should only be used with authentication, see [WithTokenAuthentication].
+func WithRelayedFrames() Option {
+	return func(s *Server) {
+		s.relayedFrames = true
+	}
+}
+
+// readClientFrames reads the frames of the given client until the connection is closed.
+// Reading also processes the websocket control messages, e.g. the close handshake.
+func (s *Server) readClientFrames(c dxmapConnection) {
+	defer c.Close()
+	for {
+		data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		f, err := DecodeFrame(data)
+		if err != nil {
+			c.logger.Warn("invalid client frame", "error", err)
+			continue
+		}
+		if subscription, ok := f.(*SubscribeFrame); ok {
+			filter, err := subscriptionFilter(subscription)
+			if err != nil {
+				c.logger.Warn("invalid subscription", "error", err)
+				continue
+			}
+			c.filter.set(filter)
+			c.logger.Debug("client subscribed", "frame_types", subscription.FrameTypes, "excluded", subscription.ExcludedFrameTypes, "bands", subscription.Bands, "modes", subscription.Modes)
+			continue
+		}
+		if s.relayedFrames {
+			err = s.Send(f)
+			if err != nil {
+				c.logger.Warn("cannot broadcast client frame", "frame_type", f.FrameType(), "frame_id", f.Header().ID, "error", err)
+			}
+		}
+		if s.handleClientFrame != nil {
+			s.handleClientFrame(f, c.id, c.conn.RemoteAddr())
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package godxmap
+
+import "time"
+
+// Clock returns the current time.
+type Clock func() time.Time
+
+// WithClock uses the given clock instead of [time.Now] for the DateTime field and the ULID of the frames, and for all time-based
+// decisions of the server, e.g. the expiry of spots and markers, the coalescing of spots, the cache of [NewCachingEnricher],
+// the retention of the history and the resume buffer, the audit events and the statistics. This way, tests and replays produce deterministic timestamps.
+// The network timeouts, the pace of the rate limiter and the pace of a [Replayer] always follow the wall clock.
+func WithClock(clock Clock) Option {
+	return func(s *Server) {
+		s.clock = clock
+	}
+}
+
+// Now returns the current time of the server's clock, see [WithClock].
+func (s *Server) Now() time.Time {
+	return s.now()
+}
+
+// now returns the current time of the server's clock.
+func (s *Server) now() time.Time {
+	return s.clock()
+}
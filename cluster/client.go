@@ -0,0 +1,332 @@
+// The package cluster provides a telnet client for DX clusters that feeds the received spots into a [godxmap.Server].
+package cluster
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+const (
+	defaultKeepalive = 5 * time.Minute
+	loginTimeout     = 10 * time.Second
+	dialTimeout      = 10 * time.Second
+	writeTimeout     = 10 * time.Second
+)
+
+// Profile describes the login dialog of a cluster node software.
+type Profile struct {
+	// LoginPrompts are the (lower case) markers of the prompt that asks for the callsign.
+	LoginPrompts []string
+	// PasswordPrompts are the (lower case) markers of the prompt that asks for the password.
+	PasswordPrompts []string
+	// Commands are sent after the login, before the commands given with [WithCommands].
+	Commands []string
+}
+
+// The login profiles of common cluster node software.
+var (
+	// GenericProfile works with most cluster nodes. It is the default.
+	GenericProfile = Profile{
+		LoginPrompts:    []string{"login", "call"},
+		PasswordPrompts: []string{"password"},
+	}
+	DXSpiderProfile = Profile{
+		LoginPrompts:    []string{"login"},
+		PasswordPrompts: []string{"password"},
+	}
+	ARClusterProfile = Profile{
+		LoginPrompts:    []string{"please enter your call"},
+		PasswordPrompts: []string{"please enter your password"},
+		Commands:        []string{"set/nobeep"},
+	}
+	CCClusterProfile = Profile{
+		LoginPrompts:    []string{"please enter your call", "login"},
+		PasswordPrompts: []string{"password"},
+		Commands:        []string{"set/nobeep"},
+	}
+)
+
+// SpotHandler is called for every spot received from the cluster.
+type SpotHandler func(Spot)
+
+// ToServer returns a [SpotHandler] that shows every received spot on the map of the given server.
+func ToServer(server *godxmap.Server) SpotHandler {
+	return func(spot Spot) {
+		err := server.ShowSpot(spot.DXSpot())
+		if err != nil {
+			server.Logger().Warn("cannot show cluster spot", "call", spot.DX, "error", err)
+		}
+	}
+}
+
+// Client connects to a DX cluster via telnet, logs in with the given callsign and forwards all received spots.
+type Client struct {
+	addr       string
+	call       string
+	password   string
+	profile    Profile
+	commands   []string
+	handler    SpotHandler
+	onLine     func(string)
+	keepalive  time.Duration
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	logger     *slog.Logger
+
+	writeLock sync.Mutex
+	conn      net.Conn
+
+	source godxmap.SourceRunner
+}
+
+// Option configures a [Client] instance.
+type Option func(*Client)
+
+// WithKeepalive sets the interval in which an empty line is sent to keep the connection alive. The default is five minutes.
+func WithKeepalive(interval time.Duration) Option {
+	return func(c *Client) {
+		c.keepalive = interval
+	}
+}
+
+// WithProfile sets the login profile of the cluster node. The default is [GenericProfile].
+func WithProfile(profile Profile) Option {
+	return func(c *Client) {
+		c.profile = profile
+	}
+}
+
+// WithPassword logs in with the given password, if the cluster asks for one.
+func WithPassword(password string) Option {
+	return func(c *Client) {
+		c.password = password
+	}
+}
+
+// WithCommands sends the given commands after the login, e.g. to set up the spot filters of the node:
+//
+//	cluster.WithCommands("set/dx filter call K")
+func WithCommands(commands ...string) Option {
+	return func(c *Client) {
+		c.commands = append(c.commands, commands...)
+	}
+}
+
+// WithReconnect reconnects automatically when the connection is lost. The delay between the attempts starts with
+// the given minimum and is doubled after every failed attempt, up to the given maximum.
+func WithReconnect(minBackoff time.Duration, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.minBackoff = minBackoff
+		c.maxBackoff = max(minBackoff, maxBackoff)
+	}
+}
+
+// WithLogger writes the log messages of the client to the given logger. By default, [slog.Default] is used.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// NewClient creates a new client for the cluster at the given address (host:port) that logs in with the given callsign.
+// To actually connect to the cluster, use the Run method.
+func NewClient(addr string, call string, handler SpotHandler, options ...Option) *Client {
+	result := &Client{
+		addr:      addr,
+		call:      call,
+		profile:   GenericProfile,
+		handler:   handler,
+		keepalive: defaultKeepalive,
+		logger:    slog.Default(),
+	}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+// Run connects to the cluster and processes the received lines until the connection is closed or the given context is done.
+// If reconnection is enabled with [WithReconnect], Run only returns when the given context is done.
+func (c *Client) Run(ctx context.Context) error {
+	if c.minBackoff <= 0 {
+		_, err := c.session(ctx)
+		return err
+	}
+
+	backoff := c.minBackoff
+	for {
+		loggedIn, err := c.session(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if loggedIn {
+			backoff = c.minBackoff
+		}
+		c.logger.Info("reconnecting to cluster", "addr", c.addr, "backoff", backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, c.maxBackoff)
+	}
+}
+
+// Start runs the client in the background. The received spots are emitted on the given channel instead of
+// being passed to the handler. Start, Stop and Status implement [godxmap.SpotSource] and [godxmap.SourceStatus].
+func (c *Client) Start(spots chan<- godxmap.Spot) error {
+	return c.source.Start(spots, c.Run)
+}
+
+// Stop stops the client that was started with Start.
+func (c *Client) Stop() {
+	c.source.Stop()
+}
+
+// Status reports if the client that was started with Start is still running.
+func (c *Client) Status() (bool, error) {
+	return c.source.Status()
+}
+
+// session connects to the cluster, logs in and processes the received lines until the connection is lost.
+// It reports if the login was successful.
+func (c *Client) session(ctx context.Context) (bool, error) {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return false, fmt.Errorf("cannot connect to cluster %s: %v", c.addr, err)
+	}
+	c.writeLock.Lock()
+	c.conn = conn
+	c.writeLock.Unlock()
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	lines := bufio.NewReader(newTelnetReader(conn))
+	err = c.login(lines)
+	if err != nil {
+		return false, fmt.Errorf("cannot log in to cluster %s: %v", c.addr, err)
+	}
+	go c.sendKeepalive(ctx)
+
+	return true, c.readSpots(ctx, lines)
+}
+
+func (c *Client) login(lines *bufio.Reader) error {
+	c.conn.SetReadDeadline(time.Now().Add(loginTimeout))
+	defer c.conn.SetReadDeadline(time.Time{})
+
+	// without a recognizable prompt, just try to send the callsign
+	waitForPrompt(lines, c.profile.LoginPrompts)
+	err := c.Send(c.call)
+	if err != nil {
+		return err
+	}
+
+	if c.password != "" {
+		if !waitForPrompt(lines, c.profile.PasswordPrompts) {
+			return fmt.Errorf("no password prompt")
+		}
+		err = c.Send(c.password)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, command := range append(c.profile.Commands, c.commands...) {
+		err = c.Send(command)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForPrompt reads until a prompt that contains one of the given markers and ends with a colon.
+func waitForPrompt(lines *bufio.Reader, markers []string) bool {
+	prompt := ""
+	for {
+		b, err := lines.ReadByte()
+		if err != nil {
+			return false
+		}
+		prompt += string(b)
+		lower := strings.ToLower(prompt)
+		if strings.HasSuffix(strings.TrimSpace(lower), ":") {
+			for _, marker := range markers {
+				if strings.Contains(lower, marker) {
+					return true
+				}
+			}
+		}
+		if b == '\n' {
+			prompt = ""
+		}
+	}
+}
+
+// Send sends the given command line to the cluster.
+func (c *Client) Send(command string) error {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	if c.conn == nil {
+		return fmt.Errorf("not connected to cluster %s", c.addr)
+	}
+	// a stuck connection must not block the callers, e.g. all users of a terminal
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err := io.WriteString(c.conn, command+"\r\n")
+	return err
+}
+
+func (c *Client) sendKeepalive(ctx context.Context) {
+	if c.keepalive <= 0 {
+		return
+	}
+	ticker := time.NewTicker(c.keepalive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := c.Send("")
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) readSpots(ctx context.Context, lines *bufio.Reader) error {
+	for {
+		line, err := lines.ReadString('\n')
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("connection to cluster %s lost: %v", c.addr, err)
+		}
+		spot, ok := ParseSpot(line, time.Now())
+		if ok && !c.source.Emit(spot.DXSpot()) && c.handler != nil {
+			c.handler(spot)
+		} else if !ok && c.onLine != nil {
+			c.onLine(strings.TrimRight(line, "\r\n"))
+		}
+	}
+}
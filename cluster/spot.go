@@ -0,0 +1,70 @@
+package cluster
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ftl/godxmap"
+)
+
+// Spot is a DX spot received from a DX cluster.
+type Spot struct {
+	Spotter      string
+	FrequencyKHz float64
+	DX           string
+	Comment      string
+	Time         time.Time
+	Locator      string
+}
+
+// DXSpot converts this spot into a [godxmap.Spot]. The locator is not converted, because clusters send the locator of the spotter.
+func (s Spot) DXSpot() godxmap.Spot {
+	return godxmap.Spot{
+		Time:         s.Time,
+		DX:           s.DX,
+		Spotter:      s.Spotter,
+		FrequencyKHz: s.FrequencyKHz,
+		Comments:     s.Comment,
+	}
+}
+
+var spotExpression = regexp.MustCompile(`(?i)^DX de ([A-Z0-9/\-#]+):?\s+(\d+(?:\.\d+)?)\s+([A-Z0-9/]+)\s+(.*?)\s*(\d{4})Z(?:\s+([A-R]{2}\d{2}(?:[A-X]{2})?))?\s*$`)
+
+// ParseSpot parses a "DX de" line as it is sent by DX clusters. The time of the spot is completed with the date
+// of the given reference time (UTC).
+func ParseSpot(line string, now time.Time) (Spot, bool) {
+	// some clusters ring the bell after each spot
+	matches := spotExpression.FindStringSubmatch(strings.TrimFunc(line, isSpaceOrControl))
+	if matches == nil {
+		return Spot{}, false
+	}
+
+	frequency, err := strconv.ParseFloat(matches[2], 64)
+	if err != nil {
+		return Spot{}, false
+	}
+	hours, _ := strconv.Atoi(matches[5][:2])
+	minutes, _ := strconv.Atoi(matches[5][2:])
+	now = now.UTC()
+	spotTime := time.Date(now.Year(), now.Month(), now.Day(), hours, minutes, 0, 0, time.UTC)
+	if spotTime.After(now.Add(time.Hour)) {
+		// the spot was sent shortly before midnight
+		spotTime = spotTime.AddDate(0, 0, -1)
+	}
+
+	return Spot{
+		Spotter:      strings.ToUpper(strings.TrimRight(matches[1], "-#")),
+		FrequencyKHz: frequency,
+		DX:           strings.ToUpper(matches[3]),
+		Comment:      matches[4],
+		Time:         spotTime,
+		Locator:      strings.ToUpper(matches[6]),
+	}, true
+}
+
+func isSpaceOrControl(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsControl(r)
+}
@@ -0,0 +1,78 @@
+package cluster
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSpot(t *testing.T) {
+	now := time.Date(2025, 10, 25, 12, 30, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name     string
+		line     string
+		now      time.Time
+		expected Spot
+		valid    bool
+	}{
+		{
+			name:     "cluster spot",
+			line:     "DX de W3LPL:     14025.0  DL1ABC       CW 599                         1215Z",
+			now:      now,
+			expected: Spot{Spotter: "W3LPL", FrequencyKHz: 14025, DX: "DL1ABC", Comment: "CW 599", Time: time.Date(2025, 10, 25, 12, 15, 0, 0, time.UTC)},
+			valid:    true,
+		},
+		{
+			name:     "skimmer spot",
+			line:     "DX de EA5WU-#:    14004.9  OH0R         CW 19 dB 25 WPM CQ             1229Z",
+			now:      now,
+			expected: Spot{Spotter: "EA5WU", FrequencyKHz: 14004.9, DX: "OH0R", Comment: "CW 19 dB 25 WPM CQ", Time: time.Date(2025, 10, 25, 12, 29, 0, 0, time.UTC)},
+			valid:    true,
+		},
+		{
+			name:     "spot with locator",
+			line:     "DX de dl2xyz:    50313.0  k1abc/p      FT8 -12 dB                     1230Z jo62",
+			now:      now,
+			expected: Spot{Spotter: "DL2XYZ", FrequencyKHz: 50313, DX: "K1ABC/P", Comment: "FT8 -12 dB", Time: time.Date(2025, 10, 25, 12, 30, 0, 0, time.UTC), Locator: "JO62"},
+			valid:    true,
+		},
+		{
+			name:     "spot without comment and with trailing bell",
+			line:     "DX de N1MM:  7025  DL1ABC 1200Z\a\r\n",
+			now:      now,
+			expected: Spot{Spotter: "N1MM", FrequencyKHz: 7025, DX: "DL1ABC", Time: time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC)},
+			valid:    true,
+		},
+		{
+			name:     "spot from before midnight",
+			line:     "DX de W3LPL:     14025.0  DL1ABC       CW                             2359Z",
+			now:      time.Date(2025, 10, 26, 0, 5, 0, 0, time.UTC),
+			expected: Spot{Spotter: "W3LPL", FrequencyKHz: 14025, DX: "DL1ABC", Comment: "CW", Time: time.Date(2025, 10, 25, 23, 59, 0, 0, time.UTC)},
+			valid:    true,
+		},
+		{
+			name:     "reference time in another zone",
+			line:     "DX de W3LPL:     14025.0  DL1ABC       CW                             2300Z",
+			now:      time.Date(2025, 10, 26, 1, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+			expected: Spot{Spotter: "W3LPL", FrequencyKHz: 14025, DX: "DL1ABC", Comment: "CW", Time: time.Date(2025, 10, 25, 23, 0, 0, 0, time.UTC)},
+			valid:    true,
+		},
+		{name: "empty line", line: "", now: now},
+		{name: "announcement", line: "To ALL de W3LPL: contest starts in 10 minutes", now: now},
+		{name: "prompt", line: "DL1ABC de W3LPL 25-Oct-2025 1230Z dxspider >", now: now},
+		{name: "truncated", line: "DX de W3LPL:     14025.0  DL1ABC", now: now},
+		{name: "missing frequency", line: "DX de W3LPL:     DL1ABC       CW                             1215Z", now: now},
+		{name: "invalid frequency", line: "DX de W3LPL:     14.025.0  DL1ABC       CW                     1215Z", now: now},
+		{name: "oversized", line: "DX de W3LPL:     14025.0  DL1ABC " + strings.Repeat("x", 100000), now: now},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, ok := ParseSpot(tc.line, tc.now)
+			if ok != tc.valid {
+				t.Fatalf("expected valid %t, got %t: %+v", tc.valid, ok, actual)
+			}
+			if actual != tc.expected {
+				t.Errorf("expected\n%+v\ngot\n%+v", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package cluster
+
+import "io"
+
+const (
+	telnetIAC  = 255
+	telnetSB   = 250
+	telnetSE   = 240
+	telnetWILL = 251
+	telnetDONT = 254
+)
+
+// telnetReader removes telnet command sequences from the data stream. All option negotiations are ignored.
+type telnetReader struct {
+	r     io.Reader
+	state int
+}
+
+const (
+	telnetData = iota
+	telnetCommand
+	telnetOption
+	telnetSubnegotiation
+	telnetSubnegotiationIAC
+)
+
+func newTelnetReader(r io.Reader) *telnetReader {
+	return &telnetReader{r: r}
+}
+
+func (t *telnetReader) Read(p []byte) (int, error) {
+	for {
+		n, err := t.r.Read(p)
+		if n == 0 {
+			return 0, err
+		}
+		result := 0
+		for _, b := range p[:n] {
+			switch t.state {
+			case telnetData:
+				if b == telnetIAC {
+					t.state = telnetCommand
+					continue
+				}
+				p[result] = b
+				result++
+			case telnetCommand:
+				switch {
+				case b == telnetIAC:
+					// escaped 255
+					p[result] = b
+					result++
+					t.state = telnetData
+				case b == telnetSB:
+					t.state = telnetSubnegotiation
+				case b >= telnetWILL && b <= telnetDONT:
+					t.state = telnetOption
+				default:
+					t.state = telnetData
+				}
+			case telnetOption:
+				t.state = telnetData
+			case telnetSubnegotiation:
+				if b == telnetIAC {
+					t.state = telnetSubnegotiationIAC
+				}
+			case telnetSubnegotiationIAC:
+				if b == telnetSE {
+					t.state = telnetData
+				} else {
+					t.state = telnetSubnegotiation
+				}
+			}
+		}
+		if result > 0 || err != nil {
+			return result, err
+		}
+	}
+}
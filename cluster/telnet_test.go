@@ -0,0 +1,37 @@
+package cluster
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestTelnetReader(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		input    []byte
+		expected string
+	}{
+		{"plain text", []byte("login: "), "login: "},
+		{"option negotiation", []byte{'a', telnetIAC, telnetWILL, 1, 'b', telnetIAC, telnetDONT, 34, 'c'}, "abc"},
+		{"escaped IAC", []byte{'a', telnetIAC, telnetIAC, 'b'}, "a\xffb"},
+		{"subnegotiation", []byte{'a', telnetIAC, telnetSB, 24, 1, telnetIAC, telnetSE, 'b'}, "ab"},
+		{"IAC within subnegotiation", []byte{telnetIAC, telnetSB, 24, telnetIAC, telnetIAC, 0, telnetIAC, telnetSE, 'a'}, "a"},
+		{"other command", []byte{'a', telnetIAC, 241, 'b'}, "ab"},
+		{"only commands", []byte{telnetIAC, telnetWILL, 1, telnetIAC, telnetDONT, 1}, ""},
+		{"truncated command", []byte{'a', telnetIAC}, "a"},
+		{"truncated subnegotiation", []byte{'a', telnetIAC, telnetSB, 24, 1}, "a"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// read byte by byte to split the command sequences
+			actual, err := io.ReadAll(newTelnetReader(iotest.OneByteReader(bytes.NewReader(tc.input))))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(actual) != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package cluster
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+// responseTimeout is the time after which a command without response prompt is given up, so the following lines
+// are not taken as its response.
+const responseTimeout = 30 * time.Second
+
+// commandVerbs are the verbs of the cluster commands with qualifier (e.g. "sh/dx") that are passed through from the map clients.
+var commandVerbs = []string{"SH", "SHOW", "SET", "UNSET", "ACCEPT", "REJECT", "CLEAR", "ANN", "ANNOUNCE", "TALK", "READ", "DIR"}
+
+// promptExpression matches the prompt of the cluster node that ends the response to a command,
+// e.g. "DL1ABC de DB0ABC 15-Oct-2026 1200Z dxspider >".
+var promptExpression = regexp.MustCompile(`^\S+ de \S+ .*>\s*`)
+
+// broadcastExpression matches the lines that the cluster node sends to all users, e.g. announcements or WWV,
+// they are never a response to a command.
+var broadcastExpression = regexp.MustCompile(`(?i)^(to \S+ de |wwv de |wcy de )`)
+
+// Terminal turns the map clients into a thin cluster terminal: gab messages that start with a cluster command,
+// e.g. "sh/dx 20", are sent to the cluster, and the response is shown as gab messages to the client that sent the command.
+//
+// The cluster handles the commands one after another. The lines up to the next prompt of the cluster node are the
+// response to the oldest pending command.
+type Terminal struct {
+	client *Client
+	server *godxmap.Server
+
+	mutex   sync.Mutex
+	pending []pendingCommand
+}
+
+type pendingCommand struct {
+	client     godxmap.ClientID
+	remoteAddr string
+	command    string
+	sent       time.Time
+}
+
+// NewTerminal attaches a new terminal to the given cluster client. It must be called before the client is started.
+// Register the terminal's HandleClientFrame method with [godxmap.WithClientFrameHandler].
+func NewTerminal(client *Client, server *godxmap.Server) *Terminal {
+	result := &Terminal{
+		client: client,
+		server: server,
+	}
+	client.onLine = result.handleLine
+	return result
+}
+
+// HandleClientFrame implements [godxmap.ClientFrameHandler].
+func (t *Terminal) HandleClientFrame(f godxmap.Frame, client godxmap.ClientID, remoteAddr string) {
+	gab, ok := f.(*godxmap.GabFrame)
+	if !ok || !isCommand(gab.Message) {
+		return
+	}
+	command := strings.TrimSpace(gab.Message)
+
+	// the lock keeps the order of the pending commands and the order in which they are sent the same
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	err := t.client.Send(command)
+	if err != nil {
+		t.server.Logger().Warn("cannot send command to cluster", "remote_addr", remoteAddr, "error", err)
+		return
+	}
+	t.pending = append(t.pending, pendingCommand{client: client, remoteAddr: remoteAddr, command: command, sent: t.server.Now()})
+}
+
+func (t *Terminal) handleLine(line string) {
+	t.mutex.Lock()
+	command, ok := t.respondingCommand(t.server.Now())
+	if ok && promptExpression.MatchString(line) {
+		// the prompt ends the response, anything after the prompt is already the next line
+		line = promptExpression.ReplaceAllString(line, "")
+		t.pending = t.pending[1:]
+		command, ok = t.respondingCommand(t.server.Now())
+	}
+	t.mutex.Unlock()
+
+	if !ok || strings.TrimSpace(line) == "" || broadcastExpression.MatchString(line) {
+		return
+	}
+
+	err := t.server.SendTo(command.client, &godxmap.GabFrame{From: t.client.addr, Message: line})
+	if err != nil {
+		t.server.Logger().Warn("cannot show cluster response", "remote_addr", command.remoteAddr, "command", command.command, "error", err)
+	}
+}
+
+// respondingCommand returns the oldest pending command. The commands that did not get a prompt in time are given up.
+// t.mutex must be held.
+func (t *Terminal) respondingCommand(now time.Time) (pendingCommand, bool) {
+	for len(t.pending) > 0 && now.Sub(t.pending[0].sent) > responseTimeout {
+		t.pending = t.pending[1:]
+	}
+	if len(t.pending) == 0 {
+		return pendingCommand{}, false
+	}
+	return t.pending[0], true
+}
+
+// isCommand reports if the given message starts with a cluster command, e.g. "sh/dx", "set/dx filter" or "dx 14025 DL1ABC".
+// Other gab messages are regular chat.
+func isCommand(message string) bool {
+	words := strings.Fields(strings.ToUpper(message))
+	if len(words) == 0 {
+		return false
+	}
+	if words[0] == "DX" {
+		// a spot needs a frequency and a callsign
+		_, err := strconv.ParseFloat(words[min(1, len(words)-1)], 64)
+		return len(words) >= 3 && err == nil
+	}
+	verb, _, qualified := strings.Cut(words[0], "/")
+	if !qualified {
+		return false
+	}
+	for _, commandVerb := range commandVerbs {
+		if verb == commandVerb {
+			return true
+		}
+	}
+	return false
+}
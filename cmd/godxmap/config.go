@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// config contains the settings of the serve command. It is read from the JSON configuration file, the flags override it.
+type config struct {
+	Addr    string
+	Source  string
+	Call    string
+	TTL     duration
+	Token   string
+	Relay   bool
+	Viewer  bool
+	Debug   bool
+	Verbose bool
+
+	Cluster struct {
+		Addr     string
+		Password string
+		Commands list
+	}
+	RBN struct {
+		Addr   string
+		Bands  list
+		Modes  list
+		MinSNR int
+	}
+	WSJTX struct {
+		Addr string
+	}
+	ADIF struct {
+		File string
+	}
+}
+
+func defaultConfig() config {
+	return config{
+		Addr: ":12345",
+	}
+}
+
+func (c *config) register(flags *flag.FlagSet) {
+	flags.StringVar(&c.Addr, "addr", c.Addr, "the listening address of the websocket")
+	flags.StringVar(&c.Source, "source", c.Source, "the identity of this server that is sent in all frames, the listening address by default")
+	flags.StringVar(&c.Call, "call", c.Call, "your callsign, used to log in to the DX cluster and the RBN")
+	flags.Var(&c.TTL, "ttl", "the time-to-live hint of the spots and calls, e.g. 30m")
+	flags.StringVar(&c.Token, "token", c.Token, "the access token that the clients need to connect")
+	flags.BoolVar(&c.Relay, "relay", c.Relay, "broadcast the frames that the clients send, e.g. with godxmap send")
+	flags.BoolVar(&c.Viewer, "viewer", c.Viewer, "serve a simple map page on /viewer/")
+	flags.BoolVar(&c.Debug, "debug", c.Debug, "serve the debug page on /debug/dxmap")
+	flags.BoolVar(&c.Verbose, "verbose", c.Verbose, "log debug messages")
+
+	flags.StringVar(&c.Cluster.Addr, "cluster", c.Cluster.Addr, "the address (host:port) of a DX cluster node")
+	flags.StringVar(&c.Cluster.Password, "cluster-password", c.Cluster.Password, "the password for the DX cluster node")
+	flags.Var(&c.Cluster.Commands, "cluster-commands", "comma separated commands that are sent to the DX cluster node after the login")
+
+	flags.StringVar(&c.RBN.Addr, "rbn", c.RBN.Addr, `the address (host:port) of the RBN telnet service, or "cw" or "ft8"`)
+	flags.Var(&c.RBN.Bands, "rbn-bands", "comma separated bands of the RBN spots, e.g. 20m,40m")
+	flags.Var(&c.RBN.Modes, "rbn-modes", "comma separated modes of the RBN spots, e.g. CW,RTTY")
+	flags.IntVar(&c.RBN.MinSNR, "rbn-min-snr", c.RBN.MinSNR, "the minimum SNR in dB of the RBN spots")
+
+	flags.StringVar(&c.WSJTX.Addr, "wsjtx", c.WSJTX.Addr, "the UDP address to receive the messages of WSJT-X, e.g. 127.0.0.1:2237")
+
+	flags.StringVar(&c.ADIF.File, "adif", c.ADIF.File, "an ADIF file to watch for logged QSOs")
+}
+
+func (c *config) load(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("cannot read configuration: %v", err)
+	}
+	err = json.Unmarshal(data, c)
+	if err != nil {
+		return fmt.Errorf("cannot read configuration %s: %v", filename, err)
+	}
+	return nil
+}
+
+// parseConfig parses the given command line arguments. If a configuration file is given, it is loaded first and
+// the flags are applied on top of it.
+func parseConfig(flags *flag.FlagSet, args []string) (config, error) {
+	result := defaultConfig()
+	var filename string
+	flags.StringVar(&filename, "config", "", "a JSON configuration file, the flags take precedence")
+	result.register(flags)
+
+	err := flags.Parse(args)
+	if err != nil {
+		return config{}, err
+	}
+	if filename == "" {
+		return result, nil
+	}
+	err = result.load(filename)
+	if err != nil {
+		return config{}, err
+	}
+	// apply the flags again, so they override the configuration file
+	err = flags.Parse(args)
+	if err != nil {
+		return config{}, err
+	}
+	return result, nil
+}
+
+// duration is a [time.Duration] that is written as string, e.g. "30m", on the command line and in the configuration file.
+type duration time.Duration
+
+func (d *duration) String() string {
+	return time.Duration(*d).String()
+}
+
+func (d *duration) Set(s string) error {
+	value, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = duration(value)
+	return nil
+}
+
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+	return d.Set(s)
+}
+
+// list is a list of strings that is written comma separated on the command line and as JSON array in the configuration file.
+type list []string
+
+func (l *list) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *list) Set(s string) error {
+	*l = nil
+	for _, value := range strings.Split(s, ",") {
+		value = strings.TrimSpace(value)
+		if value != "" {
+			*l = append(*l, value)
+		}
+	}
+	return nil
+}
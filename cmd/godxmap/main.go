@@ -0,0 +1,79 @@
+// The command godxmap runs a standalone godxmap server and feeds it with spots and QSOs from a DX cluster, the Reverse Beacon
+// Network, WSJT-X and an ADIF log file, so the station data can be shown on HamDXMap without writing any code.
+//
+// Install it with:
+//
+//	go install github.com/ftl/godxmap/cmd/godxmap@latest
+//
+// Usage:
+//
+//	godxmap [serve] [flags]
+//	godxmap send [flags] <frame> <arguments>
+//	godxmap replay [flags] <file>
+//
+// The command send sends a single spot, call or gab to a running server, e.g. from a shell script:
+//
+//	godxmap send -url ws://localhost:12345/ spot DL1ABC 14025 CW 23 dB
+//	godxmap send gab "QRV on 20m"
+//
+// The server only broadcasts the frames of its clients with the flag -relay.
+//
+// The command replay serves a map and plays an ADIF or Cabrillo log back, e.g. for the review of a contest:
+//
+//	godxmap replay -speed 120 contest.log
+//
+// Run godxmap -h, godxmap send -h or godxmap replay -h to see all flags.
+//
+// The settings of the server can also be read from a JSON configuration file with the flag -config,
+// the flags given on the command line take precedence:
+//
+//	{
+//		"Addr": ":12345",
+//		"Call": "DL1ABC",
+//		"Cluster": {"Addr": "dxc.example.org:7300"},
+//		"RBN": {"Addr": "cw", "Bands": ["20m", "40m"], "MinSNR": 10},
+//		"WSJTX": {"Addr": "127.0.0.1:2237"},
+//		"ADIF": {"File": "/home/dl1abc/log.adi"}
+//	}
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := run(ctx, os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "godxmap: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the subcommand that is given as first argument. Without a subcommand, the server is started.
+func run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return serve(ctx, args)
+	}
+	switch args[0] {
+	case "serve":
+		return serve(ctx, args[1:])
+	case "send":
+		return send(ctx, args[1:])
+	case "replay":
+		return replay(ctx, args[1:])
+	default:
+		return serve(ctx, args)
+	}
+}
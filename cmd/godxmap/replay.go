@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ftl/godxmap"
+	"github.com/ftl/godxmap/adif"
+	"github.com/ftl/godxmap/cabrillo"
+)
+
+const replayUsage = `Usage: godxmap replay [flags] <file>
+
+Serves a map and plays the QSOs of the given ADIF or Cabrillo log back as logged calls.
+The replay starts when the first map is connected, the map keeps showing the QSOs afterwards
+until godxmap is stopped.
+
+Flags:
+`
+
+const waitInterval = 500 * time.Millisecond
+
+// replay serves a map and plays the QSOs of a log file back until the given context is done.
+func replay(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("godxmap replay", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), replayUsage)
+		flags.PrintDefaults()
+	}
+	addr := flags.String("addr", ":12345", "the listening address of the websocket")
+	speed := flags.Float64("speed", 60, "the speed of the playback, 1 is the original speed, 60 plays one hour in one minute, 0 shows all QSOs at once")
+	format := flags.String("format", "", `the format of the log, "adif" or "cabrillo", by default it is detected from the file`)
+	originalTime := flags.Bool("original-time", false, "send the QSOs with their original time instead of the time of the playback")
+	wait := flags.Bool("wait", true, "wait until the first map is connected before the playback starts")
+	viewer := flags.Bool("viewer", false, "serve a simple map page on /viewer/")
+	verbose := flags.Bool("verbose", false, "log debug messages")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("missing log file")
+	}
+	filename := flags.Arg(0)
+
+	qsos, err := readLog(filename, *format)
+	if err != nil {
+		return err
+	}
+
+	level := slog.LevelInfo
+	if *verbose {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	serverOptions := []godxmap.Option{godxmap.WithLogger(logger), godxmap.WithMapState()}
+	if *viewer {
+		serverOptions = append(serverOptions, godxmap.WithViewer())
+	}
+	server := godxmap.NewServer(*addr, serverOptions...)
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	var frames []godxmap.Frame
+	for _, qso := range qsos {
+		frames = append(frames, server.QSOFrames(qso)...)
+	}
+	options := []godxmap.ReplayOption{godxmap.WithReplaySpeed(*speed)}
+	if *originalTime {
+		options = append(options, godxmap.WithOriginalTime())
+	}
+	replayer := godxmap.NewReplayer(server, frames, options...)
+
+	go func() {
+		if *wait && !awaitConnection(ctx, server, logger) {
+			return
+		}
+		logger.Info("replaying the log", "file", filename, "qsos", len(qsos), "speed", *speed)
+		err := replayer.Run(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Error("cannot replay the log", "file", filename, "error", err)
+			return
+		}
+		if ctx.Err() == nil {
+			logger.Info("replay complete")
+		}
+	}()
+
+	logger.Info("serving the map", "addr", *addr)
+	err = server.Serve()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// readLog reads the QSOs of the given ADIF or Cabrillo file. If the format is empty, it is detected from the file.
+func readLog(filename string, format string) ([]godxmap.QSO, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read log: %v", err)
+	}
+	if format == "" {
+		format = logFormat(filename, data)
+	}
+
+	var result []godxmap.QSO
+	switch strings.ToLower(format) {
+	case "adif":
+		for _, record := range adif.NewParser().Feed(data) {
+			qso := adif.QSO(record)
+			if qso.Call != "" {
+				result = append(result, qso)
+			}
+		}
+	case "cabrillo":
+		result, err = cabrillo.ReadQSOs(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("cannot read Cabrillo log %s: %v", filename, err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no QSOs found in %s", filename)
+	}
+	return result, nil
+}
+
+// logFormat detects the format of the given log file by its name or its content.
+func logFormat(filename string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".adi", ".adif":
+		return "adif"
+	case ".cbr", ".cabrillo":
+		return "cabrillo"
+	}
+	if bytes.Contains(bytes.ToUpper(data), []byte("START-OF-LOG:")) {
+		return "cabrillo"
+	}
+	return "adif"
+}
+
+// awaitConnection waits until a map is connected to the given server. It reports false if the given context is done before.
+func awaitConnection(ctx context.Context, server *godxmap.Server, logger *slog.Logger) bool {
+	logger.Info("waiting for a map to connect")
+	ticker := time.NewTicker(waitInterval)
+	defer ticker.Stop()
+	for len(server.Connections()) == 0 {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+	return true
+}
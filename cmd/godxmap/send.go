@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+const sendUsage = `Usage: godxmap send [flags] <frame> <arguments>
+
+Sends a single frame to a running godxmap or wtSock server. A godxmap server only broadcasts
+the frames of its clients with the flag -relay. The frames are:
+
+  spot <call> <frequency kHz> [comments]   a DX spot, use -spotter to set the spotter
+  call <call> [frequency kHz]              a logged call
+  partial <call>                           a partially entered call
+  gab <message>                            a gab message, use -from and -to to set the sender and the receiver
+
+Flags:
+`
+
+// send sends a single frame, given on the command line, to the server.
+func send(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("godxmap send", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), sendUsage)
+		flags.PrintDefaults()
+	}
+	url := flags.String("url", "ws://localhost:12345/", "the websocket URL of the server")
+	token := flags.String("token", "", "the access token of the server")
+	spotter := flags.String("spotter", "", "the spotter of a DX spot")
+	from := flags.String("from", "godxmap", "the sender of a gab message")
+	to := flags.String("to", "", "the receiver of a gab message")
+	timeout := flags.Duration("timeout", 10*time.Second, "the time to connect and send the frame")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	f, err := commandLineFrame(flags.Args(), *spotter, *from, *to)
+	if err != nil {
+		flags.Usage()
+		return err
+	}
+	f.Header().DateTime = time.Now().UnixMilli()
+
+	options := []godxmap.ClientOption{
+		godxmap.WithClientLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		godxmap.WithFrameHandler(func(godxmap.Frame) {}),
+	}
+	if *token != "" {
+		options = append(options, godxmap.WithClientToken(*token))
+	}
+	return sendFrame(ctx, *url, f, *timeout, options...)
+}
+
+// commandLineFrame creates the frame that is described by the given arguments.
+func commandLineFrame(args []string, spotter string, from string, to string) (godxmap.Frame, error) {
+	if len(args) == 0 {
+		return nil, errors.New("missing frame")
+	}
+	kind, args := args[0], args[1:]
+	switch kind {
+	case "spot":
+		if len(args) < 2 {
+			return nil, errors.New("a spot needs a call and a frequency")
+		}
+		frequency, err := parseFrequency(args[1])
+		if err != nil {
+			return nil, err
+		}
+		return &godxmap.DXSpotFrame{
+			Spot:      strings.ToUpper(args[0]),
+			Spotter:   strings.ToUpper(spotter),
+			Frequency: frequency,
+			Comments:  strings.Join(args[2:], " "),
+		}, nil
+	case "call":
+		if len(args) < 1 || len(args) > 2 {
+			return nil, errors.New("a logged call needs a call and optionally a frequency")
+		}
+		var frequency float64
+		if len(args) == 2 {
+			var err error
+			frequency, err = parseFrequency(args[1])
+			if err != nil {
+				return nil, err
+			}
+		}
+		return &godxmap.LoggedCallFrame{
+			Call:      strings.ToUpper(args[0]),
+			Frequency: frequency,
+		}, nil
+	case "partial":
+		if len(args) != 1 {
+			return nil, errors.New("a partial call needs exactly one call")
+		}
+		return &godxmap.PartialCallFrame{
+			Call: strings.ToUpper(args[0]),
+		}, nil
+	case "gab":
+		if len(args) == 0 {
+			return nil, errors.New("a gab needs a message")
+		}
+		return &godxmap.GabFrame{
+			From:    from,
+			To:      to,
+			Message: strings.Join(args, " "),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown frame %q", kind)
+	}
+}
+
+func parseFrequency(s string) (float64, error) {
+	result, err := strconv.ParseFloat(s, 64)
+	if err != nil || result <= 0 {
+		return 0, fmt.Errorf("invalid frequency %q", s)
+	}
+	return result, nil
+}
+
+// sendFrame connects to the server at the given URL, sends the given frame and disconnects.
+func sendFrame(ctx context.Context, url string, f godxmap.Frame, timeout time.Duration, options ...godxmap.ClientOption) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	connected := make(chan error, 1)
+	options = append(options, godxmap.WithStateHandler(func(state godxmap.ClientState, err error) {
+		switch state {
+		case godxmap.ClientConnected:
+			connected <- nil
+		case godxmap.ClientDisconnected:
+			if err == nil {
+				err = ctx.Err()
+			}
+			select {
+			case connected <- err:
+			default:
+			}
+		}
+	}))
+	client := godxmap.NewClient(url, options...)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	select {
+	case err := <-connected:
+		if err != nil {
+			return err
+		}
+	case <-ctx.Done():
+		return fmt.Errorf("cannot connect to %s: %v", url, ctx.Err())
+	}
+	return client.Send(f)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ftl/godxmap"
+	"github.com/ftl/godxmap/adif"
+	"github.com/ftl/godxmap/cluster"
+	"github.com/ftl/godxmap/rbn"
+	"github.com/ftl/godxmap/wsjtx"
+)
+
+const (
+	reconnectMinBackoff = 5 * time.Second
+	reconnectMaxBackoff = 5 * time.Minute
+)
+
+// serve runs the server and the configured sources until the given context is done.
+func serve(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("godxmap serve", flag.ContinueOnError)
+	cfg, err := parseConfig(flags, args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+	if (cfg.Cluster.Addr != "" || cfg.RBN.Addr != "") && cfg.Call == "" {
+		return errors.New("the DX cluster and the RBN need your callsign, use -call")
+	}
+
+	level := slog.LevelInfo
+	if cfg.Verbose {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+
+	options := []godxmap.Option{godxmap.WithLogger(logger)}
+	if cfg.Source != "" {
+		options = append(options, godxmap.WithSource(cfg.Source))
+	}
+	if cfg.TTL > 0 {
+		options = append(options, godxmap.WithDefaultTTL(time.Duration(cfg.TTL)))
+	}
+	if cfg.Token != "" {
+		options = append(options, godxmap.WithTokenAuthentication(godxmap.SharedSecret(cfg.Token)))
+	}
+	if cfg.Relay {
+		options = append(options, godxmap.WithRelayedFrames())
+	}
+	if cfg.Viewer {
+		options = append(options, godxmap.WithViewer())
+	}
+	if cfg.Debug {
+		options = append(options, godxmap.WithDebugEndpoint())
+	}
+	server := godxmap.NewServer(cfg.Addr, options...)
+
+	err = attachSources(ctx, server, cfg, logger)
+	if err != nil {
+		server.Close()
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	logger.Info("serving the map", "addr", cfg.Addr)
+	err = server.Serve()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// attachSources attaches the configured sources to the given server. The ADIF watcher runs until the given context is done.
+func attachSources(ctx context.Context, server *godxmap.Server, cfg config, logger *slog.Logger) error {
+	if cfg.Cluster.Addr != "" {
+		options := []cluster.Option{
+			cluster.WithReconnect(reconnectMinBackoff, reconnectMaxBackoff),
+			cluster.WithLogger(logger),
+		}
+		if cfg.Cluster.Password != "" {
+			options = append(options, cluster.WithPassword(cfg.Cluster.Password))
+		}
+		if len(cfg.Cluster.Commands) > 0 {
+			options = append(options, cluster.WithCommands(cfg.Cluster.Commands...))
+		}
+		client := cluster.NewClient(cfg.Cluster.Addr, cfg.Call, nil, options...)
+		err := server.AttachSource("cluster", client)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cfg.RBN.Addr != "" {
+		filter := rbn.Filter{MinSNR: cfg.RBN.MinSNR}
+		for _, band := range cfg.RBN.Bands {
+			filter.Bands = append(filter.Bands, godxmap.Band(strings.ToLower(band)))
+		}
+		for _, mode := range cfg.RBN.Modes {
+			filter.Modes = append(filter.Modes, godxmap.Mode(strings.ToUpper(mode)))
+		}
+		client := rbn.NewClient(rbnAddr(cfg.RBN.Addr), cfg.Call, filter, nil,
+			cluster.WithReconnect(reconnectMinBackoff, reconnectMaxBackoff),
+			cluster.WithLogger(logger),
+		)
+		err := server.AttachSource("rbn", client)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cfg.WSJTX.Addr != "" {
+		err := server.AttachSource("wsjtx", wsjtx.NewListener(cfg.WSJTX.Addr, server))
+		if err != nil {
+			return err
+		}
+	}
+
+	if cfg.ADIF.File != "" {
+		watcher := adif.NewWatcher(cfg.ADIF.File, server)
+		go func() {
+			err := watcher.Run(ctx)
+			if err != nil {
+				logger.Error("cannot watch the ADIF file", "file", cfg.ADIF.File, "error", err)
+			}
+		}()
+	}
+
+	return nil
+}
+
+// rbnAddr resolves the shortcuts "cw" and "ft8" to the addresses of the RBN telnet service.
+func rbnAddr(addr string) string {
+	switch strings.ToLower(addr) {
+	case "cw":
+		return rbn.CWAddr
+	case "ft8":
+		return rbn.FT8Addr
+	default:
+		return addr
+	}
+}
@@ -0,0 +1,200 @@
+package godxmap
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithCoalescing holds the DX spots back for the given delay and coalesces the spots of the same callsign on the same band
+// that arrive in the meantime into the latest one. Only this spot is sent to the clients. This prevents the markers from
+// flickering when a station is re-spotted or its frequency drifts, e.g. during a cluster storm, and saves bandwidth.
+//
+// A spot is sent at the latest after the given delay, also if it is replaced by newer spots in the meantime. Any other frame
+// sends the pending spots first, so the order of the frames is kept, e.g. when a spot is cleared right away.
+func WithCoalescing(delay time.Duration) Option {
+	return func(s *Server) {
+		s.coalescer = &coalescer{
+			delay:   delay,
+			pending: make(map[coalesceKey]*pendingSpot),
+			wakeup:  make(chan struct{}, 1),
+			stop:    make(chan struct{}),
+			stopped: make(chan struct{}),
+		}
+	}
+}
+
+type coalesceKey struct {
+	call string
+	band Band
+}
+
+type pendingSpot struct {
+	spot *DXSpotFrame
+	due  time.Time
+}
+
+type coalescer struct {
+	delay   time.Duration
+	wakeup  chan struct{}
+	stop    chan struct{}
+	stopped chan struct{}
+
+	lock    sync.Mutex
+	pending map[coalesceKey]*pendingSpot
+	order   []coalesceKey
+	closed  bool
+}
+
+// Add holds the given spot back until it is due. If a spot of the same callsign on the same band is already pending,
+// it is replaced and returned. If the coalescer is closed, Add does not hold the spot back and reports false.
+func (c *coalescer) Add(f *DXSpotFrame, now time.Time) (*DXSpotFrame, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.closed {
+		return nil, false
+	}
+	key := coalesceKey{strings.ToUpper(f.Spot), BandOf(f.Frequency)}
+	if pending, ok := c.pending[key]; ok {
+		replaced := pending.spot
+		pending.spot = f
+		return replaced, true
+	}
+	c.pending[key] = &pendingSpot{spot: f, due: now.Add(c.delay)}
+	c.order = append(c.order, key)
+	if len(c.order) == 1 {
+		select {
+		case c.wakeup <- struct{}{}:
+		default:
+		}
+	}
+	return nil, true
+}
+
+// Due removes the spots that are due at the given time and returns them in the order they were added.
+func (c *coalescer) Due(now time.Time) []*DXSpotFrame {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var result []*DXSpotFrame
+	for len(c.order) > 0 {
+		pending := c.pending[c.order[0]]
+		if pending.due.After(now) {
+			break
+		}
+		result = append(result, pending.spot)
+		delete(c.pending, c.order[0])
+		c.order = c.order[1:]
+	}
+	return result
+}
+
+// Flush removes all pending spots and returns them in the order they were added.
+func (c *coalescer) Flush() []*DXSpotFrame {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.flush()
+}
+
+// Close stops holding back spots and returns the pending spots.
+func (c *coalescer) Close() []*DXSpotFrame {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.closed = true
+	return c.flush()
+}
+
+// flush removes all pending spots and returns them in the order they were added. c.lock must be held.
+func (c *coalescer) flush() []*DXSpotFrame {
+	if len(c.order) == 0 {
+		return nil
+	}
+	result := make([]*DXSpotFrame, 0, len(c.order))
+	for _, key := range c.order {
+		result = append(result, c.pending[key].spot)
+	}
+	c.pending = make(map[coalesceKey]*pendingSpot)
+	c.order = nil
+	return result
+}
+
+// next returns the time when the next pending spot is due, or false if no spot is pending.
+func (c *coalescer) next() (time.Time, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if len(c.order) == 0 {
+		return time.Time{}, false
+	}
+	return c.pending[c.order[0]].due, true
+}
+
+// coalesce holds the given frame back if it is a DX spot and the spots are coalesced. Any other frame sends the pending
+// spots first. It reports if the frame was held back.
+func (s *Server) coalesce(f Frame) bool {
+	if s.coalescer == nil {
+		return false
+	}
+	spot, ok := f.(*DXSpotFrame)
+	if !ok {
+		s.sendSpots(s.coalescer.Flush())
+		return false
+	}
+	replaced, held := s.coalescer.Add(spot, s.now())
+	if replaced != nil {
+		s.stats.drop(1)
+		traceFrame(s.logger, replaced, "frame dropped", "reason", dropCoalesced)
+	}
+	return held
+}
+
+// flushCoalesced sends all pending spots right away.
+func (s *Server) flushCoalesced() {
+	if s.coalescer == nil {
+		return
+	}
+	s.sendSpots(s.coalescer.Flush())
+}
+
+func (s *Server) coalesceSpots() {
+	defer close(s.coalescer.stopped)
+	timer := time.NewTimer(s.coalescer.delay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-s.coalescer.stop:
+			return
+		case <-s.coalescer.wakeup:
+		case <-timer.C:
+		}
+		s.sendSpots(s.coalescer.Due(s.now()))
+		if next, ok := s.coalescer.next(); ok {
+			timer.Reset(next.Sub(s.now()))
+		}
+	}
+}
+
+func (s *Server) sendSpots(spots []*DXSpotFrame) {
+	for _, spot := range spots {
+		_, ok, err := s.broadcast(singleFrame(spot))
+		if err != nil {
+			s.logger.Warn("cannot send coalesced spot", "call", spot.Spot, "error", err)
+		}
+		if ok {
+			s.detectOpening(spot)
+		}
+	}
+}
+
+// stopCoalescing sends the pending spots and stops the coalescing. It must be called before the run loop is stopped.
+func (s *Server) stopCoalescing() {
+	if s.coalescer == nil {
+		return
+	}
+	close(s.coalescer.stop)
+	<-s.coalescer.stopped
+	s.sendSpots(s.coalescer.Close())
+}
@@ -0,0 +1,93 @@
+package godxmap_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ftl/godxmap"
+	"github.com/ftl/godxmap/godxmaptest"
+)
+
+func TestCoalescingSendsTheLatestSpot(t *testing.T) {
+	clock := godxmaptest.NewClock(time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC))
+	server := godxmaptest.NewServer(t, godxmap.WithCoalescing(50*time.Millisecond), godxmap.WithClock(clock.Now))
+	recorder := godxmaptest.NewRecorder(t, server)
+
+	server.ShowDXSpot("DL1ABC", "W1AW", 14025, "")
+	server.ShowDXSpot("DL2ABC", "W1AW", 14030, "")
+	server.ShowDXSpot("dl1abc", "K1TTT", 14025.3, "")
+	server.ShowDXSpot("DL1ABC", "W1AW", 7025, "")
+
+	// the spots are due by the clock of the server
+	time.Sleep(100 * time.Millisecond)
+	if frames := recorder.Frames(); len(frames) != 0 {
+		t.Errorf("the spots were sent before they were due: %v", frames)
+	}
+	clock.Advance(time.Second)
+
+	frames := recorder.Await(3)
+	if len(frames) != 3 {
+		t.Fatalf("expected 3 frames, got %v", frames)
+	}
+	expected := []struct {
+		spotter   string
+		frequency float64
+	}{
+		{"K1TTT", 14025.3},
+		{"W1AW", 14030},
+		{"W1AW", 7025},
+	}
+	for i, e := range expected {
+		spot := frames[i].(*godxmap.DXSpotFrame)
+		if spot.Spotter != e.spotter || spot.Frequency != e.frequency {
+			t.Errorf("spot %d: expected %.1fkHz by %s, got %.1fkHz by %s", i, e.frequency, e.spotter, spot.Frequency, spot.Spotter)
+		}
+	}
+}
+
+func TestCoalescingKeepsTheOrderOfTheFrames(t *testing.T) {
+	clock := godxmaptest.NewClock(time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC))
+	server := godxmaptest.NewServer(t, godxmap.WithCoalescing(time.Hour), godxmap.WithClock(clock.Now))
+	recorder := godxmaptest.NewRecorder(t, server)
+
+	server.ShowDXSpot("DL1ABC", "W1AW", 14025, "")
+	server.ShowDXSpot("DL1ABC", "K1TTT", 14026, "")
+	// any other frame sends the pending spots first
+	server.ShowGab("W1AW", "", "done")
+
+	frames := recorder.Await(2)
+	if len(frames) != 2 || frames[1].FrameType() != godxmap.GabFrameType {
+		t.Fatalf("unexpected frames: %v", frames)
+	}
+	if spot := frames[0].(*godxmap.DXSpotFrame); spot.Spotter != "K1TTT" {
+		t.Errorf("expected the latest spot, got the spot by %s", spot.Spotter)
+	}
+}
+
+func TestCloseSendsThePendingSpots(t *testing.T) {
+	clock := godxmaptest.NewClock(time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC))
+	server := godxmaptest.NewServer(t, godxmap.WithCoalescing(time.Hour), godxmap.WithClock(clock.Now))
+	conn, err := server.Pipe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	server.ShowDXSpot("DL1ABC", "W1AW", 14025, "")
+	server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), godxmaptest.DefaultTimeout)
+	defer cancel()
+	data, err := conn.Receive(ctx)
+	if err != nil {
+		t.Fatalf("the pending spot was not sent: %v", err)
+	}
+	f, err := godxmap.DecodeFrame(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spot, ok := f.(*godxmap.DXSpotFrame); !ok || spot.Spot != "DL1ABC" {
+		t.Errorf("unexpected frame: %v", f)
+	}
+}
@@ -0,0 +1,111 @@
+package godxmap
+
+// WithWinTestCompatibility sends the frames exactly like the original Win-Test wtSock gateway, so clients written
+// against the original gateway work unmodified:
+//
+//   - only LoggedCall, PartialCall, DXSpot and Gab frames are sent, all other frame types are suppressed,
+//   - the frames only contain the fields of the original gateway, without ID and without the extensions of godxmap,
+//     e.g. TTL, Style, Locator or Highlight, and all these fields are always present, even if they are empty,
+//   - every frame is sent in a websocket message of its own, batches are never sent as JSON array.
+//
+// The compatibility mode only affects the websocket clients, the sinks and the REST API still get the complete frames.
+func WithWinTestCompatibility() Option {
+	return func(s *Server) {
+		s.winTestCompatible = true
+	}
+}
+
+// wtSockHeader contains the header fields of the original gateway.
+type wtSockHeader struct {
+	Frame      string `json:"Frame"`
+	DateTime   int64  `json:"DateTime"`
+	SourceAddr string `json:"SourceAddr"`
+}
+
+func newWTSockHeader(h *FrameHeader) wtSockHeader {
+	return wtSockHeader{
+		Frame:      h.Frame,
+		DateTime:   h.DateTime,
+		SourceAddr: h.SourceAddr,
+	}
+}
+
+type wtSockLoggedCall struct {
+	wtSockHeader
+	Call      string  `json:"Call"`
+	Frequency float64 `json:"Frequency"`
+}
+
+type wtSockPartialCall struct {
+	wtSockHeader
+	Call string `json:"Call"`
+}
+
+type wtSockDXSpot struct {
+	wtSockHeader
+	Spot      string  `json:"Spot"`
+	Spotter   string  `json:"Spotter"`
+	Frequency float64 `json:"Frequency"`
+	Comments  string  `json:"Comments"`
+}
+
+type wtSockGab struct {
+	wtSockHeader
+	From    string `json:"From"`
+	To      string `json:"To"`
+	Message string `json:"Message"`
+}
+
+// winTestFrameTypes are the frame types that the original gateway sends.
+var winTestFrameTypes = map[string]bool{
+	LoggedCallFrameType:  true,
+	PartialCallFrameType: true,
+	DXSpotFrameType:      true,
+	GabFrameType:         true,
+}
+
+// winTestMessage returns the frames of the given message that the original gateway knows, as single frames.
+// It reports false if none of the frames remains.
+func winTestMessage(m message) (message, bool) {
+	result := message{frames: make([]Frame, 0, len(m.frames)), cache: m.cache}
+	for _, f := range m.frames {
+		if winTestFrameTypes[f.FrameType()] {
+			result.frames = append(result.frames, f)
+		}
+	}
+	return result, len(result.frames) > 0
+}
+
+// winTestFrame returns the given frame in the format of the original gateway.
+func winTestFrame(f Frame) any {
+	switch f := f.(type) {
+	case *LoggedCallFrame:
+		return wtSockLoggedCall{
+			wtSockHeader: newWTSockHeader(f.Header()),
+			Call:         f.Call,
+			Frequency:    f.Frequency,
+		}
+	case *PartialCallFrame:
+		return wtSockPartialCall{
+			wtSockHeader: newWTSockHeader(f.Header()),
+			Call:         f.Call,
+		}
+	case *DXSpotFrame:
+		return wtSockDXSpot{
+			wtSockHeader: newWTSockHeader(f.Header()),
+			Spot:         f.Spot,
+			Spotter:      f.Spotter,
+			Frequency:    f.Frequency,
+			Comments:     f.Comments,
+		}
+	case *GabFrame:
+		return wtSockGab{
+			wtSockHeader: newWTSockHeader(f.Header()),
+			From:         f.From,
+			To:           f.To,
+			Message:      f.Message,
+		}
+	default:
+		return f
+	}
+}
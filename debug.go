@@ -0,0 +1,246 @@
+package godxmap
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DebugPath is the path of the debug endpoint, see [WithDebugEndpoint].
+const DebugPath = "/debug/dxmap"
+
+const debugRecentFrames = 50
+
+// WithDebugEndpoint provides a page on the same address as the websocket that shows the connected clients, the recently
+// broadcast frames, the attached sources and the statistics of the server, e.g. to find out why nothing shows up on a map:
+//
+//	http://localhost:8080/debug/dxmap
+//
+// The page is rendered as HTML, or as JSON if the request accepts application/json or has the query parameter format=json.
+// The debug endpoint requires the same token as the websocket, if [WithTokenAuthentication] is used.
+func WithDebugEndpoint() Option {
+	return func(s *Server) {
+		s.debug = &debugRecorder{frames: make([]Frame, 0, debugRecentFrames)}
+	}
+}
+
+// ConnectionInfo describes a connected client.
+type ConnectionInfo struct {
+	RemoteAddr string
+	Connected  time.Time
+	// Bands and Modes are the filters that the client requested, see [BandsParameter] and [ModesParameter].
+	Bands []Band `json:",omitempty"`
+	Modes []Mode `json:",omitempty"`
+	// FrameTypes and Excluded are the frame types that the client subscribed to or excluded, see [SubscribeFrame].
+	FrameTypes []string `json:",omitempty"`
+	Excluded   []string `json:",omitempty"`
+	// ResumeAfter is the ID of the frame after which the client requested to resume the feed, see [WithResume].
+	ResumeAfter string `json:",omitempty"`
+}
+
+// Connections returns information about the currently connected clients, ordered by the time they connected.
+func (s *Server) Connections() []ConnectionInfo {
+	return s.connections.list()
+}
+
+type connectionRegistry struct {
+	lock        sync.Mutex
+	nextID      int
+	connections map[int]registeredConnection
+}
+
+type registeredConnection struct {
+	connection dxmapConnection
+	connected  time.Time
+}
+
+func (r *connectionRegistry) add(c dxmapConnection, connected time.Time) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.connections == nil {
+		r.connections = make(map[int]registeredConnection)
+	}
+	r.nextID++
+	r.connections[r.nextID] = registeredConnection{connection: c, connected: connected}
+	return r.nextID
+}
+
+func (r *connectionRegistry) remove(id int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.connections, id)
+}
+
+func (r *connectionRegistry) list() []ConnectionInfo {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	result := make([]ConnectionInfo, 0, len(r.connections))
+	for _, registered := range r.connections {
+		c := registered.connection
+		filter := c.filter.get()
+		result = append(result, ConnectionInfo{
+			RemoteAddr:  c.conn.RemoteAddr(),
+			Connected:   registered.connected,
+			Bands:       sortedKeys(filter.bands),
+			Modes:       sortedKeys(filter.modes),
+			FrameTypes:  sortedKeys(filter.frameTypes),
+			Excluded:    sortedKeys(filter.excluded),
+			ResumeAfter: c.resumeAfter,
+		})
+	}
+	slices.SortFunc(result, func(a, b ConnectionInfo) int {
+		return a.Connected.Compare(b.Connected)
+	})
+	return result
+}
+
+func sortedKeys[K ~string](m map[K]bool) []K {
+	result := make([]K, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	slices.Sort(result)
+	return result
+}
+
+// debugRecorder keeps the most recently broadcast frames for the debug endpoint.
+type debugRecorder struct {
+	lock   sync.Mutex
+	frames []Frame
+	next   int
+}
+
+func (d *debugRecorder) record(m message) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	for _, f := range m.frames {
+		if len(d.frames) < debugRecentFrames {
+			d.frames = append(d.frames, f)
+		} else {
+			d.frames[d.next] = f
+		}
+		d.next = (d.next + 1) % debugRecentFrames
+	}
+}
+
+// recent returns the recorded frames, the most recent first.
+func (d *debugRecorder) recent() []Frame {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	result := make([]Frame, 0, len(d.frames))
+	for i := range len(d.frames) {
+		result = append(result, d.frames[(d.next-1-i+2*len(d.frames))%len(d.frames)])
+	}
+	return result
+}
+
+type debugReport struct {
+	Started      time.Time
+	Uptime       string
+	Clients      []ConnectionInfo
+	FramesSent   map[string]int
+	SendErrors   int
+	Dropped      int
+	Sources      []debugSource
+	RecentFrames []Frame
+}
+
+type debugSource struct {
+	Name     string
+	Running  bool
+	Error    string `json:",omitempty"`
+	Started  time.Time
+	Spots    int
+	Rejected int
+	LastSpot time.Time
+}
+
+func (s *Server) debugReport() debugReport {
+	stats := s.Stats()
+	result := debugReport{
+		Started:      stats.Started,
+		Uptime:       stats.Uptime.Round(time.Second).String(),
+		Clients:      s.Connections(),
+		FramesSent:   stats.FramesSent,
+		SendErrors:   stats.SendErrors,
+		Dropped:      stats.Dropped,
+		RecentFrames: s.debug.recent(),
+	}
+	for _, health := range s.SourceHealth() {
+		source := debugSource{
+			Name:     health.Name,
+			Running:  health.Running,
+			Started:  health.Started,
+			Spots:    health.Spots,
+			Rejected: health.Rejected,
+			LastSpot: health.LastSpot,
+		}
+		if health.Err != nil {
+			source.Error = health.Err.Error()
+		}
+		result.Sources = append(result.Sources, source)
+	}
+	return result
+}
+
+func (s *Server) serveDebug(w http.ResponseWriter, r *http.Request) {
+	report := s.debugReport()
+	if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err := debugTemplate.Execute(w, report)
+	if err != nil {
+		s.logger.Warn("cannot render debug page", "error", err)
+	}
+}
+
+var debugTemplate = template.Must(template.New("debug").Funcs(template.FuncMap{
+	"json": func(f Frame) string {
+		data, _ := json.Marshal(f)
+		return string(data)
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><title>godxmap</title></head>
+<body>
+<h1>godxmap</h1>
+<p>Started {{.Started.Format "2006-01-02 15:04:05 MST"}}, up {{.Uptime}}. {{.SendErrors}} send errors, {{.Dropped}} dropped frames.</p>
+<h2>Clients</h2>
+<table>
+<tr><th>Remote Address</th><th>Connected</th><th>Bands</th><th>Modes</th><th>Frame Types</th><th>Excluded</th></tr>
+{{range .Clients}}<tr><td>{{.RemoteAddr}}</td><td>{{.Connected.Format "15:04:05"}}</td><td>{{.Bands}}</td><td>{{.Modes}}</td><td>{{.FrameTypes}}</td><td>{{.Excluded}}</td></tr>
+{{else}}<tr><td colspan="6">no clients connected</td></tr>
+{{end}}</table>
+<h2>Frames Sent</h2>
+<table>
+{{range $frameType, $count := .FramesSent}}<tr><td>{{$frameType}}</td><td>{{$count}}</td></tr>
+{{else}}<tr><td>no frames sent</td></tr>
+{{end}}</table>
+<h2>Sources</h2>
+<table>
+<tr><th>Name</th><th>Running</th><th>Spots</th><th>Rejected</th><th>Error</th></tr>
+{{range .Sources}}<tr><td>{{.Name}}</td><td>{{.Running}}</td><td>{{.Spots}}</td><td>{{.Rejected}}</td><td>{{.Error}}</td></tr>
+{{else}}<tr><td colspan="5">no sources attached</td></tr>
+{{end}}</table>
+<h2>Recent Frames</h2>
+<pre>{{range .RecentFrames}}{{json .}}
+{{else}}no frames sent
+{{end}}</pre>
+</body>
+</html>
+`))
@@ -0,0 +1,92 @@
+package godxmap
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithDeduplication suppresses repeated spots of the same callsign within the given time window, if the frequency
+// differs by less than the given tolerance in kHz. This keeps the map clean when several spot sources are combined,
+// e.g. a DX cluster and the Reverse Beacon Network.
+func WithDeduplication(window time.Duration, toleranceKHz float64) Option {
+	return func(s *Server) {
+		s.dedup = newDeduplicator(window, toleranceKHz)
+	}
+}
+
+type deduplicator struct {
+	window       time.Duration
+	toleranceKHz float64
+
+	mutex     sync.Mutex
+	spots     map[string][]dedupEntry
+	lastPrune time.Time
+}
+
+type dedupEntry struct {
+	frequencyKHz float64
+	time         time.Time
+}
+
+func newDeduplicator(window time.Duration, toleranceKHz float64) *deduplicator {
+	return &deduplicator{
+		window:       window,
+		toleranceKHz: toleranceKHz,
+		spots:        make(map[string][]dedupEntry),
+	}
+}
+
+// Duplicate reports if a spot of the given call on the given frequency was already seen within the time window.
+// Otherwise, the spot is remembered.
+func (d *deduplicator) Duplicate(call string, frequencyKHz float64, t time.Time) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	call = strings.ToUpper(call)
+	entries := d.spots[call][:0]
+	duplicate := false
+	for _, entry := range d.spots[call] {
+		if t.Sub(entry.time) >= d.window {
+			continue
+		}
+		entries = append(entries, entry)
+		if math.Abs(entry.frequencyKHz-frequencyKHz) <= d.toleranceKHz && !t.Before(entry.time) {
+			duplicate = true
+		}
+	}
+	if !duplicate {
+		entries = append(entries, dedupEntry{frequencyKHz: frequencyKHz, time: t})
+	}
+	if len(entries) == 0 {
+		delete(d.spots, call)
+	} else {
+		d.spots[call] = entries
+	}
+	d.prune(t)
+	return duplicate
+}
+
+// prune removes the calls that were not spotted within the time window. d.mutex must be held.
+func (d *deduplicator) prune(now time.Time) {
+	if now.Sub(d.lastPrune) < d.window {
+		return
+	}
+	d.lastPrune = now
+	for call, entries := range d.spots {
+		if now.Sub(entries[len(entries)-1].time) >= d.window {
+			delete(d.spots, call)
+		}
+	}
+}
+
+func (d *deduplicator) Shed(pressure MemoryPressure) {
+	if pressure < MemoryPressureCritical {
+		return
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.spots = make(map[string][]dedupEntry)
+}
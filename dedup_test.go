@@ -0,0 +1,55 @@
+package godxmap_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ftl/godxmap"
+	"github.com/ftl/godxmap/godxmaptest"
+)
+
+func TestDeduplication(t *testing.T) {
+	clock := godxmaptest.NewClock(time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC))
+	server := godxmaptest.NewServer(t, godxmap.WithDeduplication(5*time.Minute, 1), godxmap.WithClock(clock.Now))
+	recorder := godxmaptest.NewRecorder(t, server)
+
+	for _, step := range []struct {
+		advance   time.Duration
+		call      string
+		frequency float64
+	}{
+		{0, "DL1ABC", 14025},
+		{time.Minute, "dl1abc", 14025.8},   // duplicate, within the tolerance
+		{time.Minute, "DL1ABC", 14027},     // outside the tolerance
+		{time.Minute, "DL2ABC", 14025},     // other call
+		{2 * time.Minute, "DL1ABC", 14025}, // the first spot is out of the window
+		{time.Minute, "DL1ABC", 14026.5},   // duplicate of the second spot
+	} {
+		clock.Advance(step.advance)
+		err := server.ShowDXSpot(step.call, "W1AW", step.frequency, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	server.ShowGab("W1AW", "", "done")
+
+	frames := recorder.Await(5)
+	if len(frames) != 5 || frames[4].FrameType() != godxmap.GabFrameType {
+		t.Fatalf("unexpected frames: %v", frames)
+	}
+	expected := []struct {
+		call      string
+		frequency float64
+	}{
+		{"DL1ABC", 14025},
+		{"DL1ABC", 14027},
+		{"DL2ABC", 14025},
+		{"DL1ABC", 14025},
+	}
+	for i, e := range expected {
+		spot := frames[i].(*godxmap.DXSpotFrame)
+		if spot.Spot != e.call || spot.Frequency != e.frequency {
+			t.Errorf("spot %d: expected %s on %.1fkHz, got %s on %.1fkHz", i, e.call, e.frequency, spot.Spot, spot.Frequency)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// The package dxcc resolves callsigns to DXCC entities using the country files from https://www.country-files.com
+// (cty.dat or cty.csv) and enriches the frames of a [godxmap.Server] with this information.
+package dxcc
+
+import (
+	"strings"
+
+	"github.com/ftl/godxmap"
+)
+
+// Entity describes a DXCC entity or a part of it with deviating zones or coordinates.
+type Entity struct {
+	Name          string
+	PrimaryPrefix string
+	// DXCC is the ADIF number of the entity. It is only available when loaded from cty.csv.
+	DXCC      int
+	CQZone    int
+	ITUZone   int
+	Continent string
+	// Latitude in degrees, north is positive.
+	Latitude float64
+	// Longitude in degrees, east is positive.
+	Longitude float64
+	// UTCOffset in hours.
+	UTCOffset float64
+}
+
+// Database resolves callsigns to DXCC entities.
+type Database struct {
+	prefixes     map[string]Entity
+	exactCalls   map[string]Entity
+	maxPrefixLen int
+}
+
+func newDatabase() *Database {
+	return &Database{
+		prefixes:   make(map[string]Entity),
+		exactCalls: make(map[string]Entity),
+	}
+}
+
+func (db *Database) addPrefix(prefix string, entity Entity) {
+	db.prefixes[prefix] = entity
+	db.maxPrefixLen = max(db.maxPrefixLen, len(prefix))
+}
+
+func (db *Database) addExactCall(call string, entity Entity) {
+	db.exactCalls[call] = entity
+}
+
+// Len returns the number of known prefixes and exact callsigns.
+func (db *Database) Len() int {
+	return len(db.prefixes) + len(db.exactCalls)
+}
+
+// Resolve returns the DXCC entity of the given callsign.
+func (db *Database) Resolve(call string) (Entity, bool) {
+	call = strings.ToUpper(strings.TrimSpace(call))
+	if entity, ok := db.exactCalls[call]; ok {
+		return entity, true
+	}
+	prefix := relevantPart(call)
+	if entity, ok := db.exactCalls[prefix]; ok {
+		return entity, true
+	}
+	for length := min(len(prefix), db.maxPrefixLen); length > 0; length-- {
+		if entity, ok := db.prefixes[prefix[:length]]; ok {
+			return entity, true
+		}
+	}
+	return Entity{}, false
+}
+
+// suffixes that do not change the DXCC entity of a callsign
+var ignoredSuffixes = map[string]bool{
+	"P": true, "M": true, "MM": true, "AM": true, "QRP": true, "A": true, "B": true, "LH": true,
+}
+
+// relevantPart returns the part of a compound callsign that determines the DXCC entity,
+// e.g. "VP2E" for "VP2E/DL1ABC" and "DL1ABC" for "DL1ABC/P".
+func relevantPart(call string) string {
+	parts := strings.Split(call, "/")
+	candidates := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part == "" || ignoredSuffixes[part] || (len(part) == 1 && part[0] >= '0' && part[0] <= '9') {
+			continue
+		}
+		candidates = append(candidates, part)
+	}
+	if len(candidates) == 0 {
+		return call
+	}
+	// the shorter part is the prefix of the operating location
+	result := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if len(candidate) < len(result) {
+			result = candidate
+		}
+	}
+	return result
+}
+
+// CallInfo returns the information about the given callsign for [godxmap.Server.ShowPartialCallInfo].
+func (db *Database) CallInfo(call string) (godxmap.CallInfo, bool) {
+	entity, ok := db.Resolve(call)
+	if !ok {
+		return godxmap.CallInfo{}, false
+	}
+	return godxmap.CallInfo{
+		DXCC:      entity.DXCC,
+		Entity:    entity.Name,
+		Continent: entity.Continent,
+		Position:  &godxmap.LatLon{Latitude: entity.Latitude, Longitude: entity.Longitude},
+	}, true
+}
+
+// Middleware returns a [godxmap.Middleware] that enriches all partial call frames that do not carry DXCC information yet.
+func (db *Database) Middleware() godxmap.Middleware {
+	return func(f godxmap.Frame) (godxmap.Frame, bool) {
+		partialCall, ok := f.(*godxmap.PartialCallFrame)
+		if !ok || partialCall.Entity != "" {
+			return f, true
+		}
+		info, ok := db.CallInfo(partialCall.Call)
+		if !ok {
+			return f, true
+		}
+		partialCall.DXCC = info.DXCC
+		partialCall.Entity = info.Entity
+		partialCall.Continent = info.Continent
+		if partialCall.Latitude == nil && partialCall.Longitude == nil {
+			partialCall.Latitude = &info.Position.Latitude
+			partialCall.Longitude = &info.Position.Longitude
+		}
+		return f, true
+	}
+}
+
+// ContinentOfSpot returns a function that maps a spot to the continent of the spotted station.
+// It can be used as Region function in a [godxmap.BandOpeningConfig].
+func (db *Database) ContinentOfSpot() func(spot string, spotter string) string {
+	return func(spot string, _ string) string {
+		entity, ok := db.Resolve(spot)
+		if !ok {
+			return ""
+		}
+		return entity.Continent
+	}
+}
+
+// Enrich implements [godxmap.Enricher].
+func (db *Database) Enrich(call string) (godxmap.CallInfo, error) {
+	info, ok := db.CallInfo(call)
+	if !ok {
+		return godxmap.CallInfo{}, godxmap.ErrUnknownCall
+	}
+	return info, nil
+}
@@ -0,0 +1,192 @@
+package dxcc
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LoadFile loads the given country file. Files with the extension .csv are loaded as cty.csv, all other files as cty.dat.
+func LoadFile(filename string) (*Database, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if strings.HasSuffix(strings.ToLower(filename), ".csv") {
+		return LoadCSV(file)
+	}
+	return LoadDat(file)
+}
+
+// LoadDat loads a country file in the cty.dat format.
+func LoadDat(r io.Reader) (*Database, error) {
+	result := newDatabase()
+	scanner := bufio.NewScanner(r)
+	var entity Entity
+	var aliases strings.Builder
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if line[0] != ' ' && line[0] != '\t' {
+			fields := strings.Split(line, ":")
+			if len(fields) < 8 {
+				return nil, fmt.Errorf("line %d: invalid entity", lineNumber)
+			}
+			var err error
+			entity, err = parseEntity(fields[0], "", fields[1], fields[2], fields[3], fields[4], fields[5], fields[6], fields[7])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", lineNumber, err)
+			}
+			aliases.Reset()
+			continue
+		}
+
+		aliases.WriteString(strings.TrimSpace(line))
+		if strings.HasSuffix(aliases.String(), ";") {
+			result.addAliases(strings.TrimSuffix(aliases.String(), ";"), entity)
+			aliases.Reset()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// LoadCSV loads a country file in the cty.csv format.
+func LoadCSV(r io.Reader) (*Database, error) {
+	result := newDatabase()
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	lineNumber := 0
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		lineNumber++
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) < 10 {
+			return nil, fmt.Errorf("line %d: invalid entity", lineNumber)
+		}
+		// Prefix,Entity,ADIF,CQ,ITU,Continent,Lat,Lon,UTC,Aliases;
+		entity, err := parseEntity(fields[1], fields[2], fields[3], fields[4], fields[5], fields[6], fields[7], fields[8], fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNumber, err)
+		}
+		result.addAliases(strings.TrimSuffix(strings.TrimSpace(fields[9]), ";"), entity)
+	}
+	return result, nil
+}
+
+func parseEntity(name, dxcc, cqZone, ituZone, continent, latitude, longitude, utcOffset, primaryPrefix string) (Entity, error) {
+	var err error
+	result := Entity{
+		Name:          strings.TrimSpace(name),
+		PrimaryPrefix: strings.TrimPrefix(strings.TrimSpace(primaryPrefix), "*"),
+		Continent:     strings.TrimSpace(continent),
+	}
+	if strings.TrimSpace(dxcc) != "" {
+		result.DXCC, err = strconv.Atoi(strings.TrimSpace(dxcc))
+		if err != nil {
+			return Entity{}, fmt.Errorf("invalid DXCC number: %v", err)
+		}
+	}
+	result.CQZone, err = strconv.Atoi(strings.TrimSpace(cqZone))
+	if err != nil {
+		return Entity{}, fmt.Errorf("invalid CQ zone: %v", err)
+	}
+	result.ITUZone, err = strconv.Atoi(strings.TrimSpace(ituZone))
+	if err != nil {
+		return Entity{}, fmt.Errorf("invalid ITU zone: %v", err)
+	}
+	result.Latitude, err = strconv.ParseFloat(strings.TrimSpace(latitude), 64)
+	if err != nil {
+		return Entity{}, fmt.Errorf("invalid latitude: %v", err)
+	}
+	result.Longitude, err = strconv.ParseFloat(strings.TrimSpace(longitude), 64)
+	if err != nil {
+		return Entity{}, fmt.Errorf("invalid longitude: %v", err)
+	}
+	// the country files use positive longitudes for the west
+	result.Longitude = -result.Longitude
+	result.UTCOffset, err = strconv.ParseFloat(strings.TrimSpace(utcOffset), 64)
+	if err != nil {
+		return Entity{}, fmt.Errorf("invalid UTC offset: %v", err)
+	}
+	return result, nil
+}
+
+// addAliases adds the comma or whitespace separated list of prefixes and exact callsigns (marked with =) of the given entity.
+// Each alias may override the zones, the coordinates, the continent or the UTC offset of the entity:
+// (CQ zone), [ITU zone], <latitude/longitude>, {continent}, ~UTC offset~.
+func (db *Database) addAliases(aliases string, entity Entity) {
+	for _, alias := range strings.FieldsFunc(aliases, func(r rune) bool { return r == ',' || r == ' ' }) {
+		prefix, aliasEntity := parseAlias(alias, entity)
+		if prefix == "" {
+			continue
+		}
+		if exact, found := strings.CutPrefix(prefix, "="); found {
+			db.addExactCall(exact, aliasEntity)
+		} else {
+			db.addPrefix(prefix, aliasEntity)
+		}
+	}
+}
+
+func parseAlias(alias string, entity Entity) (string, Entity) {
+	prefix := new(strings.Builder)
+	for len(alias) > 0 {
+		var closing byte
+		switch alias[0] {
+		case '(':
+			closing = ')'
+		case '[':
+			closing = ']'
+		case '<':
+			closing = '>'
+		case '{':
+			closing = '}'
+		case '~':
+			closing = '~'
+		default:
+			prefix.WriteByte(alias[0])
+			alias = alias[1:]
+			continue
+		}
+		end := strings.IndexByte(alias[1:], closing)
+		if end == -1 {
+			break
+		}
+		value := alias[1 : end+1]
+		switch alias[0] {
+		case '(':
+			entity.CQZone, _ = strconv.Atoi(value)
+		case '[':
+			entity.ITUZone, _ = strconv.Atoi(value)
+		case '<':
+			latitude, longitude, _ := strings.Cut(value, "/")
+			entity.Latitude, _ = strconv.ParseFloat(latitude, 64)
+			westLongitude, _ := strconv.ParseFloat(longitude, 64)
+			entity.Longitude = -westLongitude
+		case '{':
+			entity.Continent = value
+		case '~':
+			entity.UTCOffset, _ = strconv.ParseFloat(value, 64)
+		}
+		alias = alias[end+2:]
+	}
+	return strings.ToUpper(prefix.String()), entity
+}
@@ -0,0 +1,120 @@
+package dxcc
+
+import (
+	"strings"
+	"testing"
+)
+
+const ctyDat = `Germany:                  14:  28:  EU:   51.00:   -10.00:    -1.0:  DL:
+    DA,DB,DC,DD,DE,DF,DG,DH,DI,DJ,DK,DL,DM,DN,DO,DP,DQ,DR,Y2,Y3,Y4,Y5,Y6,Y7,Y8,Y9,
+    =DL0XX(15);
+United States:            05:  08:  NA:   37.53:    91.67:     5.0:  K:
+    AA,AB,AC,AD,AE,AF,AG,AI,AJ,AK,K,N,W,
+    AA6(3)[6],=W1AW/KH6(31)[61]<21.30/157.80>{OC}~10.0~;
+Hawaii:                   31:  61:  OC:   21.12:   157.48:    10.0:  KH6:
+    AH6,KH6,NH6,WH6;
+`
+
+const ctyCSV = `DL,Germany,230,14,28,EU,51.00,-10.00,-1.0,DA DB DC DD DE DF DG DH DI DJ DK DL DM DN DO DP DQ DR Y2 Y3 Y4 Y5 Y6 Y7 Y8 Y9 =DL0XX(15);
+K,United States,291,05,08,NA,37.53,91.67,5.0,AA AB AC AD AE AF AG AI AJ AK K N W AA6(3)[6] =W1AW/KH6(31)[61]<21.30/157.80>{OC}~10.0~;
+KH6,Hawaii,110,31,61,OC,21.12,157.48,10.0,AH6 KH6 NH6 WH6;
+`
+
+func TestLoad(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		load func(string) (*Database, error)
+		data string
+		dxcc map[string]int
+	}{
+		{"cty.dat", loadDat, ctyDat, map[string]int{}},
+		{"cty.csv", loadCSV, ctyCSV, map[string]int{"DL": 230, "K": 291, "KH6": 110}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := tc.load(tc.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if db.Len() != 46 {
+				t.Errorf("expected 46 prefixes and callsigns, got %d", db.Len())
+			}
+			for _, e := range []struct {
+				call     string
+				expected Entity
+			}{
+				{call: "DL1ABC", expected: Entity{Name: "Germany", PrimaryPrefix: "DL", CQZone: 14, ITUZone: 28, Continent: "EU", Latitude: 51, Longitude: 10, UTCOffset: -1}},
+				{call: "dl0xx", expected: Entity{Name: "Germany", PrimaryPrefix: "DL", CQZone: 15, ITUZone: 28, Continent: "EU", Latitude: 51, Longitude: 10, UTCOffset: -1}},
+				{call: "Y21ABC/P", expected: Entity{Name: "Germany", PrimaryPrefix: "DL", CQZone: 14, ITUZone: 28, Continent: "EU", Latitude: 51, Longitude: 10, UTCOffset: -1}},
+				{call: "W1AW", expected: Entity{Name: "United States", PrimaryPrefix: "K", CQZone: 5, ITUZone: 8, Continent: "NA", Latitude: 37.53, Longitude: -91.67, UTCOffset: 5}},
+				{call: "AA6XY", expected: Entity{Name: "United States", PrimaryPrefix: "K", CQZone: 3, ITUZone: 6, Continent: "NA", Latitude: 37.53, Longitude: -91.67, UTCOffset: 5}},
+				{call: "W1AW/KH6", expected: Entity{Name: "United States", PrimaryPrefix: "K", CQZone: 31, ITUZone: 61, Continent: "OC", Latitude: 21.3, Longitude: -157.8, UTCOffset: 10}},
+				{call: "KH6/DL1ABC", expected: Entity{Name: "Hawaii", PrimaryPrefix: "KH6", CQZone: 31, ITUZone: 61, Continent: "OC", Latitude: 21.12, Longitude: -157.48, UTCOffset: 10}},
+			} {
+				e.expected.DXCC = tc.dxcc[e.expected.PrimaryPrefix]
+				actual, ok := db.Resolve(e.call)
+				if !ok {
+					t.Errorf("%s: unknown", e.call)
+					continue
+				}
+				if actual != e.expected {
+					t.Errorf("%s: expected\n%+v\ngot\n%+v", e.call, e.expected, actual)
+				}
+			}
+			if entity, ok := db.Resolve("JA1ABC"); ok {
+				t.Errorf("JA1ABC: expected unknown, got %+v", entity)
+			}
+		})
+	}
+}
+
+func TestLoadInvalidFile(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		load     func(string) (*Database, error)
+		data     string
+		expected string
+	}{
+		{"truncated entity", loadDat, "Germany:  14:  28:  EU:   51.00:\n    DL;\n", "line 1: invalid entity"},
+		{"invalid CQ zone", loadDat, ctyDat + "Nowhere:  XX:  28:  EU:   51.00:   -10.00:    -1.0:  NO:\n", "line 9: invalid CQ zone"},
+		{"invalid latitude", loadDat, "Germany:  14:  28:  EU:   north:   -10.00:    -1.0:  DL:\n", "line 1: invalid latitude"},
+		{"oversized line", loadDat, "Germany:  14:  28:  EU:   51.00:   -10.00:    -1.0:  DL:\n    " + strings.Repeat("DL,", 30000) + ";\n", "token too long"},
+		{"truncated CSV entity", loadCSV, "DL,Germany,230,14,28,EU,51.00\n", "line 1: invalid entity"},
+		{"invalid DXCC number", loadCSV, "DL,Germany,DE,14,28,EU,51.00,-10.00,-1.0,DL;\n", "line 1: invalid DXCC number"},
+		{"invalid CSV", loadCSV, "DL,\"Germany,230\n", "extraneous or missing"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.load(tc.data)
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("expected error %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestParseAlias(t *testing.T) {
+	entity := Entity{Name: "United States", CQZone: 5, ITUZone: 8, Continent: "NA", Latitude: 37.53, Longitude: -91.67, UTCOffset: 5}
+	for _, tc := range []struct {
+		alias    string
+		prefix   string
+		expected Entity
+	}{
+		{"k", "K", entity},
+		{"AA6(3)", "AA6", Entity{Name: "United States", CQZone: 3, ITUZone: 8, Continent: "NA", Latitude: 37.53, Longitude: -91.67, UTCOffset: 5}},
+		{"=KL7ABC[1]{AS}", "=KL7ABC", Entity{Name: "United States", CQZone: 5, ITUZone: 1, Continent: "AS", Latitude: 37.53, Longitude: -91.67, UTCOffset: 5}},
+		{"AA6(3", "AA6", entity},
+		{"(3)", "", Entity{Name: "United States", CQZone: 3, ITUZone: 8, Continent: "NA", Latitude: 37.53, Longitude: -91.67, UTCOffset: 5}},
+	} {
+		prefix, actual := parseAlias(tc.alias, entity)
+		if prefix != tc.prefix || actual != tc.expected {
+			t.Errorf("%q: expected %q %+v, got %q %+v", tc.alias, tc.prefix, tc.expected, prefix, actual)
+		}
+	}
+}
+
+func loadDat(s string) (*Database, error) {
+	return LoadDat(strings.NewReader(s))
+}
+
+func loadCSV(s string) (*Database, error) {
+	return LoadCSV(strings.NewReader(s))
+}
@@ -0,0 +1,172 @@
+package godxmap
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnknownCall is returned by an [Enricher] that has no information about a callsign.
+var ErrUnknownCall = errors.New("unknown callsign")
+
+// Enricher provides information about callsigns, e.g. from cty.dat, an online callbook or a call history file.
+type Enricher interface {
+	Enrich(call string) (CallInfo, error)
+}
+
+// EnricherFunc adapts a function to the [Enricher] interface.
+type EnricherFunc func(call string) (CallInfo, error)
+
+// Enrich implements [Enricher].
+func (fn EnricherFunc) Enrich(call string) (CallInfo, error) {
+	return fn(call)
+}
+
+// WithEnrichers enriches all logged calls, partial calls and DX spots with the information about their callsigns,
+// before the middleware chain is applied. The enrichers are asked in the given order; later enrichers only
+// fill in the fields that are still empty. Fields that were already set by the sender are kept.
+func WithEnrichers(enrichers ...Enricher) Option {
+	return func(s *Server) {
+		s.enrichers = append(s.enrichers, enrichers...)
+	}
+}
+
+// NewCachingEnricher caches the results of the given enricher for the given time, including unknown callsigns.
+func NewCachingEnricher(enricher Enricher, ttl time.Duration) Enricher {
+	return &cachingEnricher{
+		enricher: enricher,
+		ttl:      ttl,
+		cache:    make(map[string]cachedCallInfo),
+	}
+}
+
+type cachingEnricher struct {
+	enricher Enricher
+	ttl      time.Duration
+
+	mutex sync.Mutex
+	cache map[string]cachedCallInfo
+}
+
+type cachedCallInfo struct {
+	info    CallInfo
+	err     error
+	expires time.Time
+}
+
+// clockedEnricher is implemented by the enrichers that depend on the time. The server passes them the time of its clock.
+type clockedEnricher interface {
+	EnrichAt(call string, now time.Time) (CallInfo, error)
+}
+
+// Enrich implements [Enricher].
+func (e *cachingEnricher) Enrich(call string) (CallInfo, error) {
+	return e.EnrichAt(call, time.Now())
+}
+
+// EnrichAt returns the information about the given callsign, cached results expire relative to the given time.
+func (e *cachingEnricher) EnrichAt(call string, now time.Time) (CallInfo, error) {
+	call = strings.ToUpper(call)
+
+	e.mutex.Lock()
+	cached, ok := e.cache[call]
+	e.mutex.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.info, cached.err
+	}
+
+	info, err := e.enricher.Enrich(call)
+	if err != nil && err != ErrUnknownCall {
+		return CallInfo{}, err
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	for cachedCall, cached := range e.cache {
+		if now.After(cached.expires) {
+			delete(e.cache, cachedCall)
+		}
+	}
+	e.cache[call] = cachedCallInfo{info: info, err: err, expires: now.Add(e.ttl)}
+	return info, err
+}
+
+func (s *Server) enrich(f Frame) {
+	if len(s.enrichers) == 0 {
+		return
+	}
+	switch f := f.(type) {
+	case *LoggedCallFrame:
+		info := s.callInfo(f.Call)
+		fillString(&f.Name, info.Name)
+		info.fillPosition(&f.Locator, &f.Latitude, &f.Longitude)
+	case *PartialCallFrame:
+		info := s.callInfo(f.Call)
+		fillString(&f.Name, info.Name)
+		if f.Entity == "" {
+			f.DXCC = info.DXCC
+			f.Entity = info.Entity
+		}
+		fillString(&f.Continent, info.Continent)
+		info.fillPosition(&f.Locator, &f.Latitude, &f.Longitude)
+	case *DXSpotFrame:
+		info := s.callInfo(f.Spot)
+		info.fillPosition(&f.Locator, &f.Latitude, &f.Longitude)
+	}
+}
+
+// callInfo merges the information of all enrichers about the given callsign.
+func (s *Server) callInfo(call string) CallInfo {
+	var result CallInfo
+	if call == "" {
+		return result
+	}
+	for _, enricher := range s.enrichers {
+		var info CallInfo
+		var err error
+		if clocked, ok := enricher.(clockedEnricher); ok {
+			info, err = clocked.EnrichAt(call, s.now())
+		} else {
+			info, err = enricher.Enrich(call)
+		}
+		if err == ErrUnknownCall {
+			continue
+		}
+		if err != nil {
+			s.logger.Warn("cannot enrich call", "call", call, "error", err)
+			continue
+		}
+		fillString(&result.Name, info.Name)
+		if result.Entity == "" {
+			result.DXCC = info.DXCC
+			result.Entity = info.Entity
+		}
+		fillString(&result.Continent, info.Continent)
+		if result.Locator == "" && result.Position == nil {
+			result.Locator = info.Locator
+			result.Position = info.Position
+		}
+	}
+	return result
+}
+
+func fillString(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
+
+// fillPosition fills in the position of a frame, if the frame does not have a position yet.
+func (i CallInfo) fillPosition(locator *string, latitude **float64, longitude **float64) {
+	if *locator != "" || *latitude != nil || *longitude != nil {
+		return
+	}
+	if normalized, lat, lon, err := located(i.Locator); err == nil {
+		*locator, *latitude, *longitude = normalized, lat, lon
+	}
+	if i.Position != nil {
+		lat, lon := i.Position.Latitude, i.Position.Longitude
+		*latitude, *longitude = &lat, &lon
+	}
+}
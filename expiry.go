@@ -0,0 +1,142 @@
+package godxmap
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultExpiryInterval = 10 * time.Second
+
+// SpotExpiryConfig defines the lifetimes of the DX spots, see [WithSpotExpiry].
+// A lifetime of zero means that the spots do not expire.
+type SpotExpiryConfig struct {
+	// Lifetime is the default lifetime of the spots.
+	Lifetime time.Duration
+	// Sources defines the lifetimes of the spots from the given sources, by the SourceAddr of the frames.
+	// They take precedence over the lifetimes of the bands.
+	Sources map[string]time.Duration
+	// Bands defines the lifetimes of the spots on the given bands.
+	Bands map[Band]time.Duration
+	// Interval is the period between two checks for expired spots. The default is ten seconds.
+	Interval time.Duration
+}
+
+func (c SpotExpiryConfig) lifetime(f *DXSpotFrame) time.Duration {
+	if lifetime, ok := c.Sources[f.SourceAddr]; ok {
+		return lifetime
+	}
+	if lifetime, ok := c.Bands[BandOf(f.Frequency)]; ok {
+		return lifetime
+	}
+	return c.Lifetime
+}
+
+// WithSpotExpiry removes the DX spots from all connected maps when their lifetime is over, by broadcasting a [ClearCallFrame]
+// for each expired spot. This keeps the maps consistent, instead of relying on the aging of each client.
+// The lifetime of a spot starts when it is sent, not at its DateTime. A spot of the same callsign on the same frequency
+// renews the lifetime.
+func WithSpotExpiry(config SpotExpiryConfig) Option {
+	return func(s *Server) {
+		if config.Interval <= 0 {
+			config.Interval = defaultExpiryInterval
+		}
+		s.expiry = &spotExpiry{
+			config:  config,
+			spots:   make(map[markerKey]expiringSpot),
+			stop:    make(chan struct{}),
+			stopped: make(chan struct{}),
+		}
+	}
+}
+
+type expiringSpot struct {
+	call      string
+	frequency float64
+	expires   time.Time
+}
+
+type spotExpiry struct {
+	config  SpotExpiryConfig
+	stop    chan struct{}
+	stopped chan struct{}
+
+	lock  sync.Mutex
+	spots map[markerKey]expiringSpot
+}
+
+// Update tracks the lifetime of the given frame that was received at the given time. It is called from within the run loop.
+func (e *spotExpiry) Update(f Frame, now time.Time) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	switch f := f.(type) {
+	case *DXSpotFrame:
+		key := markerKey{DXSpotFrameType, strings.ToUpper(f.Spot), frequencyKey(f.Frequency)}
+		lifetime := e.config.lifetime(f)
+		if lifetime <= 0 {
+			delete(e.spots, key)
+			return
+		}
+		// the DateTime of a spot may be in the past, e.g. when a log is replayed, so the lifetime starts now
+		e.spots[key] = expiringSpot{
+			call:      f.Spot,
+			frequency: f.Frequency,
+			expires:   now.Add(lifetime),
+		}
+	case *ClearCallFrame:
+		if f.Marker != "" && f.Marker != DXSpotFrameType {
+			return
+		}
+		call := strings.ToUpper(f.Call)
+		frequency := frequencyKey(f.Frequency)
+		for key := range e.spots {
+			if key.call == call && (f.Frequency == 0 || key.frequency == frequency) {
+				delete(e.spots, key)
+			}
+		}
+	}
+}
+
+// Expire removes the expired spots and returns them.
+func (e *spotExpiry) Expire(now time.Time) []expiringSpot {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	var result []expiringSpot
+	for key, spot := range e.spots {
+		if now.After(spot.expires) {
+			result = append(result, spot)
+			delete(e.spots, key)
+		}
+	}
+	return result
+}
+
+func (s *Server) expireSpots() {
+	defer close(s.expiry.stopped)
+	ticker := time.NewTicker(s.expiry.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.expiry.stop:
+			return
+		case <-ticker.C:
+			for _, spot := range s.expiry.Expire(s.now()) {
+				err := s.send(s.clearCallFrame(spot.call, spot.frequency, DXSpotFrameType))
+				if err != nil {
+					s.logger.Warn("cannot remove expired spot", "call", spot.call, "error", err)
+				}
+			}
+		}
+	}
+}
+
+// stopExpiry stops the expiry of the spots. It must be called before the run loop is stopped.
+func (s *Server) stopExpiry() {
+	if s.expiry == nil {
+		return
+	}
+	close(s.expiry.stop)
+	<-s.expiry.stopped
+}
@@ -0,0 +1,53 @@
+package godxmap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpotExpiryStartsWhenTheSpotIsSent(t *testing.T) {
+	expiry := &spotExpiry{
+		config: SpotExpiryConfig{Lifetime: 10 * time.Minute, Bands: map[Band]time.Duration{Band6m: time.Minute}},
+		spots:  make(map[markerKey]expiringSpot),
+	}
+	now := time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC)
+	historical := now.Add(-24 * time.Hour).UnixMilli()
+
+	expiry.Update(&DXSpotFrame{FrameHeader: FrameHeader{DateTime: historical}, Spot: "DL1ABC", Frequency: 14025}, now)
+	expiry.Update(&DXSpotFrame{FrameHeader: FrameHeader{DateTime: historical}, Spot: "DL2XYZ", Frequency: 50150}, now)
+
+	if expired := expiry.Expire(now.Add(time.Second)); len(expired) != 0 {
+		t.Errorf("spots with historical DateTime expired right away: %v", expired)
+	}
+	expired := expiry.Expire(now.Add(2 * time.Minute))
+	if len(expired) != 1 || expired[0].call != "DL2XYZ" {
+		t.Errorf("expected the 6m spot to expire after the lifetime of the band, got %v", expired)
+	}
+
+	// a new spot renews the lifetime
+	expiry.Update(&DXSpotFrame{Spot: "DL1ABC", Frequency: 14025}, now.Add(5*time.Minute))
+	if expired := expiry.Expire(now.Add(11 * time.Minute)); len(expired) != 0 {
+		t.Errorf("renewed spot expired: %v", expired)
+	}
+	expired = expiry.Expire(now.Add(16 * time.Minute))
+	if len(expired) != 1 || expired[0].call != "DL1ABC" {
+		t.Errorf("expected the renewed spot to expire, got %v", expired)
+	}
+}
+
+func TestClearCallEndsTheExpiry(t *testing.T) {
+	expiry := &spotExpiry{
+		config: SpotExpiryConfig{Lifetime: time.Minute},
+		spots:  make(map[markerKey]expiringSpot),
+	}
+	now := time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC)
+
+	expiry.Update(&DXSpotFrame{Spot: "DL1ABC", Frequency: 14025}, now)
+	expiry.Update(&DXSpotFrame{Spot: "DL1ABC", Frequency: 7025}, now)
+	expiry.Update(&ClearCallFrame{Call: "dl1abc", Frequency: 14025}, now)
+
+	expired := expiry.Expire(now.Add(2 * time.Minute))
+	if len(expired) != 1 || expired[0].frequency != 7025 {
+		t.Errorf("expected only the spot on 7025 kHz to expire, got %v", expired)
+	}
+}
@@ -0,0 +1,304 @@
+package godxmap
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Mode categories that can be used in mode filters, in addition to the single modes.
+const (
+	// ModePhone matches SSB and FM.
+	ModePhone Mode = "PHONE"
+	// ModeDigital matches all modes except CW, SSB and FM.
+	ModeDigital Mode = "DIGI"
+)
+
+// BandsParameter is the name of the query parameter that limits the spot and call frames sent to a client
+// to the given comma separated bands, e.g. ws://localhost:8080/?bands=20m,40m. The handshake of a client that
+// requests an unknown band is rejected.
+const BandsParameter = "bands"
+
+// ModesParameter is the name of the query parameter that limits the spot and call frames sent to a client
+// to the given comma separated modes or mode categories, e.g. ws://localhost:8080/?modes=CW,DIGI.
+const ModesParameter = "modes"
+
+// WithBands suppresses all spot and call frames outside of the given bands.
+// Each client can restrict the bands further with the [BandsParameter].
+func WithBands(bands ...Band) Option {
+	return func(s *Server) {
+		s.filter.bands = bandSet(bands)
+	}
+}
+
+// WithModes suppresses all spot and call frames that do not use one of the given modes or mode categories.
+// The mode of spots without explicit mode is inferred from the comments and the band plan.
+// Each client can restrict the modes further with the [ModesParameter].
+func WithModes(modes ...Mode) Option {
+	return func(s *Server) {
+		s.filter.modes = modeSet(modes)
+	}
+}
+
+// SetBands changes the bands of the running server, see [WithBands]. Without bands, all bands are sent.
+func (s *Server) SetBands(bands ...Band) {
+	s.filterLock.Lock()
+	defer s.filterLock.Unlock()
+
+	s.filter.bands = bandSet(bands)
+}
+
+// SetModes changes the modes of the running server, see [WithModes]. Without modes, all modes are sent.
+func (s *Server) SetModes(modes ...Mode) {
+	s.filterLock.Lock()
+	defer s.filterLock.Unlock()
+
+	s.filter.modes = modeSet(modes)
+}
+
+func (s *Server) accepts(f Frame) bool {
+	s.filterLock.RLock()
+	defer s.filterLock.RUnlock()
+
+	return s.filter.accepts(f)
+}
+
+// frameFilter decides which frames are sent, either by the whole server or to a single client.
+type frameFilter struct {
+	bands      map[Band]bool
+	modes      map[Mode]bool
+	frameTypes map[string]bool
+	excluded   map[string]bool
+}
+
+// requestFilter returns the filter that a client requested with the query parameters of the websocket URL.
+// It returns an error if one of the bands is unknown, the client would get the spots of all bands otherwise.
+func requestFilter(query url.Values) (frameFilter, error) {
+	var result frameFilter
+	if bands := query.Get(BandsParameter); bands != "" {
+		parsed, err := parseBands(strings.Split(bands, ","))
+		if err != nil {
+			return frameFilter{}, err
+		}
+		result.bands = bandSet(parsed)
+	}
+	if modes := query.Get(ModesParameter); modes != "" {
+		result.modes = modeSet(parseModes(modes))
+	}
+	return result, nil
+}
+
+// checkRequestFilter rejects the websocket handshake of the clients that request an invalid filter.
+func checkRequestFilter(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := requestFilter(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func bandSet(bands []Band) map[Band]bool {
+	if len(bands) == 0 {
+		return nil
+	}
+	result := make(map[Band]bool, len(bands))
+	for _, band := range bands {
+		result[band] = true
+	}
+	return result
+}
+
+// parseBands parses the given band names. It returns an error that lists the unknown bands.
+func parseBands(names []string) ([]Band, error) {
+	result := make([]Band, 0, len(names))
+	var unknown []string
+	for _, name := range names {
+		band := Band(strings.ToLower(strings.TrimSpace(name)))
+		if !slices.ContainsFunc(bandRanges, func(r bandRange) bool { return r.band == band }) {
+			unknown = append(unknown, name)
+			continue
+		}
+		result = append(result, band)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown bands: %s", strings.Join(unknown, ", "))
+	}
+	return result, nil
+}
+
+func modeSet(modes []Mode) map[Mode]bool {
+	if len(modes) == 0 {
+		return nil
+	}
+	result := make(map[Mode]bool, len(modes))
+	for _, mode := range modes {
+		result[mode] = true
+	}
+	return result
+}
+
+// parseModes parses a comma separated list of modes and mode categories.
+func parseModes(s string) []Mode {
+	var result []Mode
+	for _, name := range strings.Split(s, ",") {
+		mode := Mode(strings.ToUpper(strings.TrimSpace(name)))
+		if mode != NoMode {
+			result = append(result, mode)
+		}
+	}
+	return result
+}
+
+func (ff frameFilter) empty() bool {
+	return len(ff.bands) == 0 && len(ff.modes) == 0 && len(ff.frameTypes) == 0 && len(ff.excluded) == 0
+}
+
+// accepts reports if the given frame passes the filter. Frames that are not related to a band pass the band and mode filters.
+func (ff frameFilter) accepts(f Frame) bool {
+	if len(ff.frameTypes) > 0 && !ff.frameTypes[f.FrameType()] {
+		return false
+	}
+	if ff.excluded[f.FrameType()] {
+		return false
+	}
+	if len(ff.bands) > 0 {
+		if band, ok := bandOfFrame(f); ok && !ff.bands[band] {
+			return false
+		}
+	}
+	if len(ff.modes) > 0 {
+		if mode := modeOfFrame(f); mode != NoMode && !ff.acceptsMode(mode) {
+			return false
+		}
+	}
+	return true
+}
+
+func (ff frameFilter) acceptsMode(mode Mode) bool {
+	if ff.modes[mode] {
+		return true
+	}
+	switch mode {
+	case ModeCW:
+		return false
+	case ModeSSB, ModeFM:
+		return ff.modes[ModePhone]
+	default:
+		return ff.modes[ModeDigital]
+	}
+}
+
+// apply returns the message with all frames that pass the filter. If no frame passes, apply returns false.
+func (ff frameFilter) apply(m message) (message, bool) {
+	if ff.empty() {
+		return m, true
+	}
+	frames := make([]Frame, 0, len(m.frames))
+	for _, f := range m.frames {
+		if ff.accepts(f) {
+			frames = append(frames, f)
+		}
+	}
+	if len(frames) == 0 {
+		return message{}, false
+	}
+	if len(frames) == len(m.frames) {
+		// the unchanged message keeps its cached encoding
+		return m, true
+	}
+	return m.derive(frames), true
+}
+
+// bandOfFrame returns the band of spot and call frames.
+func bandOfFrame(f Frame) (Band, bool) {
+	switch f := f.(type) {
+	case *LoggedCallFrame:
+		if f.Band != "" {
+			return Band(f.Band), true
+		}
+		return BandOf(f.Frequency), true
+	case *DXSpotFrame:
+		return BandOf(f.Frequency), true
+	case *ContestExchangeFrame:
+		if f.Band != "" {
+			return Band(f.Band), true
+		}
+		// without frequency, the exchange belongs to the call on all bands
+		return BandOf(f.Frequency), f.Frequency != 0
+	case *BandOpeningFrame:
+		return Band(f.Band), true
+	case *BandmapFrame:
+		return Band(f.Band), true
+	case *BandmapUpdateFrame:
+		return Band(f.Band), true
+	default:
+		return NoBand, false
+	}
+}
+
+// modeOfFrame returns the mode of spot and call frames, or NoMode if the mode is unknown.
+func modeOfFrame(f Frame) Mode {
+	switch f := f.(type) {
+	case *LoggedCallFrame:
+		return Mode(strings.ToUpper(f.Mode))
+	case *ContestExchangeFrame:
+		return Mode(strings.ToUpper(f.Mode))
+	case *DXSpotFrame:
+		if f.Mode != "" {
+			return Mode(strings.ToUpper(f.Mode))
+		}
+		return inferMode(f.Frequency, f.Comments)
+	default:
+		return NoMode
+	}
+}
+
+// connectionFilter is the filter of a single client. It is changed by the subscriptions of the client while frames are sent.
+type connectionFilter struct {
+	lock   sync.RWMutex
+	filter frameFilter
+}
+
+func (f *connectionFilter) get() frameFilter {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return f.filter
+}
+
+func (f *connectionFilter) set(filter frameFilter) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.filter = filter
+}
+
+// subscriptionFilter returns the filter for the given subscription.
+// It returns an error if one of the bands is unknown, like for the [BandsParameter].
+func subscriptionFilter(subscription *SubscribeFrame) (frameFilter, error) {
+	bands, err := parseBands(subscription.Bands)
+	if err != nil {
+		return frameFilter{}, err
+	}
+	return frameFilter{
+		bands:      bandSet(bands),
+		modes:      modeSet(parseModes(strings.Join(subscription.Modes, ","))),
+		frameTypes: stringSet(subscription.FrameTypes),
+		excluded:   stringSet(subscription.ExcludedFrameTypes),
+	}, nil
+}
+
+func stringSet(values []string) map[string]bool {
+	if len(values) == 0 {
+		return nil
+	}
+	result := make(map[string]bool, len(values))
+	for _, value := range values {
+		result[value] = true
+	}
+	return result
+}
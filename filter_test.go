@@ -0,0 +1,167 @@
+package godxmap_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/ftl/godxmap"
+	"github.com/ftl/godxmap/godxmaptest"
+)
+
+func TestBandsFilter(t *testing.T) {
+	server := godxmaptest.NewServer(t, godxmap.WithBands(godxmap.Band20m, godxmap.Band40m))
+	recorder := godxmaptest.NewRecorder(t, server)
+	bandRecorder := godxmaptest.NewRecorder(t, server, godxmaptest.WithBands(godxmap.Band40m, godxmap.Band80m))
+
+	server.ShowDXSpot("DL1ABC", "W1AW", 3525, "")
+	server.ShowDXSpot("DL2ABC", "W1AW", 14025, "")
+	server.ShowDXSpot("DL3ABC", "W1AW", 7025, "")
+	server.ShowLoggedCall("DL4ABC", 28025)
+	// the gab frame is not filtered, it marks the end of the frames
+	server.ShowGab("W1AW", "", "done")
+
+	if calls := awaitCalls(t, recorder, 3); calls != "DL2ABC,DL3ABC" {
+		t.Errorf("unexpected calls of the server filter: %s", calls)
+	}
+	if calls := awaitCalls(t, bandRecorder, 2); calls != "DL3ABC" {
+		t.Errorf("unexpected calls of the client filter: %s", calls)
+	}
+}
+
+func TestModesFilter(t *testing.T) {
+	server := godxmaptest.NewServer(t, godxmap.WithModes(godxmap.ModeCW, godxmap.ModeDigital))
+	recorder := godxmaptest.NewRecorder(t, server)
+	modeRecorder := godxmaptest.NewRecorder(t, server, godxmaptest.WithModes(godxmap.ModeFT8))
+
+	server.ShowDXSpotMode("DL1ABC", "W1AW", 14025, "", godxmap.ModeCW)
+	server.ShowDXSpotMode("DL2ABC", "W1AW", 14250, "", godxmap.ModeSSB)
+	// the mode is inferred from the comments or the band plan
+	server.ShowDXSpot("DL3ABC", "W1AW", 14074, "FT8 -12 dB")
+	server.ShowDXSpot("DL4ABC", "W1AW", 14200, "")
+	server.ShowDXSpot("DL5ABC", "W1AW", 14200, "rtty test")
+	server.ShowGab("W1AW", "", "done")
+
+	if calls := awaitCalls(t, recorder, 4); calls != "DL1ABC,DL3ABC,DL5ABC" {
+		t.Errorf("unexpected calls of the server filter: %s", calls)
+	}
+	if calls := awaitCalls(t, modeRecorder, 2); calls != "DL3ABC" {
+		t.Errorf("unexpected calls of the client filter: %s", calls)
+	}
+}
+
+func TestChangeFiltersAtRuntime(t *testing.T) {
+	server := godxmaptest.NewServer(t, godxmap.WithBands(godxmap.Band20m), godxmap.WithModes(godxmap.ModeCW))
+	recorder := godxmaptest.NewRecorder(t, server)
+
+	server.SetBands(godxmap.Band40m, godxmap.Band80m)
+	server.SetModes(godxmap.ModePhone)
+	server.ShowDXSpotMode("DL1ABC", "W1AW", 14250, "", godxmap.ModeSSB)
+	server.ShowDXSpotMode("DL2ABC", "W1AW", 7025, "", godxmap.ModeCW)
+	server.ShowDXSpotMode("DL3ABC", "W1AW", 3750, "", godxmap.ModeSSB)
+	server.ShowDXSpotMode("DL4ABC", "W1AW", 7150, "", godxmap.ModeFM)
+	server.ShowGab("W1AW", "", "done")
+	if calls := awaitCalls(t, recorder, 3); calls != "DL3ABC,DL4ABC" {
+		t.Errorf("unexpected calls after the filters changed: %s", calls)
+	}
+
+	// without bands and modes, all frames are sent
+	server.SetBands()
+	server.SetModes()
+	server.ShowDXSpotMode("DL5ABC", "W1AW", 28025, "", godxmap.ModeCW)
+	server.ShowGab("W1AW", "", "done")
+	if calls := awaitCalls(t, recorder, 5); calls != "DL3ABC,DL4ABC,DL5ABC" {
+		t.Errorf("unexpected calls after the filters were removed: %s", calls)
+	}
+}
+
+func TestSubscriptionReplacesTheFilter(t *testing.T) {
+	handled := make(chan struct{}, 1)
+	server := godxmaptest.NewServer(t, godxmap.WithClientFrameHandler(func(godxmap.Frame, godxmap.ClientID, string) {
+		handled <- struct{}{}
+	}))
+	recorder := godxmaptest.NewRecorder(t, server, godxmaptest.WithBands(godxmap.Band20m))
+
+	for _, f := range []godxmap.Frame{
+		&godxmap.SubscribeFrame{FrameHeader: godxmap.FrameHeader{Frame: godxmap.SubscribeFrameType}, Bands: []string{"40m"}},
+		// a subscription with unknown bands is ignored, the filter stays at 40m
+		&godxmap.SubscribeFrame{FrameHeader: godxmap.FrameHeader{Frame: godxmap.SubscribeFrameType}, Bands: []string{"40m", "41m"}},
+		// the frames of a client are handled in order, the subscriptions apply when the gab frame is handled
+		&godxmap.GabFrame{FrameHeader: godxmap.FrameHeader{Frame: godxmap.GabFrameType}, From: "DL1ABC", Message: "hello"},
+	} {
+		err := recorder.Send(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case <-handled:
+	case <-time.After(godxmaptest.DefaultTimeout):
+		t.Fatal("the server did not handle the client frames")
+	}
+
+	server.ShowDXSpot("DL2ABC", "W1AW", 14025, "")
+	server.ShowDXSpot("DL3ABC", "W1AW", 3525, "")
+	server.ShowDXSpot("DL4ABC", "W1AW", 7030, "")
+	server.ShowGab("W1AW", "", "done")
+	if calls := awaitCalls(t, recorder, 2); calls != "DL4ABC" {
+		t.Errorf("unexpected calls after the subscription: %s", calls)
+	}
+}
+
+func TestUnknownBandsAreRejected(t *testing.T) {
+	server := godxmaptest.NewServer(t)
+	_, err := server.Pipe("bands=20m,21m")
+	if err == nil {
+		t.Error("pipe with unknown band was connected")
+	}
+
+	addr := freeAddr(t)
+	server = newServer(addr)
+	defer server.Close()
+	startServer(t, server, addr)
+
+	for _, tc := range []struct {
+		query string
+		valid bool
+	}{
+		{"bands=20m,foo", false},
+		{"bands=", true},
+		{"bands=20M,%2040m", true},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			conn, err := websocket.Dial("ws://"+addr+"/?"+tc.query, "", "http://"+addr+"/")
+			if err == nil {
+				conn.Close()
+			}
+			if tc.valid && err != nil {
+				t.Errorf("valid filter was rejected: %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Error("invalid filter was accepted")
+			}
+		})
+	}
+}
+
+// awaitCalls waits for the given number of frames, the last one must be a gab frame that marks the end of the frames.
+// It returns the calls of the spots and logged calls in the order they were received.
+func awaitCalls(t *testing.T, recorder *godxmaptest.Recorder, count int) string {
+	t.Helper()
+	frames := recorder.Await(count)
+	if len(frames) != count || frames[count-1].FrameType() != godxmap.GabFrameType {
+		t.Fatalf("unexpected frames: %v", frames)
+	}
+	var calls []string
+	for _, f := range frames {
+		switch f := f.(type) {
+		case *godxmap.DXSpotFrame:
+			calls = append(calls, f.Spot)
+		case *godxmap.LoggedCallFrame:
+			calls = append(calls, f.Call)
+		}
+	}
+	return strings.Join(calls, ",")
+}
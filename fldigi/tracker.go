@@ -0,0 +1,189 @@
+// The package fldigi tracks the frequency, the mode and the current callsign of fldigi through its XML-RPC interface
+// and shows them on the map of a [godxmap.Server].
+package fldigi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ftl/godxmap"
+	"github.com/ftl/godxmap/adif"
+)
+
+// DefaultURL is the default address of fldigi's XML-RPC interface.
+const DefaultURL = "http://127.0.0.1:7362/RPC2"
+
+const (
+	defaultInterval = time.Second
+	requestTimeout  = 2 * time.Second
+)
+
+// Tracker polls fldigi and translates its state into frames:
+//   - every change of frequency or mode is shown as station status,
+//   - the callsign in the log fields is shown as partial call, placed at its locator if known,
+//   - every QSO that is appended to the logbook is shown as logged call, if a logbook is configured.
+type Tracker struct {
+	url      string
+	station  string
+	operator string
+	logbook  string
+	server   *godxmap.Server
+	interval time.Duration
+	client   *http.Client
+}
+
+// Option configures a [Tracker] instance.
+type Option func(*Tracker)
+
+// WithInterval sets the polling interval. The default is one second.
+func WithInterval(interval time.Duration) Option {
+	return func(t *Tracker) {
+		t.interval = interval
+	}
+}
+
+// WithOperator sets the operator callsign that is shown in the station status.
+func WithOperator(operator string) Option {
+	return func(t *Tracker) {
+		t.operator = operator
+	}
+}
+
+// WithLogbook watches the given ADIF logbook of fldigi (usually ~/.fldigi/logs/logbook.adif) for logged QSOs.
+// fldigi's XML-RPC interface does not tell when a QSO is logged.
+func WithLogbook(filename string) Option {
+	return func(t *Tracker) {
+		t.logbook = filename
+	}
+}
+
+// NewTracker creates a new tracker for the fldigi instance at the given XML-RPC URL. The status is shown for the given station name.
+// To actually start tracking, use the Run method.
+func NewTracker(url string, station string, server *godxmap.Server, options ...Option) *Tracker {
+	result := &Tracker{
+		url:      url,
+		station:  station,
+		server:   server,
+		interval: defaultInterval,
+		client:   &http.Client{Timeout: requestTimeout},
+	}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+// Run polls fldigi until the given context is done or a request fails.
+func (t *Tracker) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	watcherErr := make(chan error, 1)
+	if t.logbook != "" {
+		go func() {
+			watcherErr <- adif.NewWatcher(t.logbook, t.server).Run(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	var lastFrequency float64
+	var lastMode, lastCall string
+	for {
+		frequencyKHz, mode, err := t.pollStatus(ctx)
+		if err != nil {
+			return err
+		}
+		if frequencyKHz != lastFrequency || mode != lastMode {
+			lastFrequency, lastMode = frequencyKHz, mode
+			err = t.server.ShowStatus(t.station, t.operator, frequencyKHz, mode)
+			if err != nil {
+				t.server.Logger().Warn("cannot show the fldigi status", "station", t.station, "error", err)
+			}
+		}
+
+		call, locator, err := t.pollCall(ctx)
+		if err != nil {
+			return err
+		}
+		if call != lastCall {
+			lastCall = call
+			if call != "" {
+				err = t.server.ShowPartialCallInfo(call, godxmap.CallInfo{Locator: locator})
+				if err != nil {
+					t.server.Logger().Warn("cannot show the fldigi call", "call", call, "error", err)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-watcherErr:
+			return err
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *Tracker) pollStatus(ctx context.Context) (float64, string, error) {
+	frequency, err := t.call(ctx, "main.get_frequency")
+	if err != nil {
+		return 0, "", err
+	}
+	dialHz, err := frequency.float()
+	if err != nil {
+		return 0, "", err
+	}
+
+	// the transmit frequency is the dial frequency plus or minus the audio offset in the waterfall
+	carrier, err := t.call(ctx, "modem.get_carrier")
+	if err != nil {
+		return 0, "", err
+	}
+	carrierHz, err := carrier.float()
+	if err != nil {
+		return 0, "", err
+	}
+	sideband, err := t.call(ctx, "main.get_wf_sideband")
+	if err != nil {
+		return 0, "", err
+	}
+	if sideband.string() == "LSB" {
+		carrierHz = -carrierHz
+	}
+
+	modem, err := t.call(ctx, "modem.get_name")
+	if err != nil {
+		return 0, "", err
+	}
+
+	return (dialHz + carrierHz) / 1000, normalizeMode(modem.string()), nil
+}
+
+func (t *Tracker) pollCall(ctx context.Context) (string, string, error) {
+	call, err := t.call(ctx, "log.get_call")
+	if err != nil {
+		return "", "", err
+	}
+	locator, err := t.call(ctx, "log.get_locator")
+	if err != nil {
+		return "", "", err
+	}
+	return strings.ToUpper(call.string()), strings.ToUpper(locator.string()), nil
+}
+
+// normalizeMode maps the modem names of fldigi to the modes known to godxmap.
+func normalizeMode(modem string) string {
+	modem = strings.ToUpper(modem)
+	switch {
+	case strings.HasPrefix(modem, "BPSK"), strings.HasPrefix(modem, "QPSK"), strings.HasPrefix(modem, "PSK"):
+		return string(godxmap.ModePSK)
+	case strings.HasPrefix(modem, "RTTY"):
+		return string(godxmap.ModeRTTY)
+	default:
+		return modem
+	}
+}
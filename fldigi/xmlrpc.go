@@ -0,0 +1,94 @@
+package fldigi
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// the minimal subset of XML-RPC that is needed to call fldigi's methods without parameters
+
+type methodCall struct {
+	XMLName    xml.Name `xml:"methodCall"`
+	MethodName string   `xml:"methodName"`
+	Params     struct{} `xml:"params"`
+}
+
+type methodResponse struct {
+	Params []struct {
+		Value value `xml:"value"`
+	} `xml:"params>param"`
+	Fault *struct {
+		Value struct {
+			Members []struct {
+				Name  string `xml:"name"`
+				Value value  `xml:"value"`
+			} `xml:"struct>member"`
+		} `xml:"value"`
+	} `xml:"fault"`
+}
+
+type value struct {
+	String  *string `xml:"string"`
+	Double  *string `xml:"double"`
+	Int     *string `xml:"int"`
+	I4      *string `xml:"i4"`
+	Boolean *string `xml:"boolean"`
+	Text    string  `xml:",chardata"`
+}
+
+func (v value) string() string {
+	for _, typed := range []*string{v.String, v.Double, v.Int, v.I4, v.Boolean} {
+		if typed != nil {
+			return strings.TrimSpace(*typed)
+		}
+	}
+	// values without type are strings
+	return strings.TrimSpace(v.Text)
+}
+
+func (v value) float() (float64, error) {
+	return strconv.ParseFloat(v.string(), 64)
+}
+
+func (t *Tracker) call(ctx context.Context, method string) (value, error) {
+	body, err := xml.Marshal(methodCall{MethodName: method})
+	if err != nil {
+		return value{}, err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(append([]byte(xml.Header), body...)))
+	if err != nil {
+		return value{}, err
+	}
+	request.Header.Set("Content-Type", "text/xml")
+	response, err := t.client.Do(request)
+	if err != nil {
+		return value{}, fmt.Errorf("cannot call %s: %v", method, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return value{}, fmt.Errorf("cannot call %s: %s", method, response.Status)
+	}
+
+	var result methodResponse
+	err = xml.NewDecoder(response.Body).Decode(&result)
+	if err != nil {
+		return value{}, fmt.Errorf("invalid response to %s: %v", method, err)
+	}
+	if result.Fault != nil {
+		for _, member := range result.Fault.Value.Members {
+			if member.Name == "faultString" {
+				return value{}, fmt.Errorf("fldigi reported an error for %s: %s", method, member.Value.string())
+			}
+		}
+		return value{}, fmt.Errorf("fldigi reported an error for %s", method)
+	}
+	if len(result.Params) == 0 {
+		return value{}, fmt.Errorf("empty response to %s", method)
+	}
+	return result.Params[0].Value, nil
+}
@@ -0,0 +1,558 @@
+package godxmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// The types of the frames that are modeled by this package.
+const (
+	LoggedCallFrameType      = "LoggedCall"
+	PartialCallFrameType     = "PartialCall"
+	DXSpotFrameType          = "DXSpot"
+	GabFrameType             = "Gab"
+	BandOpeningFrameType     = "BandOpening"
+	StatusFrameType          = "Status"
+	DeletedCallFrameType     = "DeletedCall"
+	StationInfoFrameType     = "StationInfo"
+	ClearCallFrameType       = "ClearCall"
+	HeadingFrameType         = "Heading"
+	StationQTHFrameType      = "StationQTH"
+	BandmapFrameType         = "Bandmap"
+	BandmapUpdateFrameType   = "BandmapUpdate"
+	ScoreFrameType           = "Score"
+	RateFrameType            = "Rate"
+	ZonesFrameType           = "Zones"
+	GraylineFrameType        = "Grayline"
+	CenterMapFrameType       = "CenterMap"
+	ZoomMapFrameType         = "ZoomMap"
+	ContestExchangeFrameType = "ContestExchange"
+	SatelliteFrameType       = "Satellite"
+	SubscribeFrameType       = "Subscribe"
+)
+
+// Highlight marks a spot or call that should be rendered prominently on the map.
+type Highlight string
+
+// The reasons to highlight a spot or call.
+const (
+	NoHighlight          Highlight = ""
+	HighlightMultiplier  Highlight = "Multiplier"
+	HighlightNewDXCC     Highlight = "NewDXCC"
+	HighlightNewBandSlot Highlight = "NewBandSlot"
+)
+
+// Frame is a single wtSock frame.
+type Frame interface {
+	// FrameType returns the content of the Frame field that identifies the type of the frame.
+	FrameType() string
+	// Header gives access to the fields that are common to all frames.
+	Header() *FrameHeader
+}
+
+// MarkerStyle contains optional hints how the map should render a marker. Empty fields use the default style of the map.
+type MarkerStyle struct {
+	// Color is a CSS color, e.g. "#ff0000" or "red".
+	Color string `json:"Color,omitempty"`
+	// Icon is the name of an icon known to the map.
+	Icon string `json:"Icon,omitempty"`
+	// Label is a text that is shown next to the marker.
+	Label string `json:"Label,omitempty"`
+}
+
+// ExpiringFrame is implemented by all frames that carry an optional time-to-live hint in their TTL field (in seconds).
+// The TTL tells map clients when the marker should fade out. A TTL of zero means no expiry.
+type ExpiringFrame interface {
+	Frame
+	TimeToLive() time.Duration
+	ttl() *int
+}
+
+// FrameHeader contains the fields that are common to all frames.
+type FrameHeader struct {
+	ID         string `json:"ID,omitempty"`
+	Frame      string `json:"Frame"`
+	DateTime   int64  `json:"DateTime"`
+	SourceAddr string `json:"SourceAddr"`
+}
+
+// Header returns the header itself. This way, all frame types that embed a FrameHeader implement the Header method of [Frame].
+func (h *FrameHeader) Header() *FrameHeader {
+	return h
+}
+
+// LoggedCallFrame shows a logged callsign on the map.
+type LoggedCallFrame struct {
+	FrameHeader
+	Call      string       `json:"Call"`
+	Name      string       `json:"Name,omitempty"`
+	Frequency float64      `json:"Frequency"`
+	Band      string       `json:"Band,omitempty"`
+	Mode      string       `json:"Mode,omitempty"`
+	Exchange  string       `json:"Exchange,omitempty"`
+	Operator  string       `json:"Operator,omitempty"`
+	Locator   string       `json:"Locator,omitempty"`
+	Latitude  *float64     `json:"Latitude,omitempty"`
+	Longitude *float64     `json:"Longitude,omitempty"`
+	TTL       int          `json:"TTL,omitempty"`
+	Style     *MarkerStyle `json:"Style,omitempty"`
+}
+
+func (*LoggedCallFrame) FrameType() string { return LoggedCallFrameType }
+
+func (f *LoggedCallFrame) TimeToLive() time.Duration { return time.Duration(f.TTL) * time.Second }
+
+func (f *LoggedCallFrame) ttl() *int { return &f.TTL }
+
+// PartialCallFrame shows the position of a (partially) entered callsign on the map.
+type PartialCallFrame struct {
+	FrameHeader
+	Call      string       `json:"Call"`
+	Name      string       `json:"Name,omitempty"`
+	DXCC      int          `json:"DXCC,omitempty"`
+	Entity    string       `json:"Entity,omitempty"`
+	Continent string       `json:"Continent,omitempty"`
+	Locator   string       `json:"Locator,omitempty"`
+	Latitude  *float64     `json:"Latitude,omitempty"`
+	Longitude *float64     `json:"Longitude,omitempty"`
+	Highlight string       `json:"Highlight,omitempty"`
+	TTL       int          `json:"TTL,omitempty"`
+	Style     *MarkerStyle `json:"Style,omitempty"`
+}
+
+func (*PartialCallFrame) FrameType() string { return PartialCallFrameType }
+
+func (f *PartialCallFrame) TimeToLive() time.Duration { return time.Duration(f.TTL) * time.Second }
+
+func (f *PartialCallFrame) ttl() *int { return &f.TTL }
+
+// DXSpotFrame shows a DX spot on the map.
+type DXSpotFrame struct {
+	FrameHeader
+	Spot      string       `json:"Spot"`
+	Spotter   string       `json:"Spotter"`
+	Frequency float64      `json:"Frequency"`
+	Comments  string       `json:"Comments"`
+	Mode      string       `json:"Mode,omitempty"`
+	Locator   string       `json:"Locator,omitempty"`
+	Latitude  *float64     `json:"Latitude,omitempty"`
+	Longitude *float64     `json:"Longitude,omitempty"`
+	Highlight string       `json:"Highlight,omitempty"`
+	Spotters  int          `json:"Spotters,omitempty"`
+	SNR       *int         `json:"SNR,omitempty"`
+	TTL       int          `json:"TTL,omitempty"`
+	Style     *MarkerStyle `json:"Style,omitempty"`
+}
+
+func (*DXSpotFrame) FrameType() string { return DXSpotFrameType }
+
+func (f *DXSpotFrame) TimeToLive() time.Duration { return time.Duration(f.TTL) * time.Second }
+
+func (f *DXSpotFrame) ttl() *int { return &f.TTL }
+
+// GabFrame displays a gab chat message next to the map.
+type GabFrame struct {
+	FrameHeader
+	From     string   `json:"From"`
+	To       string   `json:"To"`
+	Message  string   `json:"Message"`
+	Priority Priority `json:"Priority,omitempty"`
+}
+
+// Priority allows to render important gab messages differently from chit-chat.
+type Priority string
+
+// The priorities of gab messages.
+const (
+	PriorityNormal Priority = ""
+	PriorityLow    Priority = "Low"
+	PriorityHigh   Priority = "High"
+	PriorityAlert  Priority = "Alert"
+)
+
+func (*GabFrame) FrameType() string { return GabFrameType }
+
+// BandOpeningFrame announces a detected band opening, see [WithBandOpeningDetection].
+type BandOpeningFrame struct {
+	FrameHeader
+	Band   string `json:"Band"`
+	Region string `json:"Region"`
+	Spots  int    `json:"Spots"`
+	// Since is the time of the first spot of the opening in Unix milliseconds.
+	Since int64 `json:"Since"`
+}
+
+func (*BandOpeningFrame) FrameType() string { return BandOpeningFrameType }
+
+// StatusFrame reports the current status of a station in the network.
+type StatusFrame struct {
+	FrameHeader
+	Station   string  `json:"Station"`
+	Operator  string  `json:"Operator"`
+	Frequency float64 `json:"Frequency"`
+	Mode      string  `json:"Mode"`
+}
+
+func (*StatusFrame) FrameType() string { return StatusFrameType }
+
+// DeletedCallFrame reports that a QSO with the given callsign was deleted from the log.
+type DeletedCallFrame struct {
+	FrameHeader
+	Call      string  `json:"Call"`
+	Frequency float64 `json:"Frequency"`
+}
+
+func (*DeletedCallFrame) FrameType() string { return DeletedCallFrameType }
+
+// StationInfoFrame describes a station in the network.
+type StationInfoFrame struct {
+	FrameHeader
+	Station  string `json:"Station"`
+	Call     string `json:"Call"`
+	Operator string `json:"Operator"`
+}
+
+func (*StationInfoFrame) FrameType() string { return StationInfoFrameType }
+
+// ClearCallFrame removes a previously shown marker from the map.
+type ClearCallFrame struct {
+	FrameHeader
+	Call string `json:"Call"`
+	// Frequency restricts the removal to the marker on the given frequency. If zero, the markers on all frequencies are removed.
+	Frequency float64 `json:"Frequency,omitempty"`
+	// Marker restricts the removal to markers of the given frame type, e.g. "PartialCall". If empty, all markers are removed.
+	Marker string `json:"Marker,omitempty"`
+}
+
+func (*ClearCallFrame) FrameType() string { return ClearCallFrameType }
+
+// HeadingFrame shows the current beam heading of the antenna on the map.
+type HeadingFrame struct {
+	FrameHeader
+	// Azimuth in degrees, clockwise from true north.
+	Azimuth  float64 `json:"Azimuth"`
+	LongPath bool    `json:"LongPath"`
+}
+
+func (*HeadingFrame) FrameType() string { return HeadingFrameType }
+
+// StationQTHFrame tells the map where the own station is located, either by Maidenhead locator or by coordinates.
+// The map uses this location as origin for great-circle paths and bearings.
+type StationQTHFrame struct {
+	FrameHeader
+	Call      string   `json:"Call"`
+	Locator   string   `json:"Locator,omitempty"`
+	Latitude  *float64 `json:"Latitude,omitempty"`
+	Longitude *float64 `json:"Longitude,omitempty"`
+}
+
+func (*StationQTHFrame) FrameType() string { return StationQTHFrameType }
+
+// BandmapEntry is a single entry of a bandmap.
+type BandmapEntry struct {
+	Call      string  `json:"Call"`
+	Frequency float64 `json:"Frequency"`
+	Mode      string  `json:"Mode,omitempty"`
+}
+
+// BandmapFrame contains a full snapshot of the bandmap of one band. It replaces all entries of this band on the map.
+type BandmapFrame struct {
+	FrameHeader
+	Band    string         `json:"Band"`
+	Entries []BandmapEntry `json:"Entries"`
+}
+
+func (*BandmapFrame) FrameType() string { return BandmapFrameType }
+
+// BandmapUpdateFrame contains the changes of the bandmap of one band since the last snapshot or update.
+type BandmapUpdateFrame struct {
+	FrameHeader
+	Band    string         `json:"Band"`
+	Added   []BandmapEntry `json:"Added,omitempty"`
+	Removed []BandmapEntry `json:"Removed,omitempty"`
+}
+
+func (*BandmapUpdateFrame) FrameType() string { return BandmapUpdateFrameType }
+
+// ScoreFrame shows the current contest score in an overlay of the map.
+type ScoreFrame struct {
+	FrameHeader
+	QSOs        int `json:"QSOs"`
+	Points      int `json:"Points"`
+	Multipliers int `json:"Multipliers"`
+	Score       int `json:"Score"`
+}
+
+func (*ScoreFrame) FrameType() string { return ScoreFrameType }
+
+// RateFrame shows the current QSO rates in an overlay of the map. The rates are given in QSOs per hour.
+type RateFrame struct {
+	FrameHeader
+	LastHour      float64 `json:"LastHour"`
+	Last10Minutes float64 `json:"Last10Minutes"`
+}
+
+func (*RateFrame) FrameType() string { return RateFrameType }
+
+// ContestExchange contains the fields of a received contest exchange. Empty fields are not shown.
+type ContestExchange struct {
+	RST    string `json:"RST,omitempty"`
+	Serial int    `json:"Serial,omitempty"`
+	Zone   int    `json:"Zone,omitempty"`
+	// State is the state, province, section or region, depending on the contest.
+	State string `json:"State,omitempty"`
+	Power string `json:"Power,omitempty"`
+	Name  string `json:"Name,omitempty"`
+	// Other contains the remaining fields of the exchange, e.g. the precedence and check in the ARRL Sweepstakes.
+	Other string `json:"Other,omitempty"`
+}
+
+// ContestExchangeFrame attaches a received contest exchange to the marker of a call on the map.
+// If the frequency is given, the exchange is attached to the marker of the call on this frequency.
+type ContestExchangeFrame struct {
+	FrameHeader
+	Call      string  `json:"Call"`
+	Frequency float64 `json:"Frequency,omitempty"`
+	Band      string  `json:"Band,omitempty"`
+	Mode      string  `json:"Mode,omitempty"`
+	ContestExchange
+}
+
+func (*ContestExchangeFrame) FrameType() string { return ContestExchangeFrameType }
+
+// SatellitePoint is a point of the ground track of a satellite.
+type SatellitePoint struct {
+	// Time in Unix milliseconds.
+	Time      int64   `json:"Time"`
+	Latitude  float64 `json:"Latitude"`
+	Longitude float64 `json:"Longitude"`
+	// Altitude in km.
+	Altitude float64 `json:"Altitude,omitempty"`
+}
+
+// SatelliteFrame shows the position, the footprint and the ground track of a satellite as overlay on the map.
+type SatelliteFrame struct {
+	FrameHeader
+	Name string `json:"Name"`
+	// Latitude and Longitude are the current sub-satellite point.
+	Latitude  float64 `json:"Latitude"`
+	Longitude float64 `json:"Longitude"`
+	// Altitude in km.
+	Altitude float64 `json:"Altitude,omitempty"`
+	// FootprintRadius is the radius of the area around the sub-satellite point from where the satellite is visible, in km.
+	FootprintRadius float64          `json:"FootprintRadius,omitempty"`
+	Track           []SatellitePoint `json:"Track,omitempty"`
+	Style           *MarkerStyle     `json:"Style,omitempty"`
+}
+
+func (*SatelliteFrame) FrameType() string { return SatelliteFrameType }
+
+// SubscribeFrame is sent by a client to the server to select the frames it wants to receive, e.g. to reduce the
+// traffic to mobile clients. A subscription replaces the previous one of the client, and the bands and modes replace
+// the ones that the client requested during the handshake, see [BandsParameter] and [ModesParameter].
+// Empty fields do not restrict the frames.
+type SubscribeFrame struct {
+	FrameHeader
+	// FrameTypes are the types of the frames that the client wants to receive.
+	FrameTypes []string `json:"FrameTypes,omitempty"`
+	// ExcludedFrameTypes are the types of the frames that the client does not want to receive.
+	ExcludedFrameTypes []string `json:"ExcludedFrameTypes,omitempty"`
+	// Bands and Modes restrict the spot and call frames, like the [BandsParameter] and the [ModesParameter].
+	// A subscription with unknown bands is ignored.
+	Bands []string `json:"Bands,omitempty"`
+	Modes []string `json:"Modes,omitempty"`
+}
+
+func (*SubscribeFrame) FrameType() string { return SubscribeFrameType }
+
+// ZoneSystem identifies the system of zones that is highlighted on the map.
+type ZoneSystem string
+
+// The zone systems.
+const (
+	CQZones  ZoneSystem = "CQ"
+	ITUZones ZoneSystem = "ITU"
+)
+
+// ZoneState tells if a zone was already worked or is still needed.
+type ZoneState string
+
+// The states of a zone.
+const (
+	ZoneWorked ZoneState = "Worked"
+	ZoneNeeded ZoneState = "Needed"
+)
+
+// ZoneStatus is the state of a single zone on one band. An empty band means all bands.
+type ZoneStatus struct {
+	Zone  int       `json:"Zone"`
+	Band  string    `json:"Band,omitempty"`
+	State ZoneState `json:"State"`
+}
+
+// ZonesFrame highlights CQ or ITU zones on the map.
+type ZonesFrame struct {
+	FrameHeader
+	System ZoneSystem   `json:"System"`
+	Zones  []ZoneStatus `json:"Zones"`
+}
+
+func (*ZonesFrame) FrameType() string { return ZonesFrameType }
+
+// GraylineFrame commands the map to show or hide the grayline overlay.
+type GraylineFrame struct {
+	FrameHeader
+	Visible bool `json:"Visible"`
+}
+
+func (*GraylineFrame) FrameType() string { return GraylineFrameType }
+
+// CenterMapFrame commands the map to center on the given position, Maidenhead locator or callsign.
+// Only one of them should be set.
+type CenterMapFrame struct {
+	FrameHeader
+	Latitude  *float64 `json:"Latitude,omitempty"`
+	Longitude *float64 `json:"Longitude,omitempty"`
+	Locator   string   `json:"Locator,omitempty"`
+	Call      string   `json:"Call,omitempty"`
+}
+
+func (*CenterMapFrame) FrameType() string { return CenterMapFrameType }
+
+// ZoomMapFrame commands the map to set the given zoom level.
+type ZoomMapFrame struct {
+	FrameHeader
+	Zoom int `json:"Zoom"`
+}
+
+func (*ZoomMapFrame) FrameType() string { return ZoomMapFrameType }
+
+// RawFrame is a frame of a type that is not modeled by this package. Its fields are kept in a generic map.
+type RawFrame struct {
+	FrameHeader
+	Fields map[string]any
+}
+
+func (f *RawFrame) FrameType() string { return f.Frame }
+
+// MarshalJSON encodes the header and all fields as one flat JSON object. The header fields take precedence.
+func (f *RawFrame) MarshalJSON() ([]byte, error) {
+	result := make(map[string]any, len(f.Fields)+4)
+	for k, v := range f.Fields {
+		result[k] = v
+	}
+	if f.ID != "" {
+		result["ID"] = f.ID
+	}
+	result["Frame"] = f.Frame
+	result["DateTime"] = f.DateTime
+	result["SourceAddr"] = f.SourceAddr
+	return json.Marshal(result)
+}
+
+// UnmarshalJSON decodes a flat JSON object into the header and the generic fields.
+func (f *RawFrame) UnmarshalJSON(data []byte) error {
+	err := json.Unmarshal(data, &f.FrameHeader)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(data, &f.Fields)
+	if err != nil {
+		return err
+	}
+	for _, k := range []string{"ID", "Frame", "DateTime", "SourceAddr"} {
+		delete(f.Fields, k)
+	}
+	return nil
+}
+
+var frameFactories = map[string]func() Frame{
+	LoggedCallFrameType:      func() Frame { return new(LoggedCallFrame) },
+	PartialCallFrameType:     func() Frame { return new(PartialCallFrame) },
+	DXSpotFrameType:          func() Frame { return new(DXSpotFrame) },
+	GabFrameType:             func() Frame { return new(GabFrame) },
+	BandOpeningFrameType:     func() Frame { return new(BandOpeningFrame) },
+	StatusFrameType:          func() Frame { return new(StatusFrame) },
+	DeletedCallFrameType:     func() Frame { return new(DeletedCallFrame) },
+	StationInfoFrameType:     func() Frame { return new(StationInfoFrame) },
+	ClearCallFrameType:       func() Frame { return new(ClearCallFrame) },
+	HeadingFrameType:         func() Frame { return new(HeadingFrame) },
+	StationQTHFrameType:      func() Frame { return new(StationQTHFrame) },
+	BandmapFrameType:         func() Frame { return new(BandmapFrame) },
+	BandmapUpdateFrameType:   func() Frame { return new(BandmapUpdateFrame) },
+	ScoreFrameType:           func() Frame { return new(ScoreFrame) },
+	RateFrameType:            func() Frame { return new(RateFrame) },
+	ZonesFrameType:           func() Frame { return new(ZonesFrame) },
+	GraylineFrameType:        func() Frame { return new(GraylineFrame) },
+	CenterMapFrameType:       func() Frame { return new(CenterMapFrame) },
+	ZoomMapFrameType:         func() Frame { return new(ZoomMapFrame) },
+	ContestExchangeFrameType: func() Frame { return new(ContestExchangeFrame) },
+	SatelliteFrameType:       func() Frame { return new(SatelliteFrame) },
+	SubscribeFrameType:       func() Frame { return new(SubscribeFrame) },
+}
+
+// DecodeFrame decodes a single wtSock frame as it is sent by the server into the corresponding typed frame struct.
+// Frames of unknown types are decoded into a [RawFrame].
+// It returns an error if the data is not a JSON object or if the mandatory Frame field is missing.
+func DecodeFrame(data []byte) (Frame, error) {
+	var header FrameHeader
+	err := json.Unmarshal(data, &header)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode frame: %v", err)
+	}
+	if header.Frame == "" {
+		return nil, errors.New("cannot decode frame: missing frame type")
+	}
+
+	var result Frame
+	factory, ok := frameFactories[header.Frame]
+	if ok {
+		result = factory()
+	} else {
+		result = new(RawFrame)
+	}
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %s frame: %v", header.Frame, err)
+	}
+	return result, nil
+}
+
+// DecodeMessage decodes a websocket message that contains either a single frame or a batch of frames as JSON array,
+// see [Server.SendBatch]. It reports if the message was a batch.
+func DecodeMessage(data []byte) ([]Frame, bool, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, false, nil
+	}
+	if data[0] != '[' {
+		f, err := DecodeFrame(data)
+		if err != nil {
+			return nil, false, err
+		}
+		return []Frame{f}, false, nil
+	}
+
+	var rawFrames []json.RawMessage
+	err := json.Unmarshal(data, &rawFrames)
+	if err != nil {
+		return nil, true, fmt.Errorf("cannot decode frames: %v", err)
+	}
+	result := make([]Frame, 0, len(rawFrames))
+	for _, rawFrame := range rawFrames {
+		f, err := DecodeFrame(rawFrame)
+		if err != nil {
+			return nil, true, err
+		}
+		result = append(result, f)
+	}
+	return result, true, nil
+}
+
+// EncodeFrame encodes the given frame as JSON object.
+func EncodeFrame(f Frame) ([]byte, error) {
+	return json.Marshal(f)
+}
@@ -0,0 +1,76 @@
+package godxmap
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Frequency is a radio frequency in Hz. Use the constructors [Hz], [KHz] and [MHz] to avoid unit mixups.
+type Frequency float64
+
+// Hz creates a frequency from a value in Hz.
+func Hz(value float64) Frequency {
+	return Frequency(value)
+}
+
+// KHz creates a frequency from a value in kHz.
+func KHz(value float64) Frequency {
+	return Frequency(value * 1e3)
+}
+
+// MHz creates a frequency from a value in MHz.
+func MHz(value float64) Frequency {
+	return Frequency(value * 1e6)
+}
+
+// Hz returns the frequency in Hz.
+func (f Frequency) Hz() float64 {
+	return float64(f)
+}
+
+// KHz returns the frequency in kHz. This is the unit used in wtSock frames.
+func (f Frequency) KHz() float64 {
+	return float64(f) / 1e3
+}
+
+// MHz returns the frequency in MHz.
+func (f Frequency) MHz() float64 {
+	return float64(f) / 1e6
+}
+
+// Band returns the amateur radio band that contains this frequency, or NoBand.
+func (f Frequency) Band() Band {
+	return BandOf(f.KHz())
+}
+
+// Check returns an error if this frequency is outside of the amateur radio bands.
+func (f Frequency) Check() error {
+	if f.Band() == NoBand {
+		return fmt.Errorf("%v is outside of the amateur radio bands", f)
+	}
+	return nil
+}
+
+func (f Frequency) String() string {
+	return strconv.FormatFloat(f.KHz(), 'f', -1, 64) + "kHz"
+}
+
+// ShowLoggedCallFrequency adds information about a logged callsign to the map.
+// It returns an error if the frequency is outside of the amateur radio bands.
+func (s *Server) ShowLoggedCallFrequency(call string, frequency Frequency) error {
+	err := frequency.Check()
+	if err != nil {
+		return err
+	}
+	return s.ShowLoggedCall(call, frequency.KHz())
+}
+
+// ShowDXSpotFrequency adds information about a DX spot to the map.
+// It returns an error if the frequency is outside of the amateur radio bands.
+func (s *Server) ShowDXSpotFrequency(spot string, spotter string, frequency Frequency, comments string) error {
+	err := frequency.Check()
+	if err != nil {
+		return err
+	}
+	return s.ShowDXSpot(spot, spotter, frequency.KHz(), comments)
+}
@@ -0,0 +1,57 @@
+package godxmap_test
+
+import (
+	"testing"
+
+	"github.com/ftl/godxmap"
+)
+
+func TestFrequency(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		frequency godxmap.Frequency
+		hz        float64
+		kHz       float64
+		mHz       float64
+		band      godxmap.Band
+		text      string
+	}{
+		{"Hz", godxmap.Hz(14025000), 14025000, 14025, 14.025, godxmap.Band20m, "14025kHz"},
+		{"kHz", godxmap.KHz(7074.5), 7074500, 7074.5, 7.0745, godxmap.Band40m, "7074.5kHz"},
+		{"MHz", godxmap.MHz(144.3), 144300000, 144300, 144.3, godxmap.Band2m, "144300kHz"},
+		{"lower band edge", godxmap.KHz(1810), 1810000, 1810, 1.81, godxmap.Band160m, "1810kHz"},
+		{"outside the bands", godxmap.MHz(12), 12000000, 12000, 12, godxmap.NoBand, "12000kHz"},
+		{"zero", godxmap.Hz(0), 0, 0, 0, godxmap.NoBand, "0kHz"},
+		{"negative", godxmap.KHz(-14025), -14025000, -14025, -14.025, godxmap.NoBand, "-14025kHz"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if !almostEqual(tc.frequency.Hz(), tc.hz) {
+				t.Errorf("expected %vHz, got %v", tc.hz, tc.frequency.Hz())
+			}
+			if !almostEqual(tc.frequency.KHz(), tc.kHz) {
+				t.Errorf("expected %vkHz, got %v", tc.kHz, tc.frequency.KHz())
+			}
+			if !almostEqual(tc.frequency.MHz(), tc.mHz) {
+				t.Errorf("expected %vMHz, got %v", tc.mHz, tc.frequency.MHz())
+			}
+			if band := tc.frequency.Band(); band != tc.band {
+				t.Errorf("expected band %q, got %q", tc.band, band)
+			}
+			if text := tc.frequency.String(); text != tc.text {
+				t.Errorf("expected %q, got %q", tc.text, text)
+			}
+			err := tc.frequency.Check()
+			if tc.band == godxmap.NoBand && err == nil {
+				t.Error("expected an error for a frequency outside of the bands")
+			}
+			if tc.band != godxmap.NoBand && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func almostEqual(a, b float64) bool {
+	const epsilon = 1e-6
+	return a-b < epsilon && b-a < epsilon
+}
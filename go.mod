@@ -2,4 +2,9 @@ module github.com/ftl/godxmap
 
 go 1.22.3
 
-require golang.org/x/net v0.33.0
+require (
+	github.com/fsnotify/fsnotify v1.9.0
+	golang.org/x/net v0.33.0
+)
+
+require golang.org/x/sys v0.28.0 // indirect
@@ -7,180 +7,1064 @@
 package godxmap
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"math"
 	"net"
 	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
-
-	"golang.org/x/net/websocket"
 )
 
 const (
 	writeTimeout = 100 * time.Millisecond
+	// connectionQueueSize is the number of messages that are queued for a single client.
+	// A client that falls further behind is disconnected.
+	connectionQueueSize = 256
 )
 
-type frame map[string]any
-
 // Server opens a websocket and allows to send wtSock frames to all connected websocket clients.
 type Server struct {
-	addr     string
-	server   *http.Server
-	inbound  chan frame
-	register chan dxmapConnection
-	closed   chan struct{}
+	addr      string
+	source    string
+	logger    *slog.Logger
+	ttl       time.Duration
+	server    *http.Server
+	transport Transport
+	newID     IDGenerator
+	clock     Clock
+	inbound   chan message
+	register  chan dxmapConnection
+	clientIDs atomic.Uint64
+	pipes     atomic.Uint64
+	pressure  chan MemoryPressure
+	ping      chan struct{}
+	closing   chan struct{}
+	closed    chan struct{}
+	listening atomic.Bool
+
+	lifecycle   sync.Mutex
+	closeCalled bool
+	optionErr   error
+
+	openings *openingDetector
+	auditor  Auditor
+	resume   *resumeBuffer
+	history  *historyBuffer
+	state    *mapState
+	expiry   *spotExpiry
+	store    FrameStore
+	dedup    *deduplicator
+	sinks    sinkRegistry
+	sources  sourceRegistry
+	stats    *serverStats
+
+	storeWriter *storeWriter
+
+	connections connectionRegistry
+	debug       *debugRecorder
+
+	aggregator  *aggregator
+	coalescer   *coalescer
+	rateLimiter *rateLimiter
+
+	memoryWatchdog *MemoryWatchdogConfig
+
+	tlsConfig       *tls.Config
+	clientCAs       *x509.CertPool
+	validateToken   TokenValidator
+	validation      ValidationLevel
+	restAPI         bool
+	profiling       bool
+	healthEndpoints bool
+	viewer          bool
+
+	filterLock sync.RWMutex
+	filter     frameFilter
+
+	enrichers         []Enricher
+	handleClientFrame ClientFrameHandler
+	relayedFrames     bool
+	winTestCompatible bool
+
+	middlewareLock sync.RWMutex
+	middleware     []Middleware
+	transformers   map[string][]Transformer
+}
+
+// Option configures a [Server] instance.
+type Option func(*Server)
+
+// WithDefaultTTL sets the time-to-live hint of all spot and call frames that do not define their own TTL.
+// To set the TTL of a single frame, fill in its TTL field and use [Server.Send].
+func WithDefaultTTL(ttl time.Duration) Option {
+	return func(s *Server) {
+		s.ttl = ttl
+	}
+}
+
+// WithSource sets the identity of this server, e.g. a station name, callsign or instance ID, that is sent in the
+// SourceAddr field of all frames. By default, the listening address is used. To set the identity of a single frame,
+// fill in the SourceAddr field of its header and use [Server.Send].
+func WithSource(source string) Option {
+	return func(s *Server) {
+		s.source = source
+	}
+}
+
+// Source returns the identity of this server that is sent in the SourceAddr field of all frames, see [WithSource].
+func (s *Server) Source() string {
+	return s.source
+}
+
+// WithLogger writes the log messages of the server and of the integrations that feed it to the given logger.
+// By default, [slog.Default] is used. To discard all log messages, use slog.New(slog.NewTextHandler(io.Discard, nil)).
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// Logger returns the logger of this server, see [WithLogger].
+func (s *Server) Logger() *slog.Logger {
+	return s.logger
+}
+
+// invalidOption records the error of an invalid option, it is returned by Serve.
+func (s *Server) invalidOption(err error) {
+	if s.optionErr == nil {
+		s.optionErr = err
+	}
 }
 
 // NewServer creates a new server instance for the given listening address. To actually start the server instance, use the Serve method.
-func NewServer(addr string) *Server {
+func NewServer(addr string, options ...Option) *Server {
 	result := &Server{
-		addr:     addr,
-		inbound:  make(chan frame, 1),
-		register: make(chan dxmapConnection, 1),
-		closed:   make(chan struct{}),
+		addr:      addr,
+		source:    addr,
+		logger:    slog.Default(),
+		transport: xnetTransport{},
+		clock:     time.Now,
+		inbound:   make(chan message, 1),
+		register:  make(chan dxmapConnection),
+		pressure:  make(chan MemoryPressure, 1),
+		ping:      make(chan struct{}),
+		closing:   make(chan struct{}),
+		closed:    make(chan struct{}),
+	}
+	for _, option := range options {
+		option(result)
+	}
+	if result.newID == nil {
+		result.newID = func() string { return newULID(result.now()) }
 	}
+	result.stats = newServerStats(result.now())
 
+	result.startStore()
 	go result.run()
+	if result.expiry != nil {
+		go result.expireSpots()
+	}
+	if result.coalescer != nil {
+		go result.coalesceSpots()
+	}
+	if result.memoryWatchdog != nil {
+		go result.watchMemory(*result.memoryWatchdog)
+	}
 
 	return result
 }
 
 // Close the active connections, all active net.Listeners, and stop the server.
+// Close may be called at any time, also before Serve, concurrently with sending frames, or more than once.
+// Afterwards, sending a frame returns [http.ErrServerClosed].
 //
 // Close returns any error returned from closing the [Server]'s underlying Listener(s).
 func (s *Server) Close() error {
-	close(s.inbound)
+	s.lifecycle.Lock()
+	if s.closeCalled {
+		s.lifecycle.Unlock()
+		<-s.closed
+		return nil
+	}
+	s.closeCalled = true
+	server := s.server
+	s.lifecycle.Unlock()
+
+	s.detachAllSources()
+	s.stopExpiry()
+	s.stopCoalescing()
+	close(s.closing)
 	<-s.closed
-	return s.server.Close()
+	if server == nil {
+		return nil
+	}
+	return server.Close()
 }
 
 // Serve starts this server on its dedicated listening address.
 // It accepts incoming websocket connections and will distribute wtSock frames to all connected clients.
 //
 // Serve always returns a non-nil error.
-// After [Server.Shutdown] or [Server.Close], the returned error is [ErrServerClosed].
+// If one of the options of the server is invalid, Serve returns its error right away.
+// After [Server.Close], the returned error is [http.ErrServerClosed].
 func (s *Server) Serve() error {
+	if s.optionErr != nil {
+		return s.optionErr
+	}
 	mux := http.NewServeMux()
-	mux.Handle("/", websocket.Handler(func(conn *websocket.Conn) {
-		s.serveConnection(conn)
-	}))
+	mux.Handle("/", s.authenticate(checkRequestFilter(s.transport.Handler(s.serveConnection, s.logger))))
+	if s.restAPI {
+		mux.Handle(RESTPrefix, s.authenticate(s.auditAdminAction(http.HandlerFunc(s.serveREST))))
+	}
+	if s.debug != nil {
+		mux.Handle(DebugPath, s.authenticate(s.auditAdminAction(http.HandlerFunc(s.serveDebug))))
+	}
+	if s.profiling {
+		mux.Handle(ProfilingPrefix, s.authenticate(s.auditAdminAction(profilingHandler())))
+	}
+	if s.viewer {
+		mux.Handle(ViewerPath, s.authenticate(viewerHandler()))
+	}
+	if s.healthEndpoints {
+		mux.HandleFunc(HealthPath, s.serveHealth)
+		mux.HandleFunc(ReadinessPath, s.serveReadiness)
+	}
 
+	s.lifecycle.Lock()
+	if s.closeCalled {
+		s.lifecycle.Unlock()
+		return http.ErrServerClosed
+	}
+	if s.server != nil {
+		s.lifecycle.Unlock()
+		return errors.New("server is already serving")
+	}
 	listener, err := net.Listen("tcp", s.addr)
 	if err != nil {
+		s.lifecycle.Unlock()
 		return fmt.Errorf("cannot open listener: %v", err)
 	}
-	s.server = &http.Server{
+	if s.tlsConfig != nil {
+		listener = tls.NewListener(listener, s.serverTLSConfig())
+	}
+	server := &http.Server{
 		Handler: mux,
 	}
+	s.server = server
+	s.lifecycle.Unlock()
+
+	s.listening.Store(true)
+	defer s.listening.Store(false)
+	return server.Serve(listener)
+}
 
-	return s.server.Serve(listener)
+func (s *Server) serveConnection(conn TransportConn, r *http.Request) {
+	s.serve(s.newConnection(conn, r))
 }
 
-func (s *Server) serveConnection(conn *websocket.Conn) {
+func (s *Server) newConnection(conn TransportConn, r *http.Request) dxmapConnection {
 	c := newDXMapConnection(conn)
-	s.register <- c
+	c.id = ClientID(s.clientIDs.Add(1))
+	c.resumeAfter = resumeAfter(r)
+	// the filter of a websocket connection was checked during the handshake, the filter of a pipe when it was connected
+	filter, _ := requestFilter(r.URL.Query())
+	c.filter.set(filter)
+	c.winTestCompatible = s.winTestCompatible
+	c.logger = s.logger.With("remote_addr", conn.RemoteAddr())
+	return c
+}
+
+// serve registers the given connection and blocks until it is closed. If the server is closed, the connection is closed immediately.
+func (s *Server) serve(c dxmapConnection) {
+	select {
+	case s.register <- c:
+	case <-s.closing:
+		c.Close()
+		return
+	}
+	s.stats.clientConnected()
+	connectionID := s.connections.add(c, s.now())
+	s.audit(AuditEvent{Type: AuditClientConnected, RemoteAddr: c.conn.RemoteAddr()})
+	go s.readClientFrames(c)
 	c.Serve()
+	s.connections.remove(connectionID)
+	s.stats.clientDisconnected()
+	s.audit(AuditEvent{Type: AuditClientDisconnected, RemoteAddr: c.conn.RemoteAddr()})
 }
 
 func (s *Server) run() {
 	defer close(s.closed)
+	defer s.stopStore()
 
+	var writers sync.WaitGroup
 	outbound := make([]dxmapConnection, 0)
 	for {
 		select {
-		case frame, active := <-s.inbound:
+		case <-s.closing:
+			// deliver the messages that were handed over before the server was closed
+			for len(s.inbound) > 0 {
+				outbound = s.deliver(<-s.inbound, outbound)
+			}
 			for _, c := range outbound {
-				if active {
-					err := c.Send(frame)
-					if err != nil {
-						c.Close()
-					}
-				} else {
-					c.Close()
-				}
+				close(c.queue)
 			}
-			if !active {
-				return
+			writers.Wait()
+			return
+		case m := <-s.inbound:
+			outbound = s.deliver(m, outbound)
+		case <-s.ping:
+		case pressure := <-s.pressure:
+			for _, shedder := range s.loadShedders() {
+				shedder.Shed(pressure)
 			}
 		case c := <-s.register:
+			var backlog []Frame
+			if s.resume != nil && c.resumeAfter != "" {
+				backlog = s.resume.After(c.resumeAfter)
+			} else if s.state != nil {
+				backlog = s.state.Snapshot(s.now())
+			} else if s.history != nil {
+				backlog = s.history.Frames(s.now())
+			}
+			writers.Add(1)
+			go func() {
+				defer writers.Done()
+				s.write(c, backlog)
+			}()
 			outbound = append(outbound, c)
+			close(c.registered)
+		}
+	}
+}
+
+// deliver processes the given message within the run loop and queues it for the given connections.
+// It returns the connections that are still open.
+func (s *Server) deliver(m message, outbound []dxmapConnection) []dxmapConnection {
+	if m.to != 0 {
+		return s.deliverTo(m, outbound)
+	}
+	for _, f := range m.frames {
+		if s.resume != nil {
+			s.resume.Add(f, s.now())
+		}
+		if s.history != nil {
+			s.history.Add(f)
+		}
+		if s.state != nil {
+			s.state.Update(f)
+		}
+		if s.expiry != nil {
+			s.expiry.Update(f, s.now())
+		}
+	}
+	s.publish(m)
+	s.persist(m)
+	s.stats.sent(m)
+	if s.debug != nil {
+		s.debug.record(m)
+	}
+	m = m.encodeOnce()
+	for _, c := range outbound {
+		s.enqueue(c, m)
+	}
+	return slices.DeleteFunc(outbound, dxmapConnection.isClosed)
+}
+
+// deliverTo queues the given message only for the client of the message.
+// The message is not retained. It returns the connections that are still open.
+func (s *Server) deliverTo(m message, outbound []dxmapConnection) []dxmapConnection {
+	for _, c := range outbound {
+		if c.id == m.to {
+			s.stats.sent(m)
+			s.enqueue(c, m)
+		}
+	}
+	return slices.DeleteFunc(outbound, dxmapConnection.isClosed)
+}
+
+// enqueue queues the given message for the given connection. If the client is too slow, it is disconnected.
+func (s *Server) enqueue(c dxmapConnection, m message) {
+	if c.isClosed() {
+		return
+	}
+	select {
+	case c.queue <- m:
+	default:
+		c.logger.Warn("client is too slow, disconnecting", "queued_messages", len(c.queue))
+		s.stats.sendFailed()
+		c.Close()
+	}
+}
+
+// write sends the given backlog and then the queued messages to the given client, until the connection is closed
+// or the queue is closed. Every client has its own writer, so a slow client does not delay the others.
+func (s *Server) write(c dxmapConnection, backlog []Frame) {
+	for _, f := range backlog {
+		if !s.sendTo(c, singleFrame(f)) {
+			return
+		}
+	}
+	for {
+		select {
+		case m, open := <-c.queue:
+			if !open {
+				c.Close()
+				return
+			}
+			if !s.sendTo(c, m) {
+				return
+			}
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+// sendTo sends the given message to the given client and closes the connection if that fails.
+// It reports if the message was sent.
+func (s *Server) sendTo(c dxmapConnection, m message) bool {
+	err := c.Send(m)
+	if err == nil {
+		return true
+	}
+	// a connection that was closed in the meantime did not fail
+	if !c.isClosed() {
+		s.stats.sendFailed()
+	}
+	c.Close()
+	return false
+}
+
+func (s *Server) send(f Frame) error {
+	f, ok, err := s.prepare(f)
+	if err != nil || !ok {
+		return err
+	}
+	if s.coalesce(f) {
+		return nil
+	}
+	_, _, err = s.broadcast(singleFrame(f))
+	return err
+}
+
+// prepare applies the server defaults, the middleware chain and the deduplication to the given frame and validates the result.
+// If the frame was dropped, prepare returns false.
+func (s *Server) prepare(f Frame) (Frame, bool, error) {
+	f, ok, err := s.check(f)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return f, s.admit(f), nil
+}
+
+// check applies the server defaults and the middleware chain to the given frame and validates the result.
+// It does not change the state of the server, except for the statistics. If the middleware dropped the frame, check returns false.
+func (s *Server) check(f Frame) (Frame, bool, error) {
+	if expiring, ok := f.(ExpiringFrame); ok && *expiring.ttl() == 0 {
+		*expiring.ttl() = int(s.ttl.Seconds())
+	}
+	traceFrame(s.logger, f, "frame received")
+	if len(s.enrichers) > 0 {
+		s.enrich(f)
+		traceFrame(s.logger, f, "frame enriched")
+	}
+	original := f
+	f, ok := s.applyMiddleware(f)
+	if !ok {
+		s.stats.drop(1)
+		traceFrame(s.logger, original, "frame dropped", "reason", dropMiddleware)
+		return nil, false, nil
+	}
+	if !s.accepts(f) {
+		s.stats.drop(1)
+		traceFrame(s.logger, f, "frame dropped", "reason", dropFilter)
+		return nil, false, nil
+	}
+	err := ValidateFrame(f, s.validation)
+	if err != nil {
+		traceFrame(s.logger, f, "frame dropped", "reason", dropInvalid, "error", err)
+		return nil, false, err
+	}
+	return f, true, nil
+}
+
+// admit records the given checked frame for the aggregation or the deduplication of the spots.
+// It reports false if the frame is dropped as duplicate.
+func (s *Server) admit(f Frame) bool {
+	if spot, ok := f.(*DXSpotFrame); ok {
+		if s.aggregator != nil {
+			if !s.aggregator.Merge(spot) {
+				traceFrame(s.logger, f, "frame dropped", "reason", dropAggregated)
+				return false
+			}
+		} else if s.dedup != nil && s.dedup.Duplicate(spot.Spot, spot.Frequency, time.UnixMilli(spot.DateTime)) {
+			s.stats.drop(1)
+			traceFrame(s.logger, f, "frame dropped", "reason", dropDuplicate)
+			return false
 		}
 	}
+	traceFrame(s.logger, f, "frame accepted")
+	return true
 }
 
-func (s *Server) send(f frame) {
-	s.inbound <- f
+// Send sends the given frame to all connected clients. Empty header fields are filled in automatically.
+func (s *Server) Send(f Frame) error {
+	s.fillHeader(f)
+	return s.send(f)
+}
+
+// SendTo sends the given frame only to the given client, e.g. as response to a frame that this client sent,
+// see [ClientFrameHandler]. The frame is not retained for other clients, and it is not passed to the sinks or the store.
+// If the client is not connected anymore, the frame is dropped. Empty header fields are filled in automatically.
+func (s *Server) SendTo(client ClientID, f Frame) error {
+	s.fillHeader(f)
+	f, ok, err := s.check(f)
+	if err != nil || !ok {
+		return err
+	}
+	m := singleFrame(f)
+	m.to = client
+	_, _, err = s.broadcast(m)
+	return err
 }
 
 // ShowLoggedCall adds information about a logged callsign to the map.
-func (s *Server) ShowLoggedCall(call string, frequencyKHz float64) {
-	s.send(s.loggedCallFrame(call, frequencyKHz))
+func (s *Server) ShowLoggedCall(call string, frequencyKHz float64) error {
+	return s.send(s.loggedCallFrame(call, frequencyKHz))
+}
+
+// ShowLoggedCallAt adds information about a callsign that was logged at the given time to the map.
+// This is useful to replay a log.
+func (s *Server) ShowLoggedCallAt(t time.Time, call string, frequencyKHz float64) error {
+	f := s.loggedCallFrame(call, frequencyKHz)
+	f.DateTime = t.UnixMilli()
+	return s.send(f)
+}
+
+// QSO describes a logged contact for [Server.ShowLoggedQSO].
+type QSO struct {
+	Call         string
+	FrequencyKHz float64
+	// Band is inferred from the frequency if empty.
+	Band     Band
+	Mode     Mode
+	Exchange string
+	// Operator is the callsign of the operator who worked the QSO, this is useful for multi-op stations.
+	Operator string
+	// Time is the time when the QSO was logged. If zero, the current time is used.
+	Time time.Time
+	// Locator is the Maidenhead locator of the worked station, if known. Invalid locators are ignored.
+	Locator string
+	// ContestExchange is the structured exchange that was received, if known. It is attached to the marker of the call.
+	ContestExchange *ContestExchange
+}
+
+// ShowLoggedQSO adds detailed information about a logged QSO to the map.
+// If the QSO contains a contest exchange, the logged call and the exchange are sent together in one batch.
+func (s *Server) ShowLoggedQSO(qso QSO) error {
+	frames := s.QSOFrames(qso)
+	if len(frames) == 1 {
+		return s.send(frames[0])
+	}
+	return s.SendBatch(frames)
+}
+
+// QSOFrames returns the frames that [Server.ShowLoggedQSO] sends for the given QSO, without sending them,
+// e.g. to play a log back with a [Replayer]. The frames carry the time of the QSO.
+func (s *Server) QSOFrames(qso QSO) []Frame {
+	f := s.loggedQSOFrame(qso)
+	if qso.ContestExchange == nil {
+		return []Frame{f}
+	}
+	exchange := s.contestExchangeFrame(qso.Call, qso.FrequencyKHz, *qso.ContestExchange)
+	exchange.Band = f.Band
+	exchange.Mode = f.Mode
+	exchange.DateTime = f.DateTime
+	return []Frame{f, exchange}
+}
+
+// ShowContestExchange attaches the given received contest exchange to the marker of the call on the given frequency.
+// If the frequency is zero, the exchange is attached to all markers of the call.
+func (s *Server) ShowContestExchange(call string, frequencyKHz float64, exchange ContestExchange) error {
+	return s.send(s.contestExchangeFrame(call, frequencyKHz, exchange))
+}
+
+// ShowStyledLoggedCall adds information about a logged callsign to the map that is rendered with the given style.
+func (s *Server) ShowStyledLoggedCall(call string, frequencyKHz float64, style MarkerStyle) error {
+	f := s.loggedCallFrame(call, frequencyKHz)
+	f.Style = &style
+	return s.send(f)
 }
 
 // ShowPartialCall shows the position of a (partially) entered callsign on the map.
-func (s *Server) ShowPartialCall(call string) {
-	s.send(s.partialCallFrame(call))
+func (s *Server) ShowPartialCall(call string) error {
+	return s.send(s.partialCallFrame(call))
 }
 
 // ShowDXSpot adds information about a DX spot to the map.
-func (s *Server) ShowDXSpot(spot string, spotter string, frequencyKHz float64, comments string) {
-	s.send(s.dxSpotFrame(spot, spotter, frequencyKHz, comments))
+// The mode of the spot is inferred from the comments or, if not mentioned there, from the band plan.
+func (s *Server) ShowDXSpot(spot string, spotter string, frequencyKHz float64, comments string) error {
+	return s.ShowDXSpotMode(spot, spotter, frequencyKHz, comments, inferMode(frequencyKHz, comments))
+}
+
+// ShowDXSpotAt adds information about a DX spot that was spotted at the given time to the map.
+// This is useful to replay spots, e.g. for post-contest analysis.
+func (s *Server) ShowDXSpotAt(t time.Time, spot string, spotter string, frequencyKHz float64, comments string) error {
+	f := s.dxSpotFrame(spot, spotter, frequencyKHz, comments, inferMode(frequencyKHz, comments))
+	f.DateTime = t.UnixMilli()
+	return s.sendDXSpot(f)
+}
+
+// ShowStyledDXSpot adds information about a DX spot to the map that is rendered with the given style.
+// This allows to visually distinguish different spot sources.
+func (s *Server) ShowStyledDXSpot(spot string, spotter string, frequencyKHz float64, comments string, style MarkerStyle) error {
+	f := s.dxSpotFrame(spot, spotter, frequencyKHz, comments, inferMode(frequencyKHz, comments))
+	f.Style = &style
+	return s.sendDXSpot(f)
+}
+
+// ShowDXSpotMode adds information about a DX spot with the given mode to the map.
+func (s *Server) ShowDXSpotMode(spot string, spotter string, frequencyKHz float64, comments string, mode Mode) error {
+	return s.sendDXSpot(s.dxSpotFrame(spot, spotter, frequencyKHz, comments, mode))
+}
+
+// ShowMultiplier adds a DX spot to the map that is highlighted for the given reason, e.g. as needed multiplier.
+// The mode of the spot is inferred like in [Server.ShowDXSpot].
+func (s *Server) ShowMultiplier(spot string, spotter string, frequencyKHz float64, comments string, highlight Highlight) error {
+	f := s.dxSpotFrame(spot, spotter, frequencyKHz, comments, inferMode(frequencyKHz, comments))
+	f.Highlight = string(highlight)
+	return s.sendDXSpot(f)
+}
+
+func (s *Server) sendDXSpot(f *DXSpotFrame) error {
+	prepared, ok, err := s.prepare(f)
+	if err != nil || !ok {
+		return err
+	}
+	if s.coalesce(prepared) {
+		return nil
+	}
+	if _, ok, err := s.broadcast(singleFrame(prepared)); !ok {
+		return err
+	}
+
+	// only spots that were actually sent count for the band openings, not the dropped duplicates
+	if spot, ok := prepared.(*DXSpotFrame); ok {
+		s.detectOpening(spot)
+	}
+	return nil
+}
+
+// ShowPartialMultiplier shows the position of a (partially) entered callsign on the map that is highlighted
+// for the given reason, e.g. as needed multiplier.
+func (s *Server) ShowPartialMultiplier(call string, highlight Highlight) error {
+	f := s.partialCallFrame(call)
+	f.Highlight = string(highlight)
+	return s.send(f)
 }
 
 // ShowGab displays a gab chat message next to the map.
-func (s *Server) ShowGab(from string, to string, message string) {
-	s.send(s.gabFrame(from, to, message))
+func (s *Server) ShowGab(from string, to string, message string) error {
+	return s.send(s.gabFrame(from, to, message))
 }
 
-func (s *Server) loggedCallFrame(call string, frequencyKHz float64) frame {
-	result := s.newFrame("LoggedCall")
-	result["Call"] = call
-	result["Frequency"] = frequencyKHz
-	return result
+// ShowGabPriority displays a gab chat message with the given priority next to the map.
+func (s *Server) ShowGabPriority(from string, to string, message string, priority Priority) error {
+	f := s.gabFrame(from, to, message)
+	f.Priority = priority
+	return s.send(f)
 }
 
-func (s *Server) partialCallFrame(call string) frame {
-	result := s.newFrame("PartialCall")
-	result["Call"] = call
-	return result
+// ShowAlert displays an important message to all operators next to the map, e.g. "rotator stuck!".
+func (s *Server) ShowAlert(from string, message string) error {
+	return s.ShowGabPriority(from, "", message, PriorityAlert)
 }
 
-func (s *Server) dxSpotFrame(spot string, spotter string, frequencyKHz float64, comments string) frame {
-	result := s.newFrame("DXSpot")
-	result["Spot"] = spot
-	result["Spotter"] = spotter
-	result["Frequency"] = frequencyKHz
-	result["Comments"] = comments
-	return result
+// ShowGabAt displays a gab chat message that was sent at the given time next to the map.
+func (s *Server) ShowGabAt(t time.Time, from string, to string, message string) error {
+	f := s.gabFrame(from, to, message)
+	f.DateTime = t.UnixMilli()
+	return s.send(f)
+}
+
+// ClearCall removes all markers of the given callsign from the map.
+func (s *Server) ClearCall(call string) error {
+	return s.send(s.clearCallFrame(call, 0, ""))
+}
+
+// ClearPartialCall removes the marker of a (partially) entered callsign from the map, e.g. when the entry was aborted.
+func (s *Server) ClearPartialCall(call string) error {
+	return s.send(s.clearCallFrame(call, 0, PartialCallFrameType))
+}
+
+// ClearLoggedCall removes the marker of a logged callsign from the map, e.g. when the QSO was deleted from the log.
+func (s *Server) ClearLoggedCall(call string, frequencyKHz float64) error {
+	return s.send(s.clearCallFrame(call, frequencyKHz, LoggedCallFrameType))
+}
+
+// ClearDXSpot removes the marker of a DX spot from the map.
+func (s *Server) ClearDXSpot(spot string, frequencyKHz float64) error {
+	return s.send(s.clearCallFrame(spot, frequencyKHz, DXSpotFrameType))
+}
+
+// ShowHeading shows the current beam heading of the antenna on the map. The azimuth is given in degrees,
+// clockwise from true north. If longPath is true, the antenna points along the long path.
+func (s *Server) ShowHeading(azimuth float64, longPath bool) error {
+	return s.send(s.headingFrame(azimuth, longPath))
+}
+
+// ShowStationLocator tells the map that the own station with the given callsign is located in the given Maidenhead locator.
+func (s *Server) ShowStationLocator(call string, locator string) error {
+	return s.send(s.stationQTHFrame(call, strings.ToUpper(locator), nil, nil))
+}
+
+// ShowStationPosition tells the map that the own station with the given callsign is located at the given coordinates in degrees.
+func (s *Server) ShowStationPosition(call string, latitude float64, longitude float64) error {
+	return s.send(s.stationQTHFrame(call, "", &latitude, &longitude))
+}
+
+// ShowBandmap mirrors the complete bandmap of the given band onto the map. All entries of this band that
+// were sent before are replaced.
+func (s *Server) ShowBandmap(band Band, entries []BandmapEntry) error {
+	return s.send(s.bandmapFrame(band, entries))
+}
+
+// UpdateBandmap sends incremental changes of the bandmap of the given band to the map.
+func (s *Server) UpdateBandmap(band Band, added []BandmapEntry, removed []BandmapEntry) error {
+	return s.send(s.bandmapUpdateFrame(band, added, removed))
+}
+
+// ShowScore shows the current contest score in an overlay of the map.
+func (s *Server) ShowScore(qsos int, points int, multipliers int, score int) error {
+	return s.send(s.scoreFrame(qsos, points, multipliers, score))
+}
+
+// ShowRate shows the current QSO rates (QSOs per hour) of the last hour and the last ten minutes in an overlay of the map.
+func (s *Server) ShowRate(lastHour float64, last10Minutes float64) error {
+	return s.send(s.rateFrame(lastHour, last10Minutes))
+}
+
+// ShowZone highlights the state of a single CQ or ITU zone on the given band. An empty band means all bands.
+func (s *Server) ShowZone(system ZoneSystem, zone int, band Band, state ZoneState) error {
+	return s.send(s.zonesFrame(system, []ZoneStatus{{Zone: zone, Band: string(band), State: state}}))
+}
+
+// ShowZones highlights the state of many CQ or ITU zones at once, e.g. to initialize the map with the current state of the log.
+func (s *Server) ShowZones(system ZoneSystem, zones []ZoneStatus) error {
+	return s.send(s.zonesFrame(system, zones))
 }
 
-func (s *Server) gabFrame(from string, to string, message string) frame {
-	result := s.newFrame("Gab")
-	result["From"] = from
-	result["To"] = to
-	result["Message"] = message
+// ShowGrayline shows or hides the grayline overlay on all connected maps.
+func (s *Server) ShowGrayline(visible bool) error {
+	return s.send(s.graylineFrame(visible))
+}
+
+// CenterOnPosition centers all connected maps on the given coordinates in degrees.
+func (s *Server) CenterOnPosition(latitude float64, longitude float64) error {
+	f := s.centerMapFrame()
+	f.Latitude = &latitude
+	f.Longitude = &longitude
+	return s.send(f)
+}
+
+// CenterOnLocator centers all connected maps on the given Maidenhead locator.
+func (s *Server) CenterOnLocator(locator string) error {
+	f := s.centerMapFrame()
+	f.Locator = strings.ToUpper(locator)
+	return s.send(f)
+}
+
+// CenterOnCall centers all connected maps on the position of the given callsign.
+func (s *Server) CenterOnCall(call string) error {
+	f := s.centerMapFrame()
+	f.Call = call
+	return s.send(f)
+}
+
+// SetZoom sets the zoom level of all connected maps.
+func (s *Server) SetZoom(zoom int) error {
+	return s.send(s.zoomMapFrame(zoom))
+}
+
+// ShowStatus displays the current status of a station in the network.
+func (s *Server) ShowStatus(station string, operator string, frequencyKHz float64, mode string) error {
+	return s.send(s.statusFrame(station, operator, frequencyKHz, mode))
+}
+
+// ShowDeletedCall informs the map that the QSO with the given callsign was deleted from the log.
+func (s *Server) ShowDeletedCall(call string, frequencyKHz float64) error {
+	return s.send(s.deletedCallFrame(call, frequencyKHz))
+}
+
+// ShowStationInfo describes a station in the network, e.g. its callsign and current operator.
+func (s *Server) ShowStationInfo(station string, call string, operator string) error {
+	return s.send(s.stationInfoFrame(station, call, operator))
+}
+
+// SendFrame sends a wtSock frame of the given type with arbitrary fields. This allows to send frame types that are not
+// modeled by this package. The standard fields (Frame, DateTime, SourceAddr) are filled in automatically and cannot be overridden.
+func (s *Server) SendFrame(frameType string, fields map[string]any) error {
+	return s.send(s.rawFrame(frameType, fields))
+}
+
+func (s *Server) rawFrame(frameType string, fields map[string]any) *RawFrame {
+	return &RawFrame{
+		FrameHeader: s.newHeader(frameType),
+		Fields:      fields,
+	}
+}
+
+func (s *Server) loggedCallFrame(call string, frequencyKHz float64) *LoggedCallFrame {
+	return &LoggedCallFrame{
+		FrameHeader: s.newHeader(LoggedCallFrameType),
+		Call:        call,
+		Frequency:   frequencyKHz,
+	}
+}
+
+func (s *Server) loggedQSOFrame(qso QSO) *LoggedCallFrame {
+	band := qso.Band
+	if band == NoBand {
+		band = BandOf(qso.FrequencyKHz)
+	}
+	result := s.loggedCallFrame(qso.Call, qso.FrequencyKHz)
+	result.Band = string(band)
+	result.Mode = string(qso.Mode)
+	result.Exchange = qso.Exchange
+	result.Operator = qso.Operator
+	if locator, latitude, longitude, err := located(qso.Locator); err == nil {
+		result.Locator, result.Latitude, result.Longitude = locator, latitude, longitude
+	}
+	if !qso.Time.IsZero() {
+		result.DateTime = qso.Time.UnixMilli()
+	}
 	return result
 }
 
-func (s *Server) newFrame(frameType string) frame {
-	return frame{
-		"Frame":      frameType,
-		"DateTime":   time.Now().UnixMilli(),
-		"SourceAddr": s.addr,
+func (s *Server) contestExchangeFrame(call string, frequencyKHz float64, exchange ContestExchange) *ContestExchangeFrame {
+	return &ContestExchangeFrame{
+		FrameHeader:     s.newHeader(ContestExchangeFrameType),
+		Call:            call,
+		Frequency:       frequencyKHz,
+		ContestExchange: exchange,
+	}
+}
+
+func (s *Server) partialCallFrame(call string) *PartialCallFrame {
+	return &PartialCallFrame{
+		FrameHeader: s.newHeader(PartialCallFrameType),
+		Call:        call,
+	}
+}
+
+func (s *Server) dxSpotFrame(spot string, spotter string, frequencyKHz float64, comments string, mode Mode) *DXSpotFrame {
+	return &DXSpotFrame{
+		FrameHeader: s.newHeader(DXSpotFrameType),
+		Spot:        spot,
+		Spotter:     spotter,
+		Frequency:   frequencyKHz,
+		Comments:    comments,
+		Mode:        string(mode),
+	}
+}
+
+func (s *Server) gabFrame(from string, to string, message string) *GabFrame {
+	return &GabFrame{
+		FrameHeader: s.newHeader(GabFrameType),
+		From:        from,
+		To:          to,
+		Message:     message,
+	}
+}
+
+func (s *Server) bandOpeningFrame(opening BandOpening) *BandOpeningFrame {
+	return &BandOpeningFrame{
+		FrameHeader: s.newHeader(BandOpeningFrameType),
+		Band:        string(opening.Band),
+		Region:      opening.Region,
+		Spots:       opening.Spots,
+		Since:       opening.Since.UnixMilli(),
+	}
+}
+
+func (s *Server) clearCallFrame(call string, frequencyKHz float64, marker string) *ClearCallFrame {
+	return &ClearCallFrame{
+		FrameHeader: s.newHeader(ClearCallFrameType),
+		Call:        call,
+		Frequency:   frequencyKHz,
+		Marker:      marker,
+	}
+}
+
+func (s *Server) headingFrame(azimuth float64, longPath bool) *HeadingFrame {
+	azimuth = math.Mod(azimuth, 360)
+	if azimuth < 0 {
+		azimuth += 360
+	}
+	return &HeadingFrame{
+		FrameHeader: s.newHeader(HeadingFrameType),
+		Azimuth:     azimuth,
+		LongPath:    longPath,
+	}
+}
+
+func (s *Server) stationQTHFrame(call string, locator string, latitude *float64, longitude *float64) *StationQTHFrame {
+	return &StationQTHFrame{
+		FrameHeader: s.newHeader(StationQTHFrameType),
+		Call:        call,
+		Locator:     locator,
+		Latitude:    latitude,
+		Longitude:   longitude,
+	}
+}
+
+func (s *Server) bandmapFrame(band Band, entries []BandmapEntry) *BandmapFrame {
+	if entries == nil {
+		entries = []BandmapEntry{}
+	}
+	return &BandmapFrame{
+		FrameHeader: s.newHeader(BandmapFrameType),
+		Band:        string(band),
+		Entries:     entries,
+	}
+}
+
+func (s *Server) bandmapUpdateFrame(band Band, added []BandmapEntry, removed []BandmapEntry) *BandmapUpdateFrame {
+	return &BandmapUpdateFrame{
+		FrameHeader: s.newHeader(BandmapUpdateFrameType),
+		Band:        string(band),
+		Added:       added,
+		Removed:     removed,
+	}
+}
+
+func (s *Server) scoreFrame(qsos int, points int, multipliers int, score int) *ScoreFrame {
+	return &ScoreFrame{
+		FrameHeader: s.newHeader(ScoreFrameType),
+		QSOs:        qsos,
+		Points:      points,
+		Multipliers: multipliers,
+		Score:       score,
+	}
+}
+
+func (s *Server) rateFrame(lastHour float64, last10Minutes float64) *RateFrame {
+	return &RateFrame{
+		FrameHeader:   s.newHeader(RateFrameType),
+		LastHour:      lastHour,
+		Last10Minutes: last10Minutes,
+	}
+}
+
+func (s *Server) zonesFrame(system ZoneSystem, zones []ZoneStatus) *ZonesFrame {
+	return &ZonesFrame{
+		FrameHeader: s.newHeader(ZonesFrameType),
+		System:      system,
+		Zones:       zones,
+	}
+}
+
+func (s *Server) graylineFrame(visible bool) *GraylineFrame {
+	return &GraylineFrame{
+		FrameHeader: s.newHeader(GraylineFrameType),
+		Visible:     visible,
+	}
+}
+
+func (s *Server) centerMapFrame() *CenterMapFrame {
+	return &CenterMapFrame{
+		FrameHeader: s.newHeader(CenterMapFrameType),
+	}
+}
+
+func (s *Server) zoomMapFrame(zoom int) *ZoomMapFrame {
+	return &ZoomMapFrame{
+		FrameHeader: s.newHeader(ZoomMapFrameType),
+		Zoom:        zoom,
+	}
+}
+
+func (s *Server) statusFrame(station string, operator string, frequencyKHz float64, mode string) *StatusFrame {
+	return &StatusFrame{
+		FrameHeader: s.newHeader(StatusFrameType),
+		Station:     station,
+		Operator:    operator,
+		Frequency:   frequencyKHz,
+		Mode:        mode,
+	}
+}
+
+func (s *Server) deletedCallFrame(call string, frequencyKHz float64) *DeletedCallFrame {
+	return &DeletedCallFrame{
+		FrameHeader: s.newHeader(DeletedCallFrameType),
+		Call:        call,
+		Frequency:   frequencyKHz,
+	}
+}
+
+func (s *Server) stationInfoFrame(station string, call string, operator string) *StationInfoFrame {
+	return &StationInfoFrame{
+		FrameHeader: s.newHeader(StationInfoFrameType),
+		Station:     station,
+		Call:        call,
+		Operator:    operator,
+	}
+}
+
+func (s *Server) newHeader(frameType string) FrameHeader {
+	return FrameHeader{
+		ID:         s.newID(),
+		Frame:      frameType,
+		DateTime:   s.now().UnixMilli(),
+		SourceAddr: s.source,
+	}
+}
+
+func (s *Server) fillHeader(f Frame) {
+	header := f.Header()
+	if header.ID == "" {
+		header.ID = s.newID()
+	}
+	if header.Frame == "" {
+		header.Frame = f.FrameType()
+	}
+	if header.DateTime == 0 {
+		header.DateTime = s.now().UnixMilli()
+	}
+	if header.SourceAddr == "" {
+		header.SourceAddr = s.source
 	}
 }
 
 type dxmapConnection struct {
-	conn   *websocket.Conn
-	closed chan struct{}
-	frames chan frame
+	id         ClientID
+	conn       TransportConn
+	closed     chan struct{}
+	closeOnce  *sync.Once
+	queue      chan message
+	registered chan struct{}
+
+	resumeAfter       string
+	filter            *connectionFilter
+	winTestCompatible bool
+	logger            *slog.Logger
 }
 
-func newDXMapConnection(conn *websocket.Conn) dxmapConnection {
+func newDXMapConnection(conn TransportConn) dxmapConnection {
 	return dxmapConnection{
-		conn:   conn,
-		closed: make(chan struct{}),
-		frames: make(chan frame, 1),
+		conn:       conn,
+		closed:     make(chan struct{}),
+		closeOnce:  new(sync.Once),
+		queue:      make(chan message, connectionQueueSize),
+		registered: make(chan struct{}),
+		filter:     new(connectionFilter),
 	}
 }
 
@@ -188,20 +1072,26 @@ func (c dxmapConnection) Serve() {
 	<-c.closed
 }
 
-func (c dxmapConnection) Close() error {
+func (c dxmapConnection) isClosed() bool {
 	select {
 	case <-c.closed:
-		return nil
+		return true
 	default:
-		// go on
+		return false
 	}
+}
 
-	err := c.conn.Close()
-	close(c.closed)
+// Close closes the connection. It is called from the run loop, the writer and the reading goroutine of the connection.
+func (c dxmapConnection) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		err = c.conn.Close()
+		close(c.closed)
+	})
 	return err
 }
 
-func (c dxmapConnection) Send(f frame) error {
+func (c dxmapConnection) Send(m message) error {
 	select {
 	case <-c.closed:
 		return nil
@@ -209,17 +1099,42 @@ func (c dxmapConnection) Send(f frame) error {
 		// go on
 	}
 
-	err := c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
-	if err != nil {
-		log.Printf("cannot set write deadline: %v", err)
-		return err
+	filtered, ok := c.filter.get().apply(m)
+	if ok && c.winTestCompatible {
+		filtered, ok = winTestMessage(filtered)
+	}
+	if !ok {
+		traceDelivery(c.logger, m, message{}, nil)
+		return nil
 	}
 
-	err = websocket.JSON.Send(c.conn, f)
+	err := c.write(filtered)
+	traceDelivery(c.logger, m, filtered, err)
 	if err != nil {
-		log.Printf("cannot send frame: %v", err)
+		c.logger.Warn("cannot send message", "message", filtered.String(), "error", err)
 		return err
 	}
 
 	return nil
 }
+
+func (c dxmapConnection) write(m message) error {
+	if !c.winTestCompatible {
+		data, err := m.encode()
+		if err != nil {
+			return err
+		}
+		return c.conn.WriteMessage(data, writeTimeout)
+	}
+	for _, f := range m.frames {
+		data, err := m.encodeCompat(f)
+		if err != nil {
+			return err
+		}
+		err = c.conn.WriteMessage(data, writeTimeout)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package godxmaptest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a clock that only moves when it is told to, for deterministic timestamps in tests:
+//
+//	clock := godxmaptest.NewClock(time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC))
+//	server := godxmaptest.NewServer(t, godxmap.WithClock(clock.Now))
+type Clock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+// NewClock creates a new clock that stands at the given time.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now returns the current time of the clock. It can be used as [godxmap.Clock].
+func (c *Clock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.now
+}
+
+// Advance moves the clock forward by the given duration.
+func (c *Clock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// Set moves the clock to the given time.
+func (c *Clock) Set(now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = now
+}
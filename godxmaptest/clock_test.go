@@ -0,0 +1,53 @@
+package godxmaptest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ftl/godxmap"
+	"github.com/ftl/godxmap/godxmaptest"
+)
+
+func TestClockDrivesTheServer(t *testing.T) {
+	start := time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC)
+	clock := godxmaptest.NewClock(start)
+	server := godxmaptest.NewServer(t, godxmap.WithClock(clock.Now))
+	recorder := godxmaptest.NewRecorder(t, server)
+
+	server.ShowGab("W1AW", "", "first")
+	clock.Advance(time.Minute)
+	server.ShowGab("W1AW", "", "second")
+	later := start.Add(time.Hour)
+	clock.Set(later)
+	server.ShowGab("W1AW", "", "third")
+
+	frames := recorder.Await(3)
+	expected := []time.Time{start, start.Add(time.Minute), later}
+	for i, f := range frames {
+		if actual := time.UnixMilli(f.Header().DateTime); !actual.Equal(expected[i]) {
+			t.Errorf("frame %d: expected DateTime %v, got %v", i, expected[i], actual.UTC())
+		}
+	}
+	if !server.Now().Equal(later) {
+		t.Errorf("expected the server time %v, got %v", later, server.Now())
+	}
+}
+
+func TestClockExpiresTheHistory(t *testing.T) {
+	clock := godxmaptest.NewClock(time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC))
+	server := godxmaptest.NewServer(t, godxmap.WithClock(clock.Now), godxmap.WithHistory(10, time.Hour))
+	live := godxmaptest.NewRecorder(t, server)
+
+	server.ShowGab("W1AW", "", "old")
+	clock.Advance(2 * time.Hour)
+	server.ShowGab("W1AW", "", "new")
+	live.Await(2)
+
+	// a client that connects later gets the history first
+	late := godxmaptest.NewRecorder(t, server)
+	server.ShowGab("W1AW", "", "last")
+	frames := late.Await(2)
+	if len(frames) != 2 || frames[0].(*godxmap.GabFrame).Message != "new" {
+		t.Errorf("the history does not follow the clock of the server: %v", frames)
+	}
+}
@@ -0,0 +1,214 @@
+// The package godxmaptest helps to unit-test applications that embed godxmap: it provides an in-memory
+// [godxmap.Server] and a [Recorder] that captures the frames that the server broadcasts to its clients,
+// without opening any network connection. The recorded frames can be compared against golden files, see [AssertGolden].
+//
+//	func TestShowSpot(t *testing.T) {
+//		server := godxmaptest.NewServer(t)
+//		recorder := godxmaptest.NewRecorder(t, server)
+//
+//		server.ShowDXSpot("DL1ABC", "W1AW", 14025, "CW")
+//
+//		frames := recorder.Await(1)
+//		godxmaptest.AssertGolden(t, "show_spot", frames)
+//	}
+package godxmaptest
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+// DefaultTimeout is the time that [Recorder.Await] waits for the expected frames by default.
+const DefaultTimeout = 5 * time.Second
+
+// ServerAddr is the address of the in-memory servers, it is sent in the SourceAddr field of all frames.
+const ServerAddr = "godxmaptest"
+
+// NewServer creates a server that does not listen on any address. Its clients are connected in memory,
+// see [NewRecorder] and [godxmap.Server.Pipe]. The server is closed when the test and all its subtests complete.
+func NewServer(t testing.TB, options ...godxmap.Option) *godxmap.Server {
+	t.Helper()
+	result := godxmap.NewServer(ServerAddr, options...)
+	t.Cleanup(func() {
+		result.Close()
+	})
+	return result
+}
+
+// Recorder is a map client that captures all frames that it receives from the server for assertions.
+type Recorder struct {
+	t       testing.TB
+	conn    *godxmap.PipeConn
+	timeout time.Duration
+	query   url.Values
+	done    chan struct{}
+
+	lock    sync.Mutex
+	frames  []godxmap.Frame
+	changed chan struct{}
+	err     error
+}
+
+// RecorderOption configures a [Recorder] instance.
+type RecorderOption func(*Recorder)
+
+// WithBands only records the spot and call frames on the given bands, like a map client that uses the [godxmap.BandsParameter].
+func WithBands(bands ...godxmap.Band) RecorderOption {
+	return func(r *Recorder) {
+		r.query.Set(godxmap.BandsParameter, joinStrings(bands))
+	}
+}
+
+// WithModes only records the spot and call frames in the given modes, like a map client that uses the [godxmap.ModesParameter].
+func WithModes(modes ...godxmap.Mode) RecorderOption {
+	return func(r *Recorder) {
+		r.query.Set(godxmap.ModesParameter, joinStrings(modes))
+	}
+}
+
+// WithTimeout sets the time that [Recorder.Await] waits for the expected frames. The default is [DefaultTimeout].
+func WithTimeout(timeout time.Duration) RecorderOption {
+	return func(r *Recorder) {
+		r.timeout = timeout
+	}
+}
+
+// NewRecorder connects a new recorder to the given server. It records all frames that are sent after NewRecorder returns.
+// The recorder is closed when the test and all its subtests complete.
+func NewRecorder(t testing.TB, server *godxmap.Server, options ...RecorderOption) *Recorder {
+	t.Helper()
+	result := &Recorder{
+		t:       t,
+		timeout: DefaultTimeout,
+		query:   make(url.Values),
+		done:    make(chan struct{}),
+		changed: make(chan struct{}),
+	}
+	for _, option := range options {
+		option(result)
+	}
+
+	conn, err := server.Pipe(result.query.Encode())
+	if err != nil {
+		t.Fatalf("cannot connect recorder: %v", err)
+	}
+	result.conn = conn
+	go result.record()
+	t.Cleanup(result.Close)
+
+	return result
+}
+
+func (r *Recorder) record() {
+	defer close(r.done)
+	for {
+		data, err := r.conn.Receive(context.Background())
+		if err != nil {
+			r.stop(err)
+			return
+		}
+		frames, _, err := godxmap.DecodeMessage(data)
+		if err != nil {
+			r.stop(err)
+			return
+		}
+		r.add(frames)
+	}
+}
+
+func (r *Recorder) add(frames []godxmap.Frame) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.frames = append(r.frames, frames...)
+	r.notify()
+}
+
+func (r *Recorder) stop(err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.err = err
+	r.notify()
+}
+
+// notify wakes up all waiting calls of Await. It must be called with the lock held.
+func (r *Recorder) notify() {
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// Frames returns the recorded frames in the order they were received. The frames of a batch are recorded one by one.
+func (r *Recorder) Frames() []godxmap.Frame {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return slices.Clone(r.frames)
+}
+
+// FramesOfType returns the recorded frames of the given type, e.g. [godxmap.DXSpotFrameType].
+func (r *Recorder) FramesOfType(frameType string) []godxmap.Frame {
+	return slices.DeleteFunc(r.Frames(), func(f godxmap.Frame) bool {
+		return f.FrameType() != frameType
+	})
+}
+
+// Await waits until at least the given number of frames is recorded and returns all recorded frames.
+// If the frames are not received in time or the connection is lost, the test fails immediately.
+func (r *Recorder) Await(count int) []godxmap.Frame {
+	r.t.Helper()
+	timeout := time.After(r.timeout)
+	for {
+		r.lock.Lock()
+		frames := slices.Clone(r.frames)
+		err := r.err
+		changed := r.changed
+		r.lock.Unlock()
+
+		if len(frames) >= count {
+			return frames
+		}
+		if err != nil {
+			r.t.Fatalf("recorder received %d of %d frames: %v", len(frames), count, err)
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			r.t.Fatalf("recorder received %d of %d frames within %v", len(frames), count, r.timeout)
+		}
+	}
+}
+
+// Reset discards the recorded frames.
+func (r *Recorder) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.frames = nil
+}
+
+// Send sends the given frame to the server, like a map client sends e.g. a gab message or a subscription.
+func (r *Recorder) Send(f godxmap.Frame) error {
+	return r.conn.Send(f)
+}
+
+// Close disconnects the recorder from the server. The recorded frames are retained.
+func (r *Recorder) Close() {
+	r.conn.Close()
+	<-r.done
+}
+
+func joinStrings[T ~string](values []T) string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = string(value)
+	}
+	return strings.Join(result, ",")
+}
@@ -0,0 +1,188 @@
+package godxmaptest_test
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ftl/godxmap"
+	"github.com/ftl/godxmap/godxmaptest"
+)
+
+func TestRecorderRecordsFrames(t *testing.T) {
+	server := godxmaptest.NewServer(t)
+	recorder := godxmaptest.NewRecorder(t, server)
+
+	err := server.ShowDXSpot("DL1ABC", "W1AW", 14025, "CW")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = server.ShowGab("W1AW", "", "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frames := recorder.Await(2)
+	if len(frames) != 2 {
+		t.Fatalf("expected 2 frames, got %d", len(frames))
+	}
+	spot, ok := frames[0].(*godxmap.DXSpotFrame)
+	if !ok || spot.Spot != "DL1ABC" {
+		t.Errorf("unexpected first frame: %#v", frames[0])
+	}
+	if spot.SourceAddr != godxmaptest.ServerAddr {
+		t.Errorf("expected source address %q, got %q", godxmaptest.ServerAddr, spot.SourceAddr)
+	}
+	gabs := recorder.FramesOfType(godxmap.GabFrameType)
+	if len(gabs) != 1 || gabs[0].(*godxmap.GabFrame).Message != "hello" {
+		t.Errorf("unexpected gab frames: %v", gabs)
+	}
+}
+
+func TestRecorderRecordsBatchesFrameByFrame(t *testing.T) {
+	server := godxmaptest.NewServer(t)
+	recorder := godxmaptest.NewRecorder(t, server)
+
+	err := server.SendBatch([]godxmap.Frame{
+		&godxmap.DXSpotFrame{Spot: "DL1ABC", Spotter: "W1AW", Frequency: 14025},
+		&godxmap.DXSpotFrame{Spot: "DL2XYZ", Spotter: "W1AW", Frequency: 7025},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frames := recorder.Await(2)
+	if len(frames) != 2 {
+		t.Fatalf("expected 2 frames, got %d", len(frames))
+	}
+	if frames[1].(*godxmap.DXSpotFrame).Spot != "DL2XYZ" {
+		t.Errorf("unexpected order of the frames: %v", frames)
+	}
+}
+
+func TestRecorderWithBandsAndModes(t *testing.T) {
+	server := godxmaptest.NewServer(t)
+	bandRecorder := godxmaptest.NewRecorder(t, server, godxmaptest.WithBands(godxmap.Band20m))
+	modeRecorder := godxmaptest.NewRecorder(t, server, godxmaptest.WithModes(godxmap.ModeFT8))
+
+	// the frames of a client are delivered in order, so the filtered frames would have been recorded first
+	server.ShowDXSpotMode("DL1ABC", "W1AW", 7025, "", godxmap.ModeCW)
+	server.ShowDXSpotMode("DL2XYZ", "W1AW", 14025, "", godxmap.ModeCW)
+	server.ShowDXSpotMode("DL3DEF", "W1AW", 14074, "", godxmap.ModeFT8)
+
+	bandFrames := bandRecorder.Await(2)
+	if len(bandFrames) != 2 || bandFrames[0].(*godxmap.DXSpotFrame).Spot != "DL2XYZ" {
+		t.Errorf("unexpected frames on 20m: %v", bandFrames)
+	}
+	modeFrames := modeRecorder.Await(1)
+	if len(modeFrames) != 1 || modeFrames[0].(*godxmap.DXSpotFrame).Spot != "DL3DEF" {
+		t.Errorf("unexpected frames in FT8: %v", modeFrames)
+	}
+}
+
+func TestRecorderReset(t *testing.T) {
+	server := godxmaptest.NewServer(t)
+	recorder := godxmaptest.NewRecorder(t, server)
+
+	server.ShowGab("W1AW", "", "first")
+	recorder.Await(1)
+	recorder.Reset()
+	if frames := recorder.Frames(); len(frames) != 0 {
+		t.Errorf("expected no frames after Reset, got %d", len(frames))
+	}
+
+	server.ShowGab("W1AW", "", "second")
+	frames := recorder.Await(1)
+	if frames[0].(*godxmap.GabFrame).Message != "second" {
+		t.Errorf("unexpected frame after Reset: %v", frames[0])
+	}
+}
+
+func TestRecorderSend(t *testing.T) {
+	received := make(chan godxmap.Frame, 1)
+	server := godxmaptest.NewServer(t, godxmap.WithClientFrameHandler(func(f godxmap.Frame, _ godxmap.ClientID, _ string) {
+		received <- f
+	}))
+	recorder := godxmaptest.NewRecorder(t, server)
+
+	err := recorder.Send(&godxmap.GabFrame{FrameHeader: godxmap.FrameHeader{Frame: godxmap.GabFrameType}, From: "DL1ABC", Message: "sh/dx"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case f := <-received:
+		if gab, ok := f.(*godxmap.GabFrame); !ok || gab.Message != "sh/dx" {
+			t.Errorf("unexpected client frame: %v", f)
+		}
+	case <-time.After(godxmaptest.DefaultTimeout):
+		t.Fatal("the server did not receive the client frame")
+	}
+}
+
+// fatalRecorder records the failures of a test helper without failing the actual test.
+type fatalRecorder struct {
+	testing.TB
+
+	lock    sync.Mutex
+	failure string
+}
+
+func (r *fatalRecorder) Helper() {}
+
+func (r *fatalRecorder) Fatalf(format string, args ...any) {
+	r.lock.Lock()
+	r.failure = format
+	r.lock.Unlock()
+	runtime.Goexit()
+}
+
+func (r *fatalRecorder) Errorf(format string, args ...any) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.failure = format
+}
+
+func (r *fatalRecorder) Failure() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.failure
+}
+
+// run runs the given function like a test function, so it may call Fatalf.
+func (r *fatalRecorder) run(f func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+	<-done
+}
+
+func TestRecorderAwaitTimeout(t *testing.T) {
+	server := godxmaptest.NewServer(t)
+	fatal := &fatalRecorder{TB: t}
+	recorder := godxmaptest.NewRecorder(fatal, server, godxmaptest.WithTimeout(50*time.Millisecond))
+
+	server.ShowGab("W1AW", "", "only one")
+	fatal.run(func() {
+		recorder.Await(2)
+	})
+	if fatal.Failure() == "" {
+		t.Error("Await does not fail if the frames are missing")
+	}
+}
+
+func TestRecorderAwaitAfterClose(t *testing.T) {
+	server := godxmaptest.NewServer(t)
+	fatal := &fatalRecorder{TB: t}
+	recorder := godxmaptest.NewRecorder(fatal, server)
+
+	server.Close()
+	fatal.run(func() {
+		recorder.Await(1)
+	})
+	if fatal.Failure() == "" {
+		t.Error("Await does not fail if the server is closed")
+	}
+}
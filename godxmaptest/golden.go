@@ -0,0 +1,118 @@
+package godxmaptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ftl/godxmap"
+)
+
+// GoldenDir is the directory of the golden files, relative to the directory of the package under test.
+const GoldenDir = "testdata"
+
+var updateGolden = flag.Bool("godxmaptest.update", false, "update the golden files instead of comparing against them")
+
+// MarshalFrames serializes the given frames deterministically as indented JSON array: the fields of each frame
+// are ordered by name, the IDs are replaced by their sequence number in the order of their first occurrence
+// ("1", "2", ...), and the DateTime fields are set to zero. The given frames are not modified.
+func MarshalFrames(frames []godxmap.Frame) ([]byte, error) {
+	ids := make(map[string]string)
+	normalized := make([]map[string]any, 0, len(frames))
+	for _, f := range frames {
+		fields, err := frameFields(f)
+		if err != nil {
+			return nil, err
+		}
+		if id, ok := fields["ID"].(string); ok {
+			if _, known := ids[id]; !known {
+				ids[id] = fmt.Sprint(len(ids) + 1)
+			}
+			fields["ID"] = ids[id]
+		}
+		if _, ok := fields["DateTime"]; ok {
+			fields["DateTime"] = 0
+		}
+		normalized = append(normalized, fields)
+	}
+
+	result, err := json.MarshalIndent(normalized, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(result, '\n'), nil
+}
+
+// frameFields returns the JSON fields of the given frame. The numbers are kept exactly as they are encoded.
+func frameFields(f godxmap.Frame) (map[string]any, error) {
+	data, err := godxmap.EncodeFrame(f)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode %s frame: %v", f.FrameType(), err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var result map[string]any
+	err = decoder.Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode %s frame: %v", f.FrameType(), err)
+	}
+	return result, nil
+}
+
+// AssertGolden compares the given frames, serialized with [MarshalFrames], against the golden file
+// testdata/<name>.golden.json in the directory of the package under test. The test fails if they differ.
+//
+// To create or update the golden files, run the tests with the flag -godxmaptest.update:
+//
+//	go test ./... -godxmaptest.update
+func AssertGolden(t testing.TB, name string, frames []godxmap.Frame) {
+	t.Helper()
+	actual, err := MarshalFrames(frames)
+	if err != nil {
+		t.Fatalf("cannot serialize frames: %v", err)
+	}
+
+	filename := filepath.Join(GoldenDir, name+".golden.json")
+	if *updateGolden {
+		err := os.MkdirAll(filepath.Dir(filename), 0755)
+		if err == nil {
+			err = os.WriteFile(filename, actual, 0644)
+		}
+		if err != nil {
+			t.Fatalf("cannot update golden file: %v", err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("cannot read golden file, use -godxmaptest.update to create it: %v", err)
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("frames differ from %s, use -godxmaptest.update to update it:\n%s", filename, firstDifference(string(expected), string(actual)))
+	}
+}
+
+// firstDifference describes the first line in which the given texts differ.
+func firstDifference(expected string, actual string) string {
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+	for i := range max(len(expectedLines), len(actualLines)) {
+		var expectedLine, actualLine string
+		if i < len(expectedLines) {
+			expectedLine = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			actualLine = actualLines[i]
+		}
+		if expectedLine != actualLine {
+			return fmt.Sprintf("line %d:\n  want: %s\n  got:  %s", i+1, expectedLine, actualLine)
+		}
+	}
+	return ""
+}
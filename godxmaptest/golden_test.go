@@ -0,0 +1,90 @@
+package godxmaptest_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ftl/godxmap"
+	"github.com/ftl/godxmap/godxmaptest"
+)
+
+func TestMarshalFramesIsDeterministic(t *testing.T) {
+	server := godxmaptest.NewServer(t)
+	recorder := godxmaptest.NewRecorder(t, server)
+
+	for range 2 {
+		server.ShowDXSpot("DL1ABC", "W1AW", 14025, "CW")
+		server.ClearDXSpot("DL1ABC", 14025)
+	}
+	frames := recorder.Await(4)
+
+	data, err := godxmaptest.MarshalFrames(frames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields []map[string]any
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, f := range fields {
+		if f["DateTime"] != 0.0 {
+			t.Errorf("frame %d: expected DateTime 0, got %v", i, f["DateTime"])
+		}
+	}
+	if fields[0]["ID"] != "1" || fields[1]["ID"] != "2" || fields[3]["ID"] != "4" {
+		t.Errorf("the IDs are not replaced by their sequence numbers: %v", fields)
+	}
+
+	again, err := godxmaptest.MarshalFrames(frames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(data) {
+		t.Error("the frames are serialized differently the second time")
+	}
+	if frames[0].Header().DateTime == 0 {
+		t.Error("MarshalFrames modifies the given frames")
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	clock := godxmaptest.NewClock(time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC))
+	server := godxmaptest.NewServer(t, godxmap.WithClock(clock.Now))
+	recorder := godxmaptest.NewRecorder(t, server)
+
+	server.ShowDXSpot("DL1ABC", "W1AW", 14025, "CW")
+	server.ShowLoggedCall("K1XYZ", 7025)
+	server.ShowGab("W1AW", "", "hello")
+
+	godxmaptest.AssertGolden(t, "show_frames", recorder.Await(3))
+}
+
+func TestAssertGoldenReportsDifferences(t *testing.T) {
+	server := godxmaptest.NewServer(t)
+	recorder := godxmaptest.NewRecorder(t, server)
+
+	server.ShowDXSpot("DL2XYZ", "W1AW", 14025, "CW")
+	server.ShowLoggedCall("K1XYZ", 7025)
+	server.ShowGab("W1AW", "", "hello")
+
+	fatal := &fatalRecorder{TB: t}
+	frames := recorder.Await(3)
+	fatal.run(func() {
+		godxmaptest.AssertGolden(fatal, "show_frames", frames)
+	})
+	if fatal.Failure() == "" {
+		t.Error("AssertGolden does not report the different frames")
+	}
+}
+
+func TestAssertGoldenWithoutFile(t *testing.T) {
+	fatal := &fatalRecorder{TB: t}
+	fatal.run(func() {
+		godxmaptest.AssertGolden(fatal, "missing", nil)
+	})
+	if fatal.Failure() == "" {
+		t.Error("AssertGolden does not fail without golden file")
+	}
+}
@@ -0,0 +1,247 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        v5.29.3
+// source: godxmap.proto
+
+package grpcapi
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Frame carries a single wtSock frame as JSON object, exactly as it is sent to the websocket clients.
+type Frame struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Json          string                 `protobuf:"bytes,1,opt,name=json,proto3" json:"json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Frame) Reset() {
+	*x = Frame{}
+	mi := &file_godxmap_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Frame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Frame) ProtoMessage() {}
+
+func (x *Frame) ProtoReflect() protoreflect.Message {
+	mi := &file_godxmap_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Frame.ProtoReflect.Descriptor instead.
+func (*Frame) Descriptor() ([]byte, []int) {
+	return file_godxmap_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Frame) GetJson() string {
+	if x != nil {
+		return x.Json
+	}
+	return ""
+}
+
+// InjectSummary tells how many of the injected frames were accepted and rejected.
+type InjectSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accepted      uint64                 `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Rejected      uint64                 `protobuf:"varint,2,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InjectSummary) Reset() {
+	*x = InjectSummary{}
+	mi := &file_godxmap_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InjectSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InjectSummary) ProtoMessage() {}
+
+func (x *InjectSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_godxmap_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InjectSummary.ProtoReflect.Descriptor instead.
+func (*InjectSummary) Descriptor() ([]byte, []int) {
+	return file_godxmap_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *InjectSummary) GetAccepted() uint64 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *InjectSummary) GetRejected() uint64 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+// SubscribeRequest selects the frame types of a subscription. If empty, all frames are streamed.
+type SubscribeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FrameTypes    []string               `protobuf:"bytes,1,rep,name=frame_types,json=frameTypes,proto3" json:"frame_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_godxmap_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_godxmap_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_godxmap_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SubscribeRequest) GetFrameTypes() []string {
+	if x != nil {
+		return x.FrameTypes
+	}
+	return nil
+}
+
+var File_godxmap_proto protoreflect.FileDescriptor
+
+var file_godxmap_proto_rawDesc = string([]byte{
+	0x0a, 0x0d, 0x67, 0x6f, 0x64, 0x78, 0x6d, 0x61, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x0a, 0x67, 0x6f, 0x64, 0x78, 0x6d, 0x61, 0x70, 0x2e, 0x76, 0x31, 0x22, 0x1b, 0x0a, 0x05, 0x46,
+	0x72, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6a, 0x73, 0x6f, 0x6e, 0x22, 0x47, 0x0a, 0x0d, 0x49, 0x6e, 0x6a, 0x65,
+	0x63, 0x74, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x63, 0x63,
+	0x65, 0x70, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x61, 0x63, 0x63,
+	0x65, 0x70, 0x74, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x22, 0x33, 0x0a, 0x10, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x5f, 0x74,
+	0x79, 0x70, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x72, 0x61, 0x6d,
+	0x65, 0x54, 0x79, 0x70, 0x65, 0x73, 0x32, 0x81, 0x01, 0x0a, 0x05, 0x44, 0x58, 0x4d, 0x61, 0x70,
+	0x12, 0x38, 0x0a, 0x06, 0x49, 0x6e, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x11, 0x2e, 0x67, 0x6f, 0x64,
+	0x78, 0x6d, 0x61, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x1a, 0x19, 0x2e,
+	0x67, 0x6f, 0x64, 0x78, 0x6d, 0x61, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x6a, 0x65, 0x63,
+	0x74, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x28, 0x01, 0x12, 0x3e, 0x0a, 0x09, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x1c, 0x2e, 0x67, 0x6f, 0x64, 0x78, 0x6d, 0x61,
+	0x70, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x67, 0x6f, 0x64, 0x78, 0x6d, 0x61, 0x70, 0x2e,
+	0x76, 0x31, 0x2e, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x30, 0x01, 0x42, 0x20, 0x5a, 0x1e, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x66, 0x74, 0x6c, 0x2f, 0x67, 0x6f, 0x64,
+	0x78, 0x6d, 0x61, 0x70, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_godxmap_proto_rawDescOnce sync.Once
+	file_godxmap_proto_rawDescData []byte
+)
+
+func file_godxmap_proto_rawDescGZIP() []byte {
+	file_godxmap_proto_rawDescOnce.Do(func() {
+		file_godxmap_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_godxmap_proto_rawDesc), len(file_godxmap_proto_rawDesc)))
+	})
+	return file_godxmap_proto_rawDescData
+}
+
+var file_godxmap_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_godxmap_proto_goTypes = []any{
+	(*Frame)(nil),            // 0: godxmap.v1.Frame
+	(*InjectSummary)(nil),    // 1: godxmap.v1.InjectSummary
+	(*SubscribeRequest)(nil), // 2: godxmap.v1.SubscribeRequest
+}
+var file_godxmap_proto_depIdxs = []int32{
+	0, // 0: godxmap.v1.DXMap.Inject:input_type -> godxmap.v1.Frame
+	2, // 1: godxmap.v1.DXMap.Subscribe:input_type -> godxmap.v1.SubscribeRequest
+	1, // 2: godxmap.v1.DXMap.Inject:output_type -> godxmap.v1.InjectSummary
+	0, // 3: godxmap.v1.DXMap.Subscribe:output_type -> godxmap.v1.Frame
+	2, // [2:4] is the sub-list for method output_type
+	0, // [0:2] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_godxmap_proto_init() }
+func file_godxmap_proto_init() {
+	if File_godxmap_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_godxmap_proto_rawDesc), len(file_godxmap_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_godxmap_proto_goTypes,
+		DependencyIndexes: file_godxmap_proto_depIdxs,
+		MessageInfos:      file_godxmap_proto_msgTypes,
+	}.Build()
+	File_godxmap_proto = out.File
+	file_godxmap_proto_goTypes = nil
+	file_godxmap_proto_depIdxs = nil
+}
@@ -0,0 +1,163 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             v5.29.3
+// source: godxmap.proto
+
+package grpcapi
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DXMap_Inject_FullMethodName    = "/godxmap.v1.DXMap/Inject"
+	DXMap_Subscribe_FullMethodName = "/godxmap.v1.DXMap/Subscribe"
+)
+
+// DXMapClient is the client API for DXMap service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// DXMap allows to inject frames into godxmap and to consume the frames that are broadcast to the map clients.
+type DXMapClient interface {
+	// Inject streams frames into godxmap. Invalid frames are rejected.
+	Inject(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[Frame, InjectSummary], error)
+	// Subscribe streams all broadcast frames, optionally limited to the given frame types.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Frame], error)
+}
+
+type dXMapClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDXMapClient(cc grpc.ClientConnInterface) DXMapClient {
+	return &dXMapClient{cc}
+}
+
+func (c *dXMapClient) Inject(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[Frame, InjectSummary], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DXMap_ServiceDesc.Streams[0], DXMap_Inject_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Frame, InjectSummary]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DXMap_InjectClient = grpc.ClientStreamingClient[Frame, InjectSummary]
+
+func (c *dXMapClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Frame], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DXMap_ServiceDesc.Streams[1], DXMap_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, Frame]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DXMap_SubscribeClient = grpc.ServerStreamingClient[Frame]
+
+// DXMapServer is the server API for DXMap service.
+// All implementations must embed UnimplementedDXMapServer
+// for forward compatibility.
+//
+// DXMap allows to inject frames into godxmap and to consume the frames that are broadcast to the map clients.
+type DXMapServer interface {
+	// Inject streams frames into godxmap. Invalid frames are rejected.
+	Inject(grpc.ClientStreamingServer[Frame, InjectSummary]) error
+	// Subscribe streams all broadcast frames, optionally limited to the given frame types.
+	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[Frame]) error
+	mustEmbedUnimplementedDXMapServer()
+}
+
+// UnimplementedDXMapServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDXMapServer struct{}
+
+func (UnimplementedDXMapServer) Inject(grpc.ClientStreamingServer[Frame, InjectSummary]) error {
+	return status.Error(codes.Unimplemented, "method Inject not implemented")
+}
+func (UnimplementedDXMapServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[Frame]) error {
+	return status.Error(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedDXMapServer) mustEmbedUnimplementedDXMapServer() {}
+func (UnimplementedDXMapServer) testEmbeddedByValue()               {}
+
+// UnsafeDXMapServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DXMapServer will
+// result in compilation errors.
+type UnsafeDXMapServer interface {
+	mustEmbedUnimplementedDXMapServer()
+}
+
+func RegisterDXMapServer(s grpc.ServiceRegistrar, srv DXMapServer) {
+	// If the following call panics, it indicates UnimplementedDXMapServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DXMap_ServiceDesc, srv)
+}
+
+func _DXMap_Inject_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DXMapServer).Inject(&grpc.GenericServerStream[Frame, InjectSummary]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DXMap_InjectServer = grpc.ClientStreamingServer[Frame, InjectSummary]
+
+func _DXMap_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DXMapServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, Frame]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DXMap_SubscribeServer = grpc.ServerStreamingServer[Frame]
+
+// DXMap_ServiceDesc is the grpc.ServiceDesc for DXMap service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DXMap_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "godxmap.v1.DXMap",
+	HandlerType: (*DXMapServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Inject",
+			Handler:       _DXMap_Inject_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Subscribe",
+			Handler:       _DXMap_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "godxmap.proto",
+}
@@ -0,0 +1,94 @@
+// The package grpcapi provides a gRPC service that allows external processes to stream frames into a [godxmap.Server]
+// and to subscribe to the frames that are broadcast to the map clients. The service is defined in godxmap.proto;
+// the frames are carried as wtSock JSON objects, so clients in any language can use the same frame format as the map.
+//
+// To serve the API:
+//
+//	grpcServer := grpc.NewServer()
+//	grpcapi.RegisterDXMapServer(grpcServer, grpcapi.NewService(server))
+//	grpcServer.Serve(listener)
+package grpcapi
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative godxmap.proto
+
+import (
+	"io"
+
+	"github.com/ftl/godxmap"
+)
+
+const subscriptionBufferSize = 64
+
+// Service implements the DXMap gRPC service for a [godxmap.Server].
+type Service struct {
+	UnimplementedDXMapServer
+	server *godxmap.Server
+}
+
+// NewService creates a new service for the given server.
+func NewService(server *godxmap.Server) *Service {
+	return &Service{server: server}
+}
+
+// Inject implements [DXMapServer].
+func (s *Service) Inject(stream DXMap_InjectServer) error {
+	summary := new(InjectSummary)
+	for {
+		frame, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(summary)
+		}
+		if err != nil {
+			return err
+		}
+
+		f, err := godxmap.DecodeFrame([]byte(frame.GetJson()))
+		if err == nil {
+			err = s.server.Send(f)
+		}
+		if err != nil {
+			s.server.Logger().Warn("cannot inject frame", "error", err)
+			summary.Rejected++
+			continue
+		}
+		summary.Accepted++
+	}
+}
+
+// Subscribe implements [DXMapServer]. If the client cannot keep up with the broadcast frames, frames are dropped.
+func (s *Service) Subscribe(request *SubscribeRequest, stream DXMap_SubscribeServer) error {
+	frameTypes := make(map[string]bool, len(request.GetFrameTypes()))
+	for _, frameType := range request.GetFrameTypes() {
+		frameTypes[frameType] = true
+	}
+
+	frames := make(chan godxmap.Frame, subscriptionBufferSize)
+	unsubscribe := s.server.Subscribe(godxmap.SinkFunc(func(f godxmap.Frame) {
+		if len(frameTypes) > 0 && !frameTypes[f.FrameType()] {
+			return
+		}
+		select {
+		case frames <- f:
+		default:
+			s.server.Logger().Warn("cannot stream frame, subscriber is too slow", "frame_type", f.FrameType(), "frame_id", f.Header().ID)
+		}
+	}))
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case f := <-frames:
+			data, err := godxmap.EncodeFrame(f)
+			if err != nil {
+				s.server.Logger().Warn("cannot encode frame", "frame_type", f.FrameType(), "frame_id", f.Header().ID, "error", err)
+				continue
+			}
+			err = stream.Send(&Frame{Json: string(data)})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package godxmap
+
+import (
+	"net/http"
+	"time"
+)
+
+// The paths of the health endpoints, see [WithHealthEndpoints].
+const (
+	HealthPath    = "/healthz"
+	ReadinessPath = "/readyz"
+)
+
+const healthTimeout = time.Second
+
+// WithHealthEndpoints provides a liveness and a readiness probe on the same address as the websocket, e.g. for a container
+// orchestrator or a reverse proxy:
+//   - /healthz: the broadcast loop of the server is running and responsive
+//   - /readyz: additionally, the listener is bound and accepts connections
+//
+// The endpoints respond with status 200 if the server is healthy or ready, otherwise with status 503.
+// They do not require authentication, as they reveal nothing but the state of the server.
+func WithHealthEndpoints() Option {
+	return func(s *Server) {
+		s.healthEndpoints = true
+	}
+}
+
+// Healthy reports if the broadcast loop of the server is running and responsive.
+func (s *Server) Healthy() bool {
+	timeout := time.NewTimer(healthTimeout)
+	defer timeout.Stop()
+
+	select {
+	case s.ping <- struct{}{}:
+		return true
+	case <-s.closed:
+		return false
+	case <-timeout.C:
+		return false
+	}
+}
+
+// Ready reports if the server accepts connections and its broadcast loop is running.
+func (s *Server) Ready() bool {
+	return s.listening.Load() && s.Healthy()
+}
+
+func (s *Server) serveHealth(w http.ResponseWriter, r *http.Request) {
+	respondProbe(w, s.Healthy())
+}
+
+func (s *Server) serveReadiness(w http.ResponseWriter, r *http.Request) {
+	respondProbe(w, s.Ready())
+}
+
+func respondProbe(w http.ResponseWriter, ok bool) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("unavailable\n"))
+		return
+	}
+	w.Write([]byte("ok\n"))
+}
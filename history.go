@@ -0,0 +1,77 @@
+package godxmap
+
+import "time"
+
+// WithHistory retains the most recently broadcast frames, at most the given number of frames, and replays them to every
+// newly connected client, so a map that is opened in the middle of a contest immediately shows the current picture.
+// If maxAge is greater than zero, only the frames of the given period are replayed.
+//
+// Clients that resume their session with the [ResumeParameter] get the frames of the resume buffer instead, see [WithResume].
+func WithHistory(capacity int, maxAge time.Duration) Option {
+	return func(s *Server) {
+		s.history = &historyBuffer{
+			maxAge: maxAge,
+			frames: make([]Frame, max(1, capacity)),
+		}
+	}
+}
+
+// historyBuffer is a ring buffer of frames. It is only used from within the run loop of the server,
+// it does not need any synchronization.
+type historyBuffer struct {
+	maxAge   time.Duration
+	frames   []Frame
+	start    int
+	count    int
+	pressure MemoryPressure
+}
+
+func (b *historyBuffer) capacity() int {
+	switch b.pressure {
+	case MemoryPressureHigh:
+		return max(1, len(b.frames)/2)
+	case MemoryPressureCritical:
+		return 0
+	default:
+		return len(b.frames)
+	}
+}
+
+func (b *historyBuffer) Add(f Frame) {
+	capacity := b.capacity()
+	if capacity == 0 {
+		return
+	}
+	b.dropOldest(b.count - capacity + 1)
+	b.frames[(b.start+b.count)%len(b.frames)] = f
+	b.count++
+}
+
+func (b *historyBuffer) dropOldest(n int) {
+	for range min(n, b.count) {
+		b.frames[b.start] = nil
+		b.start = (b.start + 1) % len(b.frames)
+		b.count--
+	}
+}
+
+func (b *historyBuffer) Shed(pressure MemoryPressure) {
+	b.pressure = pressure
+	b.dropOldest(b.count - b.capacity())
+}
+
+// Frames returns the buffered frames that are not older than the maximum age, the oldest first.
+func (b *historyBuffer) Frames(now time.Time) []Frame {
+	var oldest int64
+	if b.maxAge > 0 {
+		oldest = now.Add(-b.maxAge).UnixMilli()
+	}
+	result := make([]Frame, 0, b.count)
+	for i := range b.count {
+		f := b.frames[(b.start+i)%len(b.frames)]
+		if f.Header().DateTime >= oldest {
+			result = append(result, f)
+		}
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package godxmap
+
+import (
+	"crypto/rand"
+	"time"
+)
+
+// IDGenerator creates a unique ID for every frame. The ID is sent in the "ID" field of the frame
+// and allows to correlate a frame across the whole processing chain.
+type IDGenerator func() string
+
+// WithIDGenerator uses the given generator to create frame IDs instead of the default [NewULID].
+func WithIDGenerator(generate IDGenerator) Option {
+	return func(s *Server) {
+		s.newID = generate
+	}
+}
+
+const crockfordBase32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULID creates a new ULID (https://github.com/ulid/spec) based on the current time.
+// ULIDs are lexicographically sortable by their creation time.
+func NewULID() string {
+	return newULID(time.Now())
+}
+
+func newULID(t time.Time) string {
+	var data [16]byte
+	timestamp := uint64(t.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		data[i] = byte(timestamp)
+		timestamp >>= 8
+	}
+	_, err := rand.Read(data[6:])
+	if err != nil {
+		panic(err)
+	}
+
+	// 128 bits encoded in 26 characters, 5 bits each, the first character only carries 3 bits
+	var result [26]byte
+	var buffer uint64
+	bits := 2
+	i := 0
+	for _, b := range data {
+		buffer = buffer<<8 | uint64(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			result[i] = crockfordBase32[(buffer>>bits)&0x1f]
+			i++
+		}
+	}
+	return string(result[:])
+}
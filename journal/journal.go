@@ -0,0 +1,263 @@
+// The package journal appends every frame of a [godxmap.Server] as one JSON line to a journal file (NDJSON),
+// creating a machine-readable record of the whole session for later analysis or replay.
+package journal
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+const (
+	queueSize       = 1024
+	rotationTimeFmt = "20060102T150405.000"
+	filePermissions = 0644
+)
+
+// Journal is a [godxmap.Sink] that appends every broadcast frame as JSON line to a file. The file can be rotated
+// by size or by age. If the disk cannot keep up, frames are dropped instead of slowing down the server.
+type Journal struct {
+	filename    string
+	frameTypes  map[string]bool
+	maxSize     int64
+	maxAge      time.Duration
+	maxBackups  int
+	logger      *slog.Logger
+	queue       chan godxmap.Frame
+	file        *os.File
+	writer      *bufio.Writer
+	size        int64
+	fileCreated time.Time
+}
+
+// Option configures a [Journal] instance.
+type Option func(*Journal)
+
+// WithFrameTypes only journals frames of the given types. By default, all frames are journaled.
+func WithFrameTypes(frameTypes ...string) Option {
+	return func(j *Journal) {
+		for _, frameType := range frameTypes {
+			j.frameTypes[frameType] = true
+		}
+	}
+}
+
+// WithMaxSize rotates the journal file when it exceeds the given size in bytes.
+func WithMaxSize(size int64) Option {
+	return func(j *Journal) {
+		j.maxSize = size
+	}
+}
+
+// WithMaxAge rotates the journal file when it is older than the given period, e.g. 24 hours for a daily journal.
+func WithMaxAge(age time.Duration) Option {
+	return func(j *Journal) {
+		j.maxAge = age
+	}
+}
+
+// WithMaxBackups keeps at most the given number of rotated files and removes the oldest ones.
+// By default, all rotated files are kept.
+func WithMaxBackups(backups int) Option {
+	return func(j *Journal) {
+		j.maxBackups = backups
+	}
+}
+
+// WithLogger writes the log messages of the journal to the given logger. By default, [slog.Default] is used.
+func WithLogger(logger *slog.Logger) Option {
+	return func(j *Journal) {
+		j.logger = logger
+	}
+}
+
+// NewJournal creates a new journal that appends to the given file, e.g. "session.ndjson".
+// A rotated file gets the time of the rotation appended to its name, e.g. "session-20251025T120000.000.ndjson".
+// Register the journal with [godxmap.WithSink] and use the Run method to actually write the frames.
+func NewJournal(filename string, options ...Option) *Journal {
+	result := &Journal{
+		filename:   filename,
+		frameTypes: make(map[string]bool),
+		logger:     slog.Default(),
+		queue:      make(chan godxmap.Frame, queueSize),
+	}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+// Publish implements [godxmap.Sink].
+func (j *Journal) Publish(f godxmap.Frame) {
+	if len(j.frameTypes) > 0 && !j.frameTypes[f.FrameType()] {
+		return
+	}
+	select {
+	case j.queue <- f:
+	default:
+		j.logger.Warn("cannot journal frame, queue is full", "file", j.filename, "frame_type", f.FrameType(), "frame_id", f.Header().ID)
+	}
+}
+
+// Run writes the frames to the journal file until the given context is done.
+func (j *Journal) Run(ctx context.Context) error {
+	err := j.open()
+	if err != nil {
+		return err
+	}
+	defer j.close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.drain()
+			return nil
+		case f := <-j.queue:
+			j.write(f)
+			if len(j.queue) == 0 {
+				j.flush()
+			}
+		}
+	}
+}
+
+// drain writes the frames that are still queued.
+func (j *Journal) drain() {
+	for {
+		select {
+		case f := <-j.queue:
+			j.write(f)
+		default:
+			return
+		}
+	}
+}
+
+func (j *Journal) write(f godxmap.Frame) {
+	data, err := godxmap.EncodeFrame(f)
+	if err != nil {
+		j.logger.Warn("cannot encode frame", "frame_type", f.FrameType(), "frame_id", f.Header().ID, "error", err)
+		return
+	}
+	if j.rotationDue(int64(len(data)) + 1) {
+		err = j.rotate()
+		if err != nil {
+			j.logger.Warn("cannot rotate journal", "file", j.filename, "error", err)
+		}
+	}
+	n, err := j.writer.Write(append(data, '\n'))
+	j.size += int64(n)
+	if err != nil {
+		j.logger.Warn("cannot write journal", "file", j.filename, "error", err)
+	}
+}
+
+func (j *Journal) flush() {
+	err := j.writer.Flush()
+	if err != nil {
+		j.logger.Warn("cannot write journal", "file", j.filename, "error", err)
+	}
+}
+
+func (j *Journal) rotationDue(size int64) bool {
+	if j.size == 0 {
+		return false
+	}
+	if j.maxSize > 0 && j.size+size > j.maxSize {
+		return true
+	}
+	return j.maxAge > 0 && time.Since(j.fileCreated) >= j.maxAge
+}
+
+func (j *Journal) open() error {
+	file, err := os.OpenFile(j.filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePermissions)
+	if err != nil {
+		return fmt.Errorf("cannot open journal: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("cannot open journal: %v", err)
+	}
+	j.file = file
+	j.writer = bufio.NewWriter(file)
+	j.size = info.Size()
+	j.fileCreated = time.Now()
+	if j.size > 0 {
+		// an existing journal is continued, its age is measured from its last modification
+		j.fileCreated = info.ModTime()
+	}
+	return nil
+}
+
+func (j *Journal) close() error {
+	err := j.writer.Flush()
+	if err != nil {
+		j.file.Close()
+		return err
+	}
+	return j.file.Close()
+}
+
+func (j *Journal) rotate() error {
+	closeErr := j.close()
+	renameErr := os.Rename(j.filename, j.rotatedFilename(time.Now()))
+	if renameErr == nil {
+		j.removeBackups()
+	}
+	// without rename, go on with the current file
+	return errors.Join(closeErr, renameErr, j.open())
+}
+
+func (j *Journal) splitFilename() (string, string) {
+	ext := filepath.Ext(j.filename)
+	return strings.TrimSuffix(j.filename, ext), ext
+}
+
+func (j *Journal) rotatedFilename(t time.Time) string {
+	base, ext := j.splitFilename()
+	return fmt.Sprintf("%s-%s%s", base, t.UTC().Format(rotationTimeFmt), ext)
+}
+
+// Backups returns the names of the rotated files of this journal, the oldest first.
+func (j *Journal) Backups() ([]string, error) {
+	base, ext := j.splitFilename()
+	result, err := filepath.Glob(fmt.Sprintf("%s-*%s", base, ext))
+	if err != nil {
+		return nil, err
+	}
+	result = slices.DeleteFunc(result, func(name string) bool {
+		timestamp := strings.TrimSuffix(strings.TrimPrefix(name, base+"-"), ext)
+		_, err := time.Parse(rotationTimeFmt, timestamp)
+		return err != nil
+	})
+	slices.Sort(result)
+	return result, nil
+}
+
+func (j *Journal) removeBackups() {
+	if j.maxBackups <= 0 {
+		return
+	}
+	backups, err := j.Backups()
+	if err != nil {
+		j.logger.Warn("cannot list journal backups", "file", j.filename, "error", err)
+		return
+	}
+	for len(backups) > j.maxBackups {
+		err := os.Remove(backups[0])
+		if err != nil {
+			j.logger.Warn("cannot remove journal backup", "file", backups[0], "error", err)
+		}
+		backups = backups[1:]
+	}
+}
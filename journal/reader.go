@@ -0,0 +1,123 @@
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/ftl/godxmap"
+)
+
+const maxLineSize = 1024 * 1024
+
+// journalExtensions are the file extensions of the journals that are loaded from a directory.
+var journalExtensions = []string{".ndjson", ".jsonl"}
+
+// ReadFrames reads the frames of the given NDJSON journal that match the given query, in the order of the journal.
+// The limit of the query is applied to the frames of this journal.
+func ReadFrames(r io.Reader, query godxmap.FrameQuery) ([]godxmap.Frame, error) {
+	var result []godxmap.Frame
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		f, err := godxmap.DecodeFrame(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNumber, err)
+		}
+		if query.Matches(f) {
+			result = append(result, f)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return limit(result, query.Limit), nil
+}
+
+// Load reads the frames that match the given query from the journal in the given file, or from all journals
+// (*.ndjson, *.jsonl) in the given directory, e.g. the current and the rotated files of a [Journal].
+// The frames are ordered by their DateTime.
+func Load(path string, query godxmap.FrameQuery) ([]godxmap.Frame, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load journal: %v", err)
+	}
+	filenames := []string{path}
+	if info.IsDir() {
+		filenames, err = journalFiles(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load journals: %v", err)
+		}
+	}
+
+	var result []godxmap.Frame
+	for _, filename := range filenames {
+		frames, err := loadFile(filename, query)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, frames...)
+	}
+	slices.SortStableFunc(result, func(a, b godxmap.Frame) int {
+		return cmp.Compare(a.Header().DateTime, b.Header().DateTime)
+	})
+	return limit(result, query.Limit), nil
+}
+
+func journalFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, entry := range entries {
+		if entry.IsDir() || !slices.Contains(journalExtensions, filepath.Ext(entry.Name())) {
+			continue
+		}
+		result = append(result, filepath.Join(dir, entry.Name()))
+	}
+	return result, nil
+}
+
+func loadFile(filename string, query godxmap.FrameQuery) ([]godxmap.Frame, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load journal: %v", err)
+	}
+	defer file.Close()
+
+	result, err := ReadFrames(file, query)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load journal %s: %v", filename, err)
+	}
+	return result, nil
+}
+
+// limit returns the most recent frames, if there are more than the given limit.
+func limit(frames []godxmap.Frame, limit int) []godxmap.Frame {
+	if limit <= 0 || len(frames) <= limit {
+		return frames
+	}
+	return frames[len(frames)-limit:]
+}
+
+// NewReplayer loads the frames that match the given query from the journal in the given file or directory, see [Load],
+// and creates a [godxmap.Replayer] that plays them back through the given server.
+func NewReplayer(server *godxmap.Server, path string, query godxmap.FrameQuery, options ...godxmap.ReplayOption) (*godxmap.Replayer, error) {
+	frames, err := Load(path, query)
+	if err != nil {
+		return nil, err
+	}
+	return godxmap.NewReplayer(server, frames, options...), nil
+}
@@ -0,0 +1,175 @@
+// The package js8call connects to the API of JS8Call and shows the heard stations and directed messages
+// on the map of a [godxmap.Server].
+package js8call
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+// The default addresses of the JS8Call API. JS8Call acts as TCP server, but sends its UDP messages to a configured address.
+const (
+	DefaultTCPAddr = "127.0.0.1:2442"
+	DefaultUDPAddr = "127.0.0.1:2242"
+)
+
+// The types of the JS8Call API messages that are shown on the map.
+const (
+	SpotMessageType     = "RX.SPOT"
+	DirectedMessageType = "RX.DIRECTED"
+)
+
+const (
+	dialTimeout = 10 * time.Second
+	// endOfMessage marks the end of a JS8Call message.
+	endOfMessage = "♢"
+)
+
+// Message is a message of the JS8Call API.
+type Message struct {
+	Type   string `json:"type"`
+	Value  string `json:"value"`
+	Params Params `json:"params"`
+}
+
+// Params contains the parameters of the JS8Call API messages that are relevant for the map.
+type Params struct {
+	Call string `json:"CALL"`
+	From string `json:"FROM"`
+	To   string `json:"TO"`
+	Text string `json:"TEXT"`
+	Grid string `json:"GRID"`
+	// Freq is the frequency of the signal in Hz, i.e. the dial frequency plus the offset.
+	Freq int64 `json:"FREQ"`
+	SNR  int   `json:"SNR"`
+}
+
+// Client receives the messages of the JS8Call API and translates them into frames:
+//   - every heard station (RX.SPOT) is shown as partial call, placed at its grid square if known,
+//   - every directed message (RX.DIRECTED) is shown as gab message.
+type Client struct {
+	network string
+	addr    string
+	server  *godxmap.Server
+}
+
+// NewClient creates a new client for the JS8Call API that feeds the given server. The network is either "tcp"
+// to connect to the TCP server of JS8Call, or "udp" to listen for the UDP messages of JS8Call on the given address.
+// To actually receive messages, use the Run method.
+func NewClient(network string, addr string, server *godxmap.Server) *Client {
+	return &Client{
+		network: network,
+		addr:    addr,
+		server:  server,
+	}
+}
+
+// Run receives and processes the JS8Call messages until the connection is closed or the given context is done.
+func (c *Client) Run(ctx context.Context) error {
+	switch c.network {
+	case "tcp":
+		return c.runTCP(ctx)
+	case "udp":
+		return c.runUDP(ctx)
+	default:
+		return fmt.Errorf("unsupported network %s", c.network)
+	}
+}
+
+func (c *Client) runTCP(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return fmt.Errorf("cannot connect to JS8Call %s: %v", c.addr, err)
+	}
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	// the messages are separated by newlines
+	lines := bufio.NewScanner(conn)
+	lines.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lines.Scan() {
+		c.handle(lines.Bytes())
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err := lines.Err(); err != nil {
+		return fmt.Errorf("connection to JS8Call %s lost: %v", c.addr, err)
+	}
+	return fmt.Errorf("connection to JS8Call %s closed", c.addr)
+}
+
+func (c *Client) runUDP(ctx context.Context) error {
+	conn, err := net.ListenPacket("udp", c.addr)
+	if err != nil {
+		return fmt.Errorf("cannot listen for JS8Call messages on %s: %v", c.addr, err)
+	}
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	buffer := make([]byte, 64*1024)
+	for {
+		n, _, err := conn.ReadFrom(buffer)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("cannot receive JS8Call message: %v", err)
+		}
+		c.handle(buffer[:n])
+	}
+}
+
+func (c *Client) handle(data []byte) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return
+	}
+	var message Message
+	err := json.Unmarshal(data, &message)
+	if err != nil {
+		c.server.Logger().Warn("invalid JS8Call message", "error", err)
+		return
+	}
+
+	switch message.Type {
+	case SpotMessageType:
+		err = c.server.ShowPartialCallInfo(message.Params.Call, godxmap.CallInfo{Locator: strings.TrimSpace(message.Params.Grid)})
+	case DirectedMessageType:
+		err = c.server.ShowGab(message.Params.From, message.Params.To, messageText(message))
+	}
+	if err != nil {
+		c.server.Logger().Warn("cannot show JS8Call message", "type", message.Type, "error", err)
+	}
+}
+
+// messageText extracts the plain text of a directed message, e.g. "HELLO" from "K1ABC: W9XYZ HELLO ♢".
+func messageText(message Message) string {
+	text := message.Params.Text
+	if text == "" {
+		text = message.Value
+	}
+	text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), endOfMessage))
+	if from, rest, found := strings.Cut(text, ":"); found && strings.EqualFold(strings.TrimSpace(from), message.Params.From) {
+		text = strings.TrimSpace(rest)
+	}
+	if to, rest, found := strings.Cut(text, " "); found && strings.EqualFold(to, message.Params.To) {
+		text = strings.TrimSpace(rest)
+	}
+	return text
+}
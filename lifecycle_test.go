@@ -0,0 +1,297 @@
+package godxmap_test
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/ftl/godxmap"
+)
+
+const lifecycleTimeout = 5 * time.Second
+
+// newServer creates a server that does not log, so the expected warnings during shutdown do not clutter the test output.
+func newServer(addr string) *godxmap.Server {
+	return godxmap.NewServer(addr, godxmap.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+}
+
+// freeAddr returns a local address that is free to listen on.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+// startServer serves the given server in the background and waits until it accepts connections.
+// The returned channel receives the result of Serve.
+func startServer(t *testing.T, server *godxmap.Server, addr string) <-chan error {
+	t.Helper()
+	result := make(chan error, 1)
+	go func() {
+		result <- server.Serve()
+	}()
+
+	deadline := time.Now().Add(lifecycleTimeout)
+	for {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			return result
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server does not accept connections: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func dial(addr string) (*websocket.Conn, error) {
+	return websocket.Dial("ws://"+addr+"/", "", "http://"+addr+"/")
+}
+
+// awaitServe waits for the result of Serve and checks that it is ErrServerClosed.
+func awaitServe(t *testing.T, served <-chan error) {
+	t.Helper()
+	select {
+	case err := <-served:
+		if !errors.Is(err, http.ErrServerClosed) {
+			t.Errorf("expected ErrServerClosed from Serve, got %v", err)
+		}
+	case <-time.After(lifecycleTimeout):
+		t.Error("Serve does not return after Close")
+	}
+}
+
+func TestCloseBeforeServe(t *testing.T) {
+	server := newServer(freeAddr(t))
+
+	err := server.Close()
+	if err != nil {
+		t.Errorf("unexpected error from Close: %v", err)
+	}
+	err = server.Serve()
+	if !errors.Is(err, http.ErrServerClosed) {
+		t.Errorf("expected ErrServerClosed from Serve after Close, got %v", err)
+	}
+	err = server.ShowDXSpot("DL1ABC", "W1AW", 14025, "CW")
+	if !errors.Is(err, http.ErrServerClosed) {
+		t.Errorf("expected ErrServerClosed from ShowDXSpot after Close, got %v", err)
+	}
+}
+
+func TestServeAndClose(t *testing.T) {
+	addr := freeAddr(t)
+	server := newServer(addr)
+	served := startServer(t, server, addr)
+
+	conn, err := dial(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	err = server.ShowDXSpot("DL1ABC", "W1AW", 14025, "CW")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var frame map[string]any
+	err = websocket.JSON.Receive(conn, &frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if frame["Spot"] != "DL1ABC" {
+		t.Errorf("unexpected frame: %v", frame)
+	}
+
+	err = server.Close()
+	if err != nil {
+		t.Errorf("unexpected error from Close: %v", err)
+	}
+	awaitServe(t, served)
+
+	// the client is disconnected
+	conn.SetReadDeadline(time.Now().Add(lifecycleTimeout))
+	err = websocket.JSON.Receive(conn, &frame)
+	if err == nil {
+		t.Error("client is still connected after Close")
+	}
+}
+
+func TestCloseTwice(t *testing.T) {
+	addr := freeAddr(t)
+	server := newServer(addr)
+	served := startServer(t, server, addr)
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			server.Close()
+		}()
+	}
+	wg.Wait()
+	err := server.Close()
+	if err != nil {
+		t.Errorf("unexpected error from the last Close: %v", err)
+	}
+	awaitServe(t, served)
+}
+
+func TestCloseWhileClientsConnect(t *testing.T) {
+	addr := freeAddr(t)
+	server := newServer(addr)
+	served := startServer(t, server, addr)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				conn, err := dial(addr)
+				if err == nil {
+					conn.Close()
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				pipe, err := server.Pipe("")
+				if err == nil {
+					pipe.Close()
+				} else if !errors.Is(err, http.ErrServerClosed) {
+					t.Errorf("unexpected error from Pipe: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			err := server.ShowDXSpot("DL1ABC", "W1AW", 14025, "CW")
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				t.Errorf("unexpected error from ShowDXSpot: %v", err)
+			}
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	err := server.Close()
+	if err != nil {
+		t.Errorf("unexpected error from Close: %v", err)
+	}
+	close(stop)
+	wg.Wait()
+	awaitServe(t, served)
+
+	// the connections are unregistered by their own goroutines after they were closed
+	deadline := time.Now().Add(lifecycleTimeout)
+	for len(server.Connections()) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d clients are still connected after Close", len(server.Connections()))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// floodSource emits spots as fast as the server takes them until it is stopped.
+type floodSource struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newFloodSource() *floodSource {
+	return &floodSource{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+func (s *floodSource) Start(spots chan<- godxmap.Spot) error {
+	go func() {
+		defer close(s.done)
+		for {
+			select {
+			case <-s.stop:
+				return
+			case spots <- godxmap.Spot{DX: "DL1ABC", Spotter: "W1AW", FrequencyKHz: 14025}:
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *floodSource) Stop() {
+	close(s.stop)
+	<-s.done
+}
+
+func TestCloseWithAttachedSources(t *testing.T) {
+	addr := freeAddr(t)
+	server := newServer(addr)
+	served := startServer(t, server, addr)
+
+	sources := []*floodSource{newFloodSource(), newFloodSource()}
+	for i, source := range sources {
+		err := server.AttachSource(string(rune('a'+i)), source)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	pipe, err := server.Pipe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pipe.Close()
+
+	time.Sleep(50 * time.Millisecond)
+	err = server.Close()
+	if err != nil {
+		t.Errorf("unexpected error from Close: %v", err)
+	}
+	awaitServe(t, served)
+
+	for i, source := range sources {
+		select {
+		case <-source.done:
+		default:
+			t.Errorf("source %d is still running after Close", i)
+		}
+	}
+	if health := server.SourceHealth(); len(health) > 0 {
+		t.Errorf("%d sources are still attached after Close", len(health))
+	}
+	err = server.AttachSource("late", newFloodSource())
+	if err == nil {
+		t.Error("a source can be attached after Close")
+	}
+}
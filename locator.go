@@ -0,0 +1,131 @@
+package godxmap
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ParseLocator returns the center of the given Maidenhead locator. The locator may have 2, 4, 6 or 8 characters.
+func ParseLocator(locator string) (LatLon, error) {
+	locator = strings.ToUpper(strings.TrimSpace(locator))
+	if len(locator) < 2 || len(locator) > 8 || len(locator)%2 != 0 {
+		return LatLon{}, fmt.Errorf("invalid locator %q", locator)
+	}
+
+	longitude, latitude := -180.0, -90.0
+	lonSize, latSize := 360.0, 180.0
+	for i := 0; i < len(locator); i += 2 {
+		var base byte
+		var divisions float64
+		switch i {
+		case 0:
+			base, divisions = 'A', 18
+		case 4:
+			base, divisions = 'A', 24
+		default:
+			base, divisions = '0', 10
+		}
+		lonSize /= divisions
+		latSize /= divisions
+		lonIndex, latIndex := float64(locator[i])-float64(base), float64(locator[i+1])-float64(base)
+		if lonIndex < 0 || lonIndex >= divisions || latIndex < 0 || latIndex >= divisions {
+			return LatLon{}, fmt.Errorf("invalid locator %q", locator)
+		}
+		longitude += lonIndex * lonSize
+		latitude += latIndex * latSize
+	}
+
+	return LatLon{
+		Latitude:  latitude + latSize/2,
+		Longitude: longitude + lonSize/2,
+	}, nil
+}
+
+// ValidLocator reports if the given string is a valid Maidenhead locator.
+func ValidLocator(locator string) bool {
+	_, err := ParseLocator(locator)
+	return err == nil
+}
+
+// Locator returns the Maidenhead locator with the given number of characters (2, 4, 6 or 8) that contains the position.
+func (p LatLon) Locator(length int) string {
+	length = min(max(length-length%2, 2), 8)
+	longitude := math.Mod(p.Longitude+180, 360)
+	if longitude < 0 {
+		longitude += 360
+	}
+	latitude := min(max(p.Latitude+90, 0), 180-1e-9)
+
+	result := make([]byte, 0, length)
+	lonSize, latSize := 360.0, 180.0
+	for i := 0; i < length; i += 2 {
+		var base byte
+		var divisions float64
+		switch i {
+		case 0:
+			base, divisions = 'A', 18
+		case 4:
+			base, divisions = 'a', 24
+		default:
+			base, divisions = '0', 10
+		}
+		lonSize /= divisions
+		latSize /= divisions
+		lonIndex, latIndex := math.Floor(longitude/lonSize), math.Floor(latitude/latSize)
+		result = append(result, base+byte(lonIndex), base+byte(latIndex))
+		longitude -= lonIndex * lonSize
+		latitude -= latIndex * latSize
+	}
+	return string(result)
+}
+
+// located returns the normalized locator and the coordinates of its center.
+func located(locator string) (string, *float64, *float64, error) {
+	position, err := ParseLocator(locator)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return strings.ToUpper(strings.TrimSpace(locator)), &position.Latitude, &position.Longitude, nil
+}
+
+// ShowLoggedCallLocator adds information about a logged callsign in the given Maidenhead locator to the map.
+// The station is placed at the center of the locator instead of the center of its DXCC entity.
+func (s *Server) ShowLoggedCallLocator(call string, frequencyKHz float64, locator string) error {
+	f := s.loggedCallFrame(call, frequencyKHz)
+	var err error
+	f.Locator, f.Latitude, f.Longitude, err = located(locator)
+	if err != nil {
+		return fmt.Errorf("cannot show %s: %v", call, err)
+	}
+	return s.send(f)
+}
+
+// ShowLoggedCallPosition adds information about a logged callsign at the given coordinates in degrees to the map.
+func (s *Server) ShowLoggedCallPosition(call string, frequencyKHz float64, latitude float64, longitude float64) error {
+	f := s.loggedCallFrame(call, frequencyKHz)
+	f.Latitude = &latitude
+	f.Longitude = &longitude
+	return s.send(f)
+}
+
+// ShowDXSpotLocator adds information about a DX spot in the given Maidenhead locator to the map.
+// The mode of the spot is inferred like in [Server.ShowDXSpot].
+func (s *Server) ShowDXSpotLocator(spot string, spotter string, frequencyKHz float64, comments string, locator string) error {
+	f := s.dxSpotFrame(spot, spotter, frequencyKHz, comments, inferMode(frequencyKHz, comments))
+	var err error
+	f.Locator, f.Latitude, f.Longitude, err = located(locator)
+	if err != nil {
+		return fmt.Errorf("cannot show %s: %v", spot, err)
+	}
+	return s.sendDXSpot(f)
+}
+
+// ShowDXSpotPosition adds information about a DX spot at the given coordinates in degrees to the map.
+// The mode of the spot is inferred like in [Server.ShowDXSpot].
+func (s *Server) ShowDXSpotPosition(spot string, spotter string, frequencyKHz float64, comments string, latitude float64, longitude float64) error {
+	f := s.dxSpotFrame(spot, spotter, frequencyKHz, comments, inferMode(frequencyKHz, comments))
+	f.Latitude = &latitude
+	f.Longitude = &longitude
+	return s.sendDXSpot(f)
+}
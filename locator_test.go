@@ -0,0 +1,100 @@
+package godxmap_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ftl/godxmap"
+)
+
+func TestParseLocator(t *testing.T) {
+	for _, tc := range []struct {
+		locator  string
+		expected godxmap.LatLon
+	}{
+		{"JO", godxmap.LatLon{Latitude: 55, Longitude: 10}},
+		{"JO62", godxmap.LatLon{Latitude: 52.5, Longitude: 13}},
+		{"jo62qm", godxmap.LatLon{Latitude: 52.520833, Longitude: 13.375}},
+		{" JO62QM15 ", godxmap.LatLon{Latitude: 52.522917, Longitude: 13.345833}},
+		{"FN31pr", godxmap.LatLon{Latitude: 41.729167, Longitude: -72.708333}},
+		{"AA00aa", godxmap.LatLon{Latitude: -89.979167, Longitude: -179.958333}},
+		{"RR99xx", godxmap.LatLon{Latitude: 89.979167, Longitude: 179.958333}},
+	} {
+		t.Run(tc.locator, func(t *testing.T) {
+			actual, err := godxmap.ParseLocator(tc.locator)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !almostEqual(actual.Latitude, tc.expected.Latitude) || !almostEqual(actual.Longitude, tc.expected.Longitude) {
+				t.Errorf("expected %+v, got %+v", tc.expected, actual)
+			}
+			if !godxmap.ValidLocator(tc.locator) {
+				t.Error("the locator is not valid")
+			}
+		})
+	}
+}
+
+func TestParseInvalidLocator(t *testing.T) {
+	for _, locator := range []string{
+		"",
+		"J",
+		"JO6",
+		"JO62Q",
+		"JO62QM1",
+		"JO62QM15AA",
+		"SA00",
+		"JS00",
+		"JOA2",
+		"JO62YA",
+		"JO62QMA5",
+		"J-62",
+		"ÄÖ62",
+		strings.Repeat("JO62", 1000),
+	} {
+		t.Run(locator, func(t *testing.T) {
+			actual, err := godxmap.ParseLocator(locator)
+			if err == nil {
+				t.Errorf("expected an error, got %+v", actual)
+			}
+			if godxmap.ValidLocator(locator) {
+				t.Error("the locator is valid")
+			}
+		})
+	}
+}
+
+func TestLocatorOfPosition(t *testing.T) {
+	for _, tc := range []struct {
+		position godxmap.LatLon
+		length   int
+		expected string
+	}{
+		{godxmap.LatLon{Latitude: 52.52, Longitude: 13.38}, 6, "JO62qm"},
+		{godxmap.LatLon{Latitude: 52.52, Longitude: 13.38}, 8, "JO62qm54"},
+		{godxmap.LatLon{Latitude: 52.52, Longitude: 13.38}, 5, "JO62"},
+		{godxmap.LatLon{Latitude: 52.52, Longitude: 13.38}, 0, "JO"},
+		{godxmap.LatLon{Latitude: 52.52, Longitude: 13.38}, 12, "JO62qm54"},
+		{godxmap.LatLon{Latitude: 41.714, Longitude: -72.727}, 6, "FN31pr"},
+		{godxmap.LatLon{Latitude: -90, Longitude: -180}, 6, "AA00aa"},
+		{godxmap.LatLon{Latitude: 90, Longitude: 180}, 6, "AR09ax"},
+		{godxmap.LatLon{Latitude: 90, Longitude: 179.99}, 6, "RR99xx"},
+		{godxmap.LatLon{Latitude: 100, Longitude: 370}, 4, "JR59"},
+	} {
+		if actual := tc.position.Locator(tc.length); actual != tc.expected {
+			t.Errorf("%+v, %d: expected %s, got %s", tc.position, tc.length, tc.expected, actual)
+		}
+	}
+}
+
+func TestLocatorRoundTrip(t *testing.T) {
+	for _, locator := range []string{"JO62qm15", "FN31pr00", "AA00aa00", "RR99xx99", "PM95vq", "GG66", "KP"} {
+		position, err := godxmap.ParseLocator(locator)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := position.Locator(len(locator)); actual != locator {
+			t.Errorf("expected %s, got %s", locator, actual)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package lookup
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// HamQTHURL is the address of the XML interface of HamQTH.com.
+const HamQTHURL = "https://www.hamqth.com/xml.php"
+
+// HamQTH looks up callsigns in the XML interface of HamQTH.com.
+type HamQTH struct {
+	username string
+	password string
+	client   *http.Client
+
+	mutex     sync.Mutex
+	sessionID string
+}
+
+// NewHamQTH creates a new [Provider] for HamQTH.com that logs in with the given credentials.
+func NewHamQTH(username string, password string) *HamQTH {
+	return &HamQTH{
+		username: username,
+		password: password,
+		client:   &http.Client{Timeout: httpTimeout},
+	}
+}
+
+type hamQTHResponse struct {
+	Session struct {
+		ID    string `xml:"session_id"`
+		Error string `xml:"error"`
+	} `xml:"session"`
+	Search *struct {
+		Callsign  string `xml:"callsign"`
+		Nick      string `xml:"nick"`
+		Name      string `xml:"adr_name"`
+		Grid      string `xml:"grid"`
+		Country   string `xml:"country"`
+		ADIF      string `xml:"adif"`
+		Latitude  string `xml:"latitude"`
+		Longitude string `xml:"longitude"`
+	} `xml:"search"`
+}
+
+// Lookup implements [Provider].
+func (h *HamQTH) Lookup(ctx context.Context, call string) (Result, error) {
+	sessionID, err := h.session(ctx, false)
+	if err != nil {
+		return Result{}, err
+	}
+	response, err := h.request(ctx, url.Values{"id": {sessionID}, "callsign": {call}, "prg": {"godxmap"}})
+	if err != nil {
+		return Result{}, err
+	}
+	if response.Search == nil && strings.Contains(response.Session.Error, "Session does not exist or expired") {
+		sessionID, err = h.session(ctx, true)
+		if err != nil {
+			return Result{}, err
+		}
+		response, err = h.request(ctx, url.Values{"id": {sessionID}, "callsign": {call}, "prg": {"godxmap"}})
+		if err != nil {
+			return Result{}, err
+		}
+	}
+	if response.Search == nil {
+		if strings.Contains(response.Session.Error, "Callsign not found") {
+			return Result{}, ErrNotFound
+		}
+		return Result{}, fmt.Errorf("HamQTH: %s", response.Session.Error)
+	}
+
+	search := response.Search
+	result := Result{
+		Call:    strings.ToUpper(search.Callsign),
+		Name:    search.Name,
+		Locator: search.Grid,
+		Country: search.Country,
+	}
+	if result.Name == "" {
+		result.Name = search.Nick
+	}
+	result.DXCC, _ = strconv.Atoi(search.ADIF)
+	result.Position = parsePosition(search.Latitude, search.Longitude)
+	return result, nil
+}
+
+func (h *HamQTH) session(ctx context.Context, renew bool) (string, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.sessionID != "" && !renew {
+		return h.sessionID, nil
+	}
+	response, err := h.request(ctx, url.Values{"u": {h.username}, "p": {h.password}})
+	if err != nil {
+		return "", err
+	}
+	if response.Session.ID == "" {
+		return "", fmt.Errorf("cannot log in to HamQTH: %s", response.Session.Error)
+	}
+	h.sessionID = response.Session.ID
+	return h.sessionID, nil
+}
+
+func (h *HamQTH) request(ctx context.Context, parameters url.Values) (*hamQTHResponse, error) {
+	result := new(hamQTHResponse)
+	err := requestXML(ctx, h.client, HamQTHURL+"?"+parameters.Encode(), result)
+	if err != nil {
+		return nil, fmt.Errorf("cannot request HamQTH: %v", err)
+	}
+	return result, nil
+}
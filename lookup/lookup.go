@@ -0,0 +1,239 @@
+// The package lookup enriches the frames of a [godxmap.Server] with information about the callsigns
+// from online callbook services like QRZ.com or HamQTH.
+package lookup
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+const (
+	defaultCacheTTL  = 24 * time.Hour
+	defaultRateLimit = time.Second
+	defaultTimeout   = 2 * time.Second
+)
+
+// ErrNotFound is returned by a [Provider] if the callbook does not know the callsign.
+var ErrNotFound = errors.New("callsign not found")
+
+var errRateLimited = errors.New("rate limit exceeded")
+
+// Result contains the information about a callsign that is provided by a callbook.
+type Result struct {
+	Call    string
+	Name    string
+	Locator string
+	Country string
+	// DXCC is the number of the DXCC entity as used in ADIF.
+	DXCC     int
+	Position *godxmap.LatLon
+}
+
+// Provider looks up callsigns in a callbook.
+type Provider interface {
+	Lookup(ctx context.Context, call string) (Result, error)
+}
+
+// Client looks up callsigns with a [Provider]. It caches the results and limits the rate of requests to the provider.
+type Client struct {
+	provider  Provider
+	cacheTTL  time.Duration
+	rateLimit time.Duration
+	timeout   time.Duration
+	logger    *slog.Logger
+	enabled   atomic.Bool
+
+	mutex       sync.Mutex
+	cache       map[string]cacheEntry
+	lastRequest time.Time
+}
+
+type cacheEntry struct {
+	result  Result
+	err     error
+	expires time.Time
+}
+
+// Option configures a [Client] instance.
+type Option func(*Client)
+
+// WithCacheTTL sets how long the results are cached. The default is 24 hours.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cacheTTL = ttl
+	}
+}
+
+// WithRateLimit sets the minimum interval between two requests to the provider. The default is one second.
+// Callsigns that cannot be looked up because of the rate limit are not enriched.
+func WithRateLimit(interval time.Duration) Option {
+	return func(c *Client) {
+		c.rateLimit = interval
+	}
+}
+
+// WithTimeout sets the maximum time to wait for the provider when a frame is enriched. The default is two seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithLogger writes the log messages of the client to the given logger. By default, [slog.Default] is used.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// NewClient creates a new client that looks up callsigns with the given provider.
+func NewClient(provider Provider, options ...Option) *Client {
+	result := &Client{
+		provider:  provider,
+		cacheTTL:  defaultCacheTTL,
+		rateLimit: defaultRateLimit,
+		timeout:   defaultTimeout,
+		logger:    slog.Default(),
+		cache:     make(map[string]cacheEntry),
+	}
+	result.enabled.Store(true)
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+// SetEnabled switches the enrichment of frames on or off. The client is enabled by default.
+func (c *Client) SetEnabled(enabled bool) {
+	c.enabled.Store(enabled)
+}
+
+// Lookup returns the information about the given callsign, either from the cache or from the provider.
+func (c *Client) Lookup(ctx context.Context, call string) (Result, error) {
+	call = strings.ToUpper(strings.TrimSpace(call))
+	now := time.Now()
+
+	c.mutex.Lock()
+	entry, ok := c.cache[call]
+	if ok && now.Before(entry.expires) {
+		c.mutex.Unlock()
+		return entry.result, entry.err
+	}
+	if now.Sub(c.lastRequest) < c.rateLimit {
+		c.mutex.Unlock()
+		return Result{}, errRateLimited
+	}
+	c.lastRequest = now
+	c.mutex.Unlock()
+
+	result, err := c.provider.Lookup(ctx, call)
+	if err != nil && err != ErrNotFound {
+		return Result{}, err
+	}
+
+	// unknown callsigns are cached as well, so they do not consume the rate limit over and over again
+	c.mutex.Lock()
+	c.cache[call] = cacheEntry{result: result, err: err, expires: now.Add(c.cacheTTL)}
+	c.mutex.Unlock()
+	return result, err
+}
+
+// Middleware returns a [godxmap.Middleware] that enriches logged calls, partial calls and DX spots
+// with the name, the locator and the country of the station. Fields that are already set are kept.
+// Register it with [godxmap.Server.Use].
+func (c *Client) Middleware() godxmap.Middleware {
+	return func(f godxmap.Frame) (godxmap.Frame, bool) {
+		if !c.enabled.Load() {
+			return f, true
+		}
+		switch f := f.(type) {
+		case *godxmap.LoggedCallFrame:
+			if result, ok := c.lookupForFrame(f.Call); ok {
+				if f.Name == "" {
+					f.Name = result.Name
+				}
+				applyPosition(result, &f.Locator, &f.Latitude, &f.Longitude)
+			}
+		case *godxmap.PartialCallFrame:
+			if result, ok := c.lookupForFrame(f.Call); ok {
+				if f.Name == "" {
+					f.Name = result.Name
+				}
+				if f.Entity == "" {
+					f.Entity = result.Country
+					f.DXCC = result.DXCC
+				}
+				applyPosition(result, &f.Locator, &f.Latitude, &f.Longitude)
+			}
+		case *godxmap.DXSpotFrame:
+			if result, ok := c.lookupForFrame(f.Spot); ok {
+				applyPosition(result, &f.Locator, &f.Latitude, &f.Longitude)
+			}
+		}
+		return f, true
+	}
+}
+
+// Enrich implements [godxmap.Enricher]. Callsigns that cannot be looked up because of the rate limit are reported as unknown.
+func (c *Client) Enrich(call string) (godxmap.CallInfo, error) {
+	if !c.enabled.Load() {
+		return godxmap.CallInfo{}, godxmap.ErrUnknownCall
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	result, err := c.Lookup(ctx, call)
+	switch err {
+	case nil:
+		return godxmap.CallInfo{
+			Name:     result.Name,
+			DXCC:     result.DXCC,
+			Entity:   result.Country,
+			Locator:  result.Locator,
+			Position: result.Position,
+		}, nil
+	case ErrNotFound, errRateLimited:
+		return godxmap.CallInfo{}, godxmap.ErrUnknownCall
+	default:
+		return godxmap.CallInfo{}, err
+	}
+}
+
+func (c *Client) lookupForFrame(call string) (Result, bool) {
+	if call == "" {
+		return Result{}, false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	result, err := c.Lookup(ctx, call)
+	switch err {
+	case nil:
+		return result, true
+	case ErrNotFound, errRateLimited:
+		return Result{}, false
+	default:
+		c.logger.Warn("cannot look up call", "call", call, "error", err)
+		return Result{}, false
+	}
+}
+
+func applyPosition(result Result, locator *string, latitude **float64, longitude **float64) {
+	if *locator != "" || *latitude != nil || *longitude != nil {
+		return
+	}
+	*locator = result.Locator
+	if result.Position != nil {
+		lat, lon := result.Position.Latitude, result.Position.Longitude
+		*latitude, *longitude = &lat, &lon
+	} else if position, err := godxmap.ParseLocator(result.Locator); err == nil {
+		*latitude, *longitude = &position.Latitude, &position.Longitude
+	}
+}
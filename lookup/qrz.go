@@ -0,0 +1,151 @@
+package lookup
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+// QRZURL is the address of the XML interface of QRZ.com.
+const QRZURL = "https://xmldata.qrz.com/xml/current/"
+
+const httpTimeout = 10 * time.Second
+
+// QRZ looks up callsigns in the XML interface of QRZ.com. A subscription is required to get complete results.
+type QRZ struct {
+	username string
+	password string
+	client   *http.Client
+
+	mutex sync.Mutex
+	key   string
+}
+
+// NewQRZ creates a new [Provider] for QRZ.com that logs in with the given credentials.
+func NewQRZ(username string, password string) *QRZ {
+	return &QRZ{
+		username: username,
+		password: password,
+		client:   &http.Client{Timeout: httpTimeout},
+	}
+}
+
+type qrzResponse struct {
+	Session struct {
+		Key   string `xml:"Key"`
+		Error string `xml:"Error"`
+	} `xml:"Session"`
+	Callsign *struct {
+		Call    string `xml:"call"`
+		FName   string `xml:"fname"`
+		Name    string `xml:"name"`
+		Grid    string `xml:"grid"`
+		Country string `xml:"country"`
+		DXCC    string `xml:"dxcc"`
+		Lat     string `xml:"lat"`
+		Lon     string `xml:"lon"`
+	} `xml:"Callsign"`
+}
+
+// Lookup implements [Provider].
+func (q *QRZ) Lookup(ctx context.Context, call string) (Result, error) {
+	key, err := q.session(ctx, false)
+	if err != nil {
+		return Result{}, err
+	}
+	response, err := q.request(ctx, url.Values{"s": {key}, "callsign": {call}})
+	if err != nil {
+		return Result{}, err
+	}
+	if response.Session.Key == "" {
+		// the session expired, log in again
+		key, err = q.session(ctx, true)
+		if err != nil {
+			return Result{}, err
+		}
+		response, err = q.request(ctx, url.Values{"s": {key}, "callsign": {call}})
+		if err != nil {
+			return Result{}, err
+		}
+	}
+	if response.Callsign == nil {
+		if strings.Contains(response.Session.Error, "Not found") {
+			return Result{}, ErrNotFound
+		}
+		return Result{}, fmt.Errorf("QRZ.com: %s", response.Session.Error)
+	}
+
+	callsign := response.Callsign
+	result := Result{
+		Call:    callsign.Call,
+		Name:    strings.TrimSpace(callsign.FName + " " + callsign.Name),
+		Locator: callsign.Grid,
+		Country: callsign.Country,
+	}
+	result.DXCC, _ = strconv.Atoi(callsign.DXCC)
+	result.Position = parsePosition(callsign.Lat, callsign.Lon)
+	return result, nil
+}
+
+func (q *QRZ) session(ctx context.Context, renew bool) (string, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.key != "" && !renew {
+		return q.key, nil
+	}
+	response, err := q.request(ctx, url.Values{"username": {q.username}, "password": {q.password}, "agent": {"godxmap"}})
+	if err != nil {
+		return "", err
+	}
+	if response.Session.Key == "" {
+		return "", fmt.Errorf("cannot log in to QRZ.com: %s", response.Session.Error)
+	}
+	q.key = response.Session.Key
+	return q.key, nil
+}
+
+func (q *QRZ) request(ctx context.Context, parameters url.Values) (*qrzResponse, error) {
+	result := new(qrzResponse)
+	err := requestXML(ctx, q.client, QRZURL+"?"+parameters.Encode(), result)
+	if err != nil {
+		return nil, fmt.Errorf("cannot request QRZ.com: %v", err)
+	}
+	return result, nil
+}
+
+func requestXML(ctx context.Context, client *http.Client, url string, v any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s", response.Status)
+	}
+	return xml.NewDecoder(response.Body).Decode(v)
+}
+
+func parsePosition(latitude string, longitude string) *godxmap.LatLon {
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latitude), 64)
+	if err != nil {
+		return nil
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(longitude), 64)
+	if err != nil {
+		return nil
+	}
+	return &godxmap.LatLon{Latitude: lat, Longitude: lon}
+}
@@ -0,0 +1,122 @@
+package godxmap
+
+import (
+	"runtime/metrics"
+	"time"
+)
+
+// MemoryPressure indicates how close the process is to its configured memory limits.
+type MemoryPressure int
+
+// The levels of memory pressure.
+const (
+	MemoryPressureNormal MemoryPressure = iota
+	MemoryPressureHigh
+	MemoryPressureCritical
+)
+
+func (p MemoryPressure) String() string {
+	switch p {
+	case MemoryPressureNormal:
+		return "normal"
+	case MemoryPressureHigh:
+		return "high"
+	case MemoryPressureCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// MemoryWatchdogConfig controls the memory watchdog of a server.
+type MemoryWatchdogConfig struct {
+	// Interval is the period between two measurements of the heap size.
+	Interval time.Duration
+	// HighWaterMark is the heap size in bytes above which the memory pressure is high.
+	HighWaterMark uint64
+	// CriticalWaterMark is the heap size in bytes above which the memory pressure is critical.
+	CriticalWaterMark uint64
+	// OnPressure is called whenever the memory pressure level changes.
+	// This allows the host application to shed its own load.
+	OnPressure func(MemoryPressure)
+}
+
+// WithMemoryWatchdog periodically measures the heap size and sheds load when the memory pressure rises.
+// With high pressure, the retained buffers of the server are shrinked to half their size, the older half of the
+// frames in the [FrameStore] is removed and the rate limits are halved. With critical pressure, the buffers are
+// dropped completely, only the most recent quarter of the stored frames is kept and the rate limits are quartered.
+func WithMemoryWatchdog(config MemoryWatchdogConfig) Option {
+	return func(s *Server) {
+		s.memoryWatchdog = &config
+	}
+}
+
+// loadShedder is implemented by all components that are able to reduce their memory footprint.
+// Shed is always called from within the run loop of the server.
+type loadShedder interface {
+	Shed(MemoryPressure)
+}
+
+func (s *Server) watchMemory(config MemoryWatchdogConfig) {
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	current := MemoryPressureNormal
+	for {
+		select {
+		case <-s.closed:
+			return
+		case <-ticker.C:
+			metrics.Read(sample)
+			heapSize := sample[0].Value.Uint64()
+
+			pressure := MemoryPressureNormal
+			switch {
+			case config.CriticalWaterMark > 0 && heapSize >= config.CriticalWaterMark:
+				pressure = MemoryPressureCritical
+			case config.HighWaterMark > 0 && heapSize >= config.HighWaterMark:
+				pressure = MemoryPressureHigh
+			}
+			if pressure == current {
+				continue
+			}
+			current = pressure
+
+			select {
+			case s.pressure <- pressure:
+			case <-s.closed:
+				return
+			}
+			if config.OnPressure != nil {
+				config.OnPressure(pressure)
+			}
+		}
+	}
+}
+
+func (s *Server) loadShedders() []loadShedder {
+	result := make([]loadShedder, 0, 7)
+	if s.resume != nil {
+		result = append(result, s.resume)
+	}
+	if s.history != nil {
+		result = append(result, s.history)
+	}
+	if s.state != nil {
+		result = append(result, s.state)
+	}
+	if s.dedup != nil {
+		result = append(result, s.dedup)
+	}
+	if s.aggregator != nil {
+		result = append(result, s.aggregator)
+	}
+	if s.rateLimiter != nil {
+		result = append(result, s.rateLimiter)
+	}
+	if s.storeWriter != nil {
+		result = append(result, s.storeWriter)
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+package godxmap
+
+// Middleware processes every frame before it is broadcast. It may return a modified or completely different frame.
+// If it returns false, the frame is dropped silently.
+type Middleware func(Frame) (Frame, bool)
+
+// Use adds the given middleware to the end of the middleware chain of this server.
+// The middleware chain is applied to every frame before it is validated and broadcast.
+func (s *Server) Use(middleware Middleware) {
+	s.middlewareLock.Lock()
+	defer s.middlewareLock.Unlock()
+
+	s.middleware = append(s.middleware, middleware)
+}
+
+// Transformer rewrites or enriches a frame of a specific type, e.g. to normalize callsigns.
+type Transformer func(Frame) Frame
+
+// Transform registers the given transformer for all frames of the given type.
+// The transformers of a frame type are applied in the order of their registration, before the middleware chain.
+func (s *Server) Transform(frameType string, transformer Transformer) {
+	s.middlewareLock.Lock()
+	defer s.middlewareLock.Unlock()
+
+	if s.transformers == nil {
+		s.transformers = make(map[string][]Transformer)
+	}
+	s.transformers[frameType] = append(s.transformers[frameType], transformer)
+}
+
+func (s *Server) applyMiddleware(f Frame) (Frame, bool) {
+	s.middlewareLock.RLock()
+	defer s.middlewareLock.RUnlock()
+
+	for _, transformer := range s.transformers[f.FrameType()] {
+		f = transformer(f)
+		if f == nil {
+			return nil, false
+		}
+	}
+	for _, middleware := range s.middleware {
+		var ok bool
+		f, ok = middleware(f)
+		if !ok || f == nil {
+			return nil, false
+		}
+	}
+	return f, true
+}
@@ -0,0 +1,162 @@
+// The package mqttpub mirrors all frames of a [godxmap.Server] to an MQTT broker, so home automation dashboards
+// and other subscribers can consume the same stream as the map clients.
+package mqttpub
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/ftl/godxmap"
+)
+
+// DefaultTopicPrefix is the default prefix of the topics. The frames are published to <prefix>/<frame type>, e.g. godxmap/DXSpot.
+const DefaultTopicPrefix = "godxmap"
+
+const (
+	connectTimeout = 10 * time.Second
+	queueSize      = 256
+)
+
+// Publisher is a [godxmap.Sink] that publishes every broadcast frame as JSON to an MQTT broker.
+// If the broker cannot keep up, frames are dropped instead of slowing down the server.
+type Publisher struct {
+	broker      string
+	clientID    string
+	username    string
+	password    string
+	topicPrefix string
+	topics      map[string]string
+	qos         byte
+	retained    bool
+	logger      *slog.Logger
+
+	queue chan godxmap.Frame
+}
+
+// Option configures a [Publisher] instance.
+type Option func(*Publisher)
+
+// WithTopicPrefix sets the prefix of the topics. The default is [DefaultTopicPrefix].
+func WithTopicPrefix(prefix string) Option {
+	return func(p *Publisher) {
+		p.topicPrefix = prefix
+	}
+}
+
+// WithTopic publishes all frames of the given type to the given topic. An empty topic suppresses the frame type.
+func WithTopic(frameType string, topic string) Option {
+	return func(p *Publisher) {
+		p.topics[frameType] = topic
+	}
+}
+
+// WithCredentials logs in to the broker with the given username and password.
+func WithCredentials(username string, password string) Option {
+	return func(p *Publisher) {
+		p.username = username
+		p.password = password
+	}
+}
+
+// WithClientID sets the MQTT client ID. By default, a unique client ID is generated.
+func WithClientID(clientID string) Option {
+	return func(p *Publisher) {
+		p.clientID = clientID
+	}
+}
+
+// WithQoS sets the quality of service level of the published messages. The default is 0.
+func WithQoS(qos byte) Option {
+	return func(p *Publisher) {
+		p.qos = qos
+	}
+}
+
+// WithRetained publishes the messages as retained messages, so new subscribers immediately get the latest frame of each topic.
+func WithRetained(retained bool) Option {
+	return func(p *Publisher) {
+		p.retained = retained
+	}
+}
+
+// WithLogger writes the log messages of the publisher to the given logger. By default, [slog.Default] is used.
+func WithLogger(logger *slog.Logger) Option {
+	return func(p *Publisher) {
+		p.logger = logger
+	}
+}
+
+// NewPublisher creates a new publisher for the given broker, e.g. tcp://localhost:1883.
+// Register it with [godxmap.WithSink] and use the Run method to actually connect to the broker.
+func NewPublisher(broker string, options ...Option) *Publisher {
+	result := &Publisher{
+		broker:      broker,
+		clientID:    fmt.Sprintf("godxmap-%d", time.Now().UnixNano()),
+		topicPrefix: DefaultTopicPrefix,
+		topics:      make(map[string]string),
+		logger:      slog.Default(),
+		queue:       make(chan godxmap.Frame, queueSize),
+	}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+// Publish implements [godxmap.Sink].
+func (p *Publisher) Publish(f godxmap.Frame) {
+	select {
+	case p.queue <- f:
+	default:
+		p.logger.Warn("cannot publish frame, queue is full", "broker", p.broker, "frame_type", f.FrameType(), "frame_id", f.Header().ID)
+	}
+}
+
+func (p *Publisher) topic(frameType string) string {
+	if topic, ok := p.topics[frameType]; ok {
+		return topic
+	}
+	return p.topicPrefix + "/" + frameType
+}
+
+// Run connects to the broker and publishes the frames until the given context is done.
+func (p *Publisher) Run(ctx context.Context) error {
+	options := mqtt.NewClientOptions().
+		AddBroker(p.broker).
+		SetClientID(p.clientID).
+		SetUsername(p.username).
+		SetPassword(p.password).
+		SetAutoReconnect(true)
+	client := mqtt.NewClient(options)
+	token := client.Connect()
+	if !token.WaitTimeout(connectTimeout) {
+		return fmt.Errorf("cannot connect to %s: timeout", p.broker)
+	}
+	if err := token.Error(); err != nil {
+		return fmt.Errorf("cannot connect to %s: %v", p.broker, err)
+	}
+	defer client.Disconnect(250)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case f := <-p.queue:
+			topic := p.topic(f.FrameType())
+			if topic == "" {
+				continue
+			}
+			payload, err := godxmap.EncodeFrame(f)
+			if err != nil {
+				p.logger.Warn("cannot encode frame", "frame_type", f.FrameType(), "frame_id", f.Header().ID, "error", err)
+				continue
+			}
+			// do not wait for the broker, paho reports failures asynchronously through the token
+			client.Publish(topic, p.qos, p.retained, payload)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// The package n1mm listens for the UDP broadcasts of N1MM Logger+ and shows contacts, spots and radio states
+// on the map of a [godxmap.Server].
+package n1mm
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/ftl/godxmap"
+)
+
+// DefaultAddr is the default address where N1MM+ sends its broadcasts to.
+const DefaultAddr = ":12060"
+
+// Listener receives the UDP broadcasts of N1MM+ and translates them into frames:
+//   - ContactInfo is shown as logged QSO,
+//   - ContactDelete removes the logged call,
+//   - Spot is shown as DX spot or removed from the map,
+//   - RadioInfo is shown as station status.
+type Listener struct {
+	addr   string
+	server *godxmap.Server
+	source godxmap.SourceRunner
+}
+
+// NewListener creates a new listener for the given UDP address that feeds the given server.
+// To actually receive broadcasts, use the Run method.
+func NewListener(addr string, server *godxmap.Server) *Listener {
+	return &Listener{
+		addr:   addr,
+		server: server,
+	}
+}
+
+// Run receives and processes the N1MM+ broadcasts until the given context is done.
+func (l *Listener) Run(ctx context.Context) error {
+	conn, err := net.ListenPacket("udp", l.addr)
+	if err != nil {
+		return fmt.Errorf("cannot listen for N1MM+ broadcasts on %s: %v", l.addr, err)
+	}
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	buffer := make([]byte, 64*1024)
+	for {
+		n, _, err := conn.ReadFrom(buffer)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("cannot receive N1MM+ broadcast: %v", err)
+		}
+		message, err := parseMessage(buffer[:n])
+		if err == errUnsupportedMessage {
+			continue
+		}
+		if err != nil {
+			l.server.Logger().Warn("invalid N1MM+ broadcast", "error", err)
+			continue
+		}
+		err = l.handle(message)
+		if err != nil {
+			l.server.Logger().Warn("cannot show N1MM+ broadcast", "error", err)
+		}
+	}
+}
+
+// Start runs the listener in the background. The spots are emitted on the given channel instead of
+// being shown directly. Start, Stop and Status implement [godxmap.SpotSource] and [godxmap.SourceStatus].
+func (l *Listener) Start(spots chan<- godxmap.Spot) error {
+	return l.source.Start(spots, l.Run)
+}
+
+// Stop stops the listener that was started with Start.
+func (l *Listener) Stop() {
+	l.source.Stop()
+}
+
+// Status reports if the listener that was started with Start is still running.
+func (l *Listener) Status() (bool, error) {
+	return l.source.Status()
+}
+
+func (l *Listener) showSpot(spot godxmap.Spot) error {
+	if l.source.Emit(spot) {
+		return nil
+	}
+	return l.server.ShowSpot(spot)
+}
+
+func (l *Listener) handle(message any) error {
+	switch message := message.(type) {
+	case *ContactInfo:
+		return l.server.ShowLoggedQSO(godxmap.QSO{
+			Call:         message.Call,
+			FrequencyKHz: float64(message.RXFrequency) / 100,
+			Mode:         godxmap.Mode(message.Mode),
+			Exchange:     message.Exchange,
+			Operator:     message.Operator,
+			Time:         parseTimestamp(message.Timestamp),
+		})
+	case *ContactDelete:
+		return l.server.ShowDeletedCall(message.Call, 0)
+	case *Spot:
+		if strings.EqualFold(message.Action, "delete") {
+			return l.server.ClearDXSpot(message.DXCall, message.Frequency)
+		}
+		comment := message.Comment
+		if message.Mode != "" && godxmap.ModeFromComments(comment) == godxmap.NoMode {
+			comment = strings.TrimSpace(message.Mode + " " + comment)
+		}
+		return l.showSpot(godxmap.Spot{
+			Time:         parseTimestamp(message.Timestamp),
+			DX:           message.DXCall,
+			Spotter:      message.SpotterCall,
+			FrequencyKHz: message.Frequency,
+			Comments:     comment,
+		})
+	case *RadioInfo:
+		return l.server.ShowStatus(message.StationName, message.OpCall, float64(message.RXFrequency)/100, message.Mode)
+	}
+	return nil
+}
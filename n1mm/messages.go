@@ -0,0 +1,105 @@
+package n1mm
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"time"
+)
+
+// timestampLayout is the layout of the timestamps in N1MM+ broadcasts.
+const timestampLayout = "2006-01-02 15:04:05"
+
+// ContactInfo is broadcast by N1MM+ when a contact is logged (contactinfo) or replaced (contactreplace).
+type ContactInfo struct {
+	XMLName     xml.Name // contactinfo or contactreplace
+	Timestamp   string   `xml:"timestamp"`
+	Call        string   `xml:"call"`
+	RXFrequency int      `xml:"rxfreq"` // in units of 10 Hz
+	TXFrequency int      `xml:"txfreq"` // in units of 10 Hz
+	Mode        string   `xml:"mode"`
+	Band        string   `xml:"band"`
+	Exchange    string   `xml:"exchange1"`
+	Operator    string   `xml:"operator"`
+	StationName string   `xml:"StationName"`
+}
+
+// ContactDelete is broadcast by N1MM+ when a contact is deleted.
+type ContactDelete struct {
+	XMLName   xml.Name `xml:"contactdelete"`
+	Timestamp string   `xml:"timestamp"`
+	Call      string   `xml:"call"`
+}
+
+// Spot is broadcast by N1MM+ for every spot that is added to or deleted from the bandmap.
+type Spot struct {
+	XMLName     xml.Name `xml:"spot"`
+	Action      string   `xml:"action"`
+	DXCall      string   `xml:"dxcall"`
+	Frequency   float64  `xml:"frequency"` // in kHz
+	SpotterCall string   `xml:"spottercall"`
+	Comment     string   `xml:"comment"`
+	Mode        string   `xml:"mode"`
+	Timestamp   string   `xml:"timestamp"`
+}
+
+// RadioInfo is broadcast by N1MM+ periodically with the state of a radio.
+type RadioInfo struct {
+	XMLName     xml.Name `xml:"RadioInfo"`
+	StationName string   `xml:"StationName"`
+	RXFrequency int      `xml:"Freq"` // in units of 10 Hz
+	TXFrequency int      `xml:"TXFreq"`
+	Mode        string   `xml:"Mode"`
+	OpCall      string   `xml:"OpCall"`
+}
+
+var errUnsupportedMessage = errors.New("unsupported message")
+
+// parseMessage parses a single N1MM+ broadcast datagram.
+func parseMessage(data []byte) (any, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var root xml.StartElement
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			root = start
+			break
+		}
+	}
+
+	var result any
+	switch strings.ToLower(root.Name.Local) {
+	case "contactinfo", "contactreplace":
+		var message ContactInfo
+		result = &message
+	case "contactdelete":
+		var message ContactDelete
+		result = &message
+	case "spot":
+		var message Spot
+		result = &message
+	case "radioinfo":
+		var message RadioInfo
+		result = &message
+	default:
+		return nil, errUnsupportedMessage
+	}
+
+	err := decoder.DecodeElement(result, &root)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func parseTimestamp(timestamp string) time.Time {
+	result, err := time.Parse(timestampLayout, timestamp)
+	if err != nil {
+		return time.Time{}
+	}
+	return result
+}
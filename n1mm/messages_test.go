@@ -0,0 +1,134 @@
+package n1mm
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMessage(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		data     string
+		expected any
+	}{
+		{
+			name: "contact info",
+			data: `<?xml version="1.0" encoding="utf-8"?>
+<contactinfo>
+	<app>N1MM</app>
+	<contestname>CQWWCW</contestname>
+	<timestamp>2025-10-25 12:15:30</timestamp>
+	<mycall>DL1ABC</mycall>
+	<band>14</band>
+	<rxfreq>1402500</rxfreq>
+	<txfreq>1402500</txfreq>
+	<operator>DL2XYZ</operator>
+	<mode>CW</mode>
+	<call>W1AW</call>
+	<exchange1>5</exchange1>
+	<StationName>RUN1</StationName>
+</contactinfo>`,
+			expected: &ContactInfo{Timestamp: "2025-10-25 12:15:30", Call: "W1AW", RXFrequency: 1402500, TXFrequency: 1402500, Mode: "CW", Band: "14", Exchange: "5", Operator: "DL2XYZ", StationName: "RUN1"},
+		},
+		{
+			name:     "contact replace",
+			data:     `<contactreplace><timestamp>2025-10-25 12:16:00</timestamp><call>W1AW</call><rxfreq>702500</rxfreq><mode>CW</mode></contactreplace>`,
+			expected: &ContactInfo{Timestamp: "2025-10-25 12:16:00", Call: "W1AW", RXFrequency: 702500, Mode: "CW"},
+		},
+		{
+			name:     "contact delete",
+			data:     `<?xml version="1.0" encoding="utf-8"?><contactdelete><app>N1MM</app><timestamp>2025-10-25 12:17:00</timestamp><call>W1AW</call><contestnr>3</contestnr></contactdelete>`,
+			expected: &ContactDelete{Timestamp: "2025-10-25 12:17:00", Call: "W1AW"},
+		},
+		{
+			name: "spot",
+			data: `<?xml version="1.0" encoding="utf-8"?>
+<spot>
+	<app>N1MM</app>
+	<StationName>RUN1</StationName>
+	<dxcall>K1TTT</dxcall>
+	<frequency>14025.3</frequency>
+	<spottercall>W3LPL</spottercall>
+	<comment>CW 599</comment>
+	<action>add</action>
+	<mode>CW</mode>
+	<status></status>
+	<timestamp>2025-10-25 12:18:00</timestamp>
+</spot>`,
+			expected: &Spot{Action: "add", DXCall: "K1TTT", Frequency: 14025.3, SpotterCall: "W3LPL", Comment: "CW 599", Mode: "CW", Timestamp: "2025-10-25 12:18:00"},
+		},
+		{
+			name:     "radio info",
+			data:     `<?xml version="1.0" encoding="utf-8"?><RadioInfo><app>N1MM</app><StationName>RUN1</StationName><RadioNr>1</RadioNr><Freq>1402500</Freq><TXFreq>1402500</TXFreq><Mode>CW</Mode><OpCall>DL2XYZ</OpCall><IsRunning>True</IsRunning></RadioInfo>`,
+			expected: &RadioInfo{StationName: "RUN1", RXFrequency: 1402500, TXFrequency: 1402500, Mode: "CW", OpCall: "DL2XYZ"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := parseMessage([]byte(tc.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			clearXMLName(actual)
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("expected\n%+v\ngot\n%+v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseInvalidMessage(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		data        string
+		unsupported bool
+	}{
+		{name: "empty", data: ""},
+		{name: "no XML", data: "hello"},
+		{name: "unsupported message", data: `<AppInfo><app>N1MM</app></AppInfo>`, unsupported: true},
+		{name: "truncated", data: `<spot><dxcall>K1TTT</dxcall><frequency>14025`},
+		{name: "invalid frequency", data: `<spot><dxcall>K1TTT</dxcall><frequency>fourteen</frequency></spot>`},
+		{name: "oversized", data: `<spot><comment>` + strings.Repeat("<x>", 20000)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := parseMessage([]byte(tc.data))
+			if err == nil {
+				t.Fatalf("expected an error, got %+v", actual)
+			}
+			if unsupported := errors.Is(err, errUnsupportedMessage); unsupported != tc.unsupported {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	for _, tc := range []struct {
+		value    string
+		expected time.Time
+	}{
+		{"2025-10-25 12:15:30", time.Date(2025, 10, 25, 12, 15, 30, 0, time.UTC)},
+		{"2025-10-25T12:15:30", time.Time{}},
+		{"", time.Time{}},
+	} {
+		if actual := parseTimestamp(tc.value); !actual.Equal(tc.expected) {
+			t.Errorf("%q: expected %v, got %v", tc.value, tc.expected, actual)
+		}
+	}
+}
+
+// clearXMLName removes the names of the root elements, they are not relevant for the comparison.
+func clearXMLName(message any) {
+	switch message := message.(type) {
+	case *ContactInfo:
+		message.XMLName.Local = ""
+	case *ContactDelete:
+		message.XMLName.Local = ""
+	case *Spot:
+		message.XMLName.Local = ""
+	case *RadioInfo:
+		message.XMLName.Local = ""
+	}
+}
@@ -0,0 +1,184 @@
+package godxmap
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BandOpeningConfig controls how band openings are detected in the stream of DX spots.
+//
+// A band opening is detected when at least MinSpots spots for the same band and region
+// arrive within Window, and this rate is at least Factor times the average rate observed during Baseline.
+// No openings are detected before the spots were observed for a full Baseline.
+type BandOpeningConfig struct {
+	// Window is the period in which the recent activity is measured.
+	Window time.Duration
+	// Baseline is the period in which the usual activity is measured. It must be longer than Window.
+	Baseline time.Duration
+	// MinSpots is the minimum number of spots within Window to detect an opening.
+	MinSpots int
+	// Factor is the minimum ratio between the recent and the usual activity to detect an opening.
+	Factor float64
+	// Holdoff is the period after an announcement in which the same band and region is not announced again.
+	Holdoff time.Duration
+	// Region returns the region of the given spot, e.g. the continent of the spotted station.
+	// If Region is nil, all spots of a band belong to the same region.
+	Region func(spot string, spotter string) string
+}
+
+// DefaultBandOpeningConfig is suitable to detect short-lived openings like sporadic E on 6m.
+var DefaultBandOpeningConfig = BandOpeningConfig{
+	Window:   10 * time.Minute,
+	Baseline: 2 * time.Hour,
+	MinSpots: 5,
+	Factor:   3,
+	Holdoff:  30 * time.Minute,
+}
+
+// Validate reports an error if the given config cannot be used to detect band openings.
+func (c BandOpeningConfig) Validate() error {
+	if c.Window <= 0 {
+		return errors.New("the window of the band opening detection must be positive")
+	}
+	if c.Baseline <= c.Window {
+		return errors.New("the baseline of the band opening detection must be longer than the window")
+	}
+	return nil
+}
+
+// BandOpening describes a detected sudden increase of activity on a band from a region.
+type BandOpening struct {
+	Band   Band
+	Region string
+	Spots  int
+	Since  time.Time
+}
+
+// WithBandOpeningDetection analyzes the spots shown with [Server.ShowDXSpot] for band openings.
+// Every detected opening is announced with a gab message and a "BandOpening" frame.
+// If the given config is invalid, [Server.Serve] returns the error of [BandOpeningConfig.Validate].
+func WithBandOpeningDetection(config BandOpeningConfig) Option {
+	return func(s *Server) {
+		detector, err := newOpeningDetector(config)
+		if err != nil {
+			s.invalidOption(err)
+			return
+		}
+		s.openings = detector
+	}
+}
+
+type openingKey struct {
+	band   Band
+	region string
+}
+
+type openingDetector struct {
+	config BandOpeningConfig
+
+	mutex     sync.Mutex
+	started   time.Time
+	pruned    time.Time
+	spots     map[openingKey][]time.Time
+	announced map[openingKey]time.Time
+}
+
+func newOpeningDetector(config BandOpeningConfig) (*openingDetector, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("cannot detect band openings: %v", err)
+	}
+	return &openingDetector{
+		config:    config,
+		spots:     make(map[openingKey][]time.Time),
+		announced: make(map[openingKey]time.Time),
+	}, nil
+}
+
+// Add records the given spot and reports if it indicates a new band opening.
+func (d *openingDetector) Add(spot string, spotter string, frequencyKHz float64, now time.Time) (BandOpening, bool) {
+	band := BandOf(frequencyKHz)
+	if band == NoBand {
+		return BandOpening{}, false
+	}
+	key := openingKey{band: band}
+	if d.config.Region != nil {
+		key.region = d.config.Region(spot, spotter)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.started.IsZero() {
+		d.started = now
+		d.pruned = now
+	}
+	baselineStart := now.Add(-d.config.Baseline)
+	if now.Sub(d.pruned) >= d.config.Window {
+		d.prune(baselineStart, now)
+		d.pruned = now
+	}
+
+	timestamps := d.spots[key]
+	for len(timestamps) > 0 && timestamps[0].Before(baselineStart) {
+		timestamps = timestamps[1:]
+	}
+	timestamps = append(timestamps, now)
+	d.spots[key] = timestamps
+
+	windowStart := now.Add(-d.config.Window)
+	recent := 0
+	for i := len(timestamps) - 1; i >= 0 && !timestamps[i].Before(windowStart); i-- {
+		recent++
+	}
+	if recent < d.config.MinSpots {
+		return BandOpening{}, false
+	}
+	if now.Sub(d.started) < d.config.Baseline {
+		// the usual activity is not known yet
+		return BandOpening{}, false
+	}
+
+	usual := len(timestamps) - recent
+	usualRate := float64(usual) / float64(d.config.Baseline-d.config.Window)
+	recentRate := float64(recent) / float64(d.config.Window)
+	if recentRate < d.config.Factor*usualRate {
+		return BandOpening{}, false
+	}
+
+	if lastAnnouncement, ok := d.announced[key]; ok && now.Sub(lastAnnouncement) < d.config.Holdoff {
+		return BandOpening{}, false
+	}
+	d.announced[key] = now
+
+	return BandOpening{
+		Band:   band,
+		Region: key.region,
+		Spots:  recent,
+		Since:  timestamps[len(timestamps)-recent],
+	}, true
+}
+
+// prune removes the bands and regions without spots since the given baseline start and the announcements
+// that are out of their holdoff. d.mutex must be held.
+func (d *openingDetector) prune(baselineStart time.Time, now time.Time) {
+	for key, timestamps := range d.spots {
+		if len(timestamps) == 0 || timestamps[len(timestamps)-1].Before(baselineStart) {
+			delete(d.spots, key)
+		}
+	}
+	for key, announced := range d.announced {
+		if now.Sub(announced) >= d.config.Holdoff {
+			delete(d.announced, key)
+		}
+	}
+}
+
+func (o BandOpening) String() string {
+	if o.Region == "" {
+		return fmt.Sprintf("%s is open: %d spots since %s", o.Band, o.Spots, o.Since.UTC().Format("15:04Z"))
+	}
+	return fmt.Sprintf("%s is open to %s: %d spots since %s", o.Band, o.Region, o.Spots, o.Since.UTC().Format("15:04Z"))
+}
@@ -0,0 +1,69 @@
+package godxmap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOpeningDetector(t *testing.T) {
+	config := BandOpeningConfig{
+		Window:   10 * time.Minute,
+		Baseline: time.Hour,
+		MinSpots: 3,
+		Factor:   3,
+		Holdoff:  30 * time.Minute,
+	}
+	detector, err := newOpeningDetector(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC)
+
+	// a quiet band during the baseline
+	for i := range 4 {
+		_, detected := detector.Add("DL1ABC", "W1AW", 50150, start.Add(time.Duration(i)*15*time.Minute))
+		if detected {
+			t.Fatalf("opening detected during the baseline at spot %d", i)
+		}
+	}
+	// a burst of spots after the baseline
+	now := start.Add(time.Hour)
+	var opening BandOpening
+	var detected bool
+	for i := range 3 {
+		opening, detected = detector.Add("DL2XYZ", "W1AW", 50150, now.Add(time.Duration(i)*time.Minute))
+	}
+	if !detected {
+		t.Fatal("opening not detected")
+	}
+	if opening.Band != Band6m || opening.Spots != 3 {
+		t.Errorf("unexpected opening: %+v", opening)
+	}
+
+	// the same band is not announced again during the holdoff
+	_, detected = detector.Add("DL3DEF", "W1AW", 50150, now.Add(5*time.Minute))
+	if detected {
+		t.Error("opening announced again during the holdoff")
+	}
+}
+
+func TestInvalidOpeningConfig(t *testing.T) {
+	tt := []struct {
+		name   string
+		config BandOpeningConfig
+	}{
+		{"without window", BandOpeningConfig{Baseline: time.Hour}},
+		{"baseline shorter than window", BandOpeningConfig{Window: time.Hour, Baseline: 10 * time.Minute}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			server := NewServer("127.0.0.1:0", WithBandOpeningDetection(tc.config))
+			defer server.Close()
+
+			err := server.Serve()
+			if err == nil || err.Error() != "cannot detect band openings: "+tc.config.Validate().Error() {
+				t.Errorf("expected the error of the invalid config, got %v", err)
+			}
+		})
+	}
+}
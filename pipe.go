@@ -0,0 +1,166 @@
+package godxmap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// PipeRemoteAddrPrefix is the prefix of the remote address of the clients that are connected through a pipe,
+// see [Server.Pipe] and [PipeConn.RemoteAddr].
+const PipeRemoteAddrPrefix = "pipe-"
+
+const pipeQueueSize = 64
+
+// PipeConn is the client side of an in-memory connection to a [Server], see [Server.Pipe].
+type PipeConn struct {
+	remoteAddr string
+	messages   chan []byte
+	incoming   chan []byte
+	closed     chan struct{}
+	closeOnce  sync.Once
+}
+
+// Pipe connects a client to the server through an in-memory connection instead of a websocket, e.g. to test the output
+// of the server without opening a network connection. The query contains the parameters that a map client would add
+// to the websocket URL, e.g. "bands=20m&modes=CW", see [BandsParameter], [ModesParameter] and [ResumeParameter].
+// The authentication of the server does not apply to pipes.
+//
+// Pipe returns when the client is registered, the client receives all frames that are sent afterwards.
+func (s *Server) Pipe(query string) (*PipeConn, error) {
+	values, err := url.ParseQuery(query)
+	if err == nil {
+		_, err = requestFilter(values)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect pipe: %v", err)
+	}
+	select {
+	case <-s.closing:
+		return nil, http.ErrServerClosed
+	default:
+	}
+
+	result := &PipeConn{
+		remoteAddr: fmt.Sprintf("%s%d", PipeRemoteAddrPrefix, s.pipes.Add(1)),
+		messages:   make(chan []byte, pipeQueueSize),
+		incoming:   make(chan []byte, pipeQueueSize),
+		closed:     make(chan struct{}),
+	}
+	r := &http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: "/", RawQuery: values.Encode()},
+		Header:     make(http.Header),
+		RemoteAddr: result.remoteAddr,
+	}
+	c := s.newConnection(pipeServerConn{result}, r)
+	go s.serve(c)
+
+	select {
+	case <-c.registered:
+		return result, nil
+	case <-s.closed:
+		return nil, http.ErrServerClosed
+	}
+}
+
+// RemoteAddr returns the unique remote address of the client on the server side of the pipe, e.g. to send frames
+// only to this client with [Server.SendTo].
+func (p *PipeConn) RemoteAddr() string {
+	return p.remoteAddr
+}
+
+// Receive blocks until the next websocket message is sent to the client, or until the given context is done.
+// The message contains a single frame as JSON object or a batch of frames as JSON array, see [DecodeMessage].
+// When the pipe is closed and all messages are received, Receive returns [io.EOF].
+func (p *PipeConn) Receive(ctx context.Context) ([]byte, error) {
+	select {
+	case data := <-p.messages:
+		return data, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-p.closed:
+		select {
+		case data := <-p.messages:
+			return data, nil
+		default:
+			return nil, io.EOF
+		}
+	}
+}
+
+// Send sends the given frame from the client to the server, like a map client sends e.g. a gab message or a subscription.
+// The frame type is filled in, if it is empty, the other empty header fields are not.
+func (p *PipeConn) Send(f Frame) error {
+	if f.Header().Frame == "" {
+		f.Header().Frame = f.FrameType()
+	}
+	data, err := EncodeFrame(f)
+	if err != nil {
+		return err
+	}
+	select {
+	case p.incoming <- data:
+		return nil
+	case <-p.closed:
+		return net.ErrClosed
+	}
+}
+
+// Close closes the pipe. The server handles this like a closed websocket connection.
+func (p *PipeConn) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.closed)
+	})
+	return nil
+}
+
+// pipeServerConn is the server side of a pipe.
+type pipeServerConn struct {
+	pipe *PipeConn
+}
+
+func (c pipeServerConn) WriteMessage(data []byte, timeout time.Duration) error {
+	// the timer is only needed if the client does not keep up
+	select {
+	case c.pipe.messages <- data:
+		return nil
+	case <-c.pipe.closed:
+		return net.ErrClosed
+	default:
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case c.pipe.messages <- data:
+		return nil
+	case <-c.pipe.closed:
+		return net.ErrClosed
+	case <-timer.C:
+		return errors.New("pipe write timeout")
+	}
+}
+
+func (c pipeServerConn) ReadMessage() ([]byte, error) {
+	select {
+	case data := <-c.pipe.incoming:
+		return data, nil
+	case <-c.pipe.closed:
+		return nil, io.EOF
+	}
+}
+
+func (c pipeServerConn) Close() error {
+	return c.pipe.Close()
+}
+
+func (c pipeServerConn) RemoteAddr() string {
+	return c.pipe.remoteAddr
+}
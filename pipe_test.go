@@ -0,0 +1,120 @@
+package godxmap_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+const pipeTimeout = 5 * time.Second
+
+func TestSendToOnlyOnePipe(t *testing.T) {
+	var server *godxmap.Server
+	server = godxmap.NewServer("127.0.0.1:0", godxmap.WithClientFrameHandler(func(f godxmap.Frame, client godxmap.ClientID, _ string) {
+		server.SendTo(client, &godxmap.GabFrame{From: "server", Message: "response to " + f.(*godxmap.GabFrame).Message})
+	}))
+	defer server.Close()
+
+	sender, err := server.Pipe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sender.Close()
+	other, err := server.Pipe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	if sender.RemoteAddr() == other.RemoteAddr() {
+		t.Errorf("both pipes have the remote address %s", sender.RemoteAddr())
+	}
+
+	err = sender.Send(&godxmap.GabFrame{From: "DL1ABC", Message: "sh/dx"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pipeTimeout)
+	defer cancel()
+	data, err := sender.Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := godxmap.DecodeFrame(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gab, ok := f.(*godxmap.GabFrame); !ok || gab.Message != "response to sh/dx" {
+		t.Errorf("unexpected response: %v", f)
+	}
+
+	// a broadcast after the response reaches the other pipe first if the response was sent to both
+	err = server.ShowGab("server", "", "broadcast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = other.Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err = godxmap.DecodeFrame(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gab, ok := f.(*godxmap.GabFrame); !ok || gab.Message != "broadcast" {
+		t.Errorf("the other pipe received the response: %v", f)
+	}
+}
+
+func TestSendToDisconnectedClient(t *testing.T) {
+	clients := make(chan godxmap.ClientID, 1)
+	server := godxmap.NewServer("127.0.0.1:0", godxmap.WithClientFrameHandler(func(_ godxmap.Frame, client godxmap.ClientID, _ string) {
+		clients <- client
+	}))
+	defer server.Close()
+
+	pipe, err := server.Pipe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pipe.Send(&godxmap.GabFrame{From: "DL1ABC", Message: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var client godxmap.ClientID
+	select {
+	case client = <-clients:
+	case <-time.After(pipeTimeout):
+		t.Fatal("the client frame was not handled")
+	}
+	pipe.Close()
+
+	// a new client never gets the frames for the old one
+	next, err := server.Pipe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer next.Close()
+	err = server.SendTo(client, &godxmap.GabFrame{From: "server", Message: "too late"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = server.ShowGab("server", "", "broadcast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pipeTimeout)
+	defer cancel()
+	data, err := next.Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := godxmap.DecodeFrame(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gab, ok := f.(*godxmap.GabFrame); !ok || gab.Message != "broadcast" {
+		t.Errorf("the new client received the frame for the old one: %v", f)
+	}
+}
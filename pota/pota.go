@@ -0,0 +1,182 @@
+// The package pota polls the activator spots of Parks on the Air (POTA) and shows them at the position of the park
+// on the map of a [godxmap.Server].
+package pota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+// DefaultURL is the address of the POTA activator spots API.
+const DefaultURL = "https://api.pota.app/spot/activator"
+
+const (
+	defaultInterval = time.Minute
+	requestTimeout  = 10 * time.Second
+)
+
+// Spot is an activator spot as it is provided by the POTA API.
+type Spot struct {
+	ID        int64   `json:"spotId"`
+	Activator string  `json:"activator"`
+	Frequency string  `json:"frequency"`
+	Mode      string  `json:"mode"`
+	Reference string  `json:"reference"`
+	ParkName  string  `json:"name"`
+	SpotTime  string  `json:"spotTime"`
+	Spotter   string  `json:"spotter"`
+	Comments  string  `json:"comments"`
+	Grid6     string  `json:"grid6"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Invalid   *bool   `json:"invalid"`
+}
+
+// Poller polls the POTA API and shows every new activator spot as DX spot at the position of the park.
+type Poller struct {
+	url      string
+	server   *godxmap.Server
+	interval time.Duration
+	client   *http.Client
+	source   godxmap.SourceRunner
+}
+
+// Option configures a [Poller] instance.
+type Option func(*Poller)
+
+// WithInterval sets the polling interval. The default is one minute.
+func WithInterval(interval time.Duration) Option {
+	return func(p *Poller) {
+		p.interval = interval
+	}
+}
+
+// WithURL polls the given URL instead of [DefaultURL].
+func WithURL(url string) Option {
+	return func(p *Poller) {
+		p.url = url
+	}
+}
+
+// NewPoller creates a new poller that feeds the given server. To actually start polling, use the Run method.
+func NewPoller(server *godxmap.Server, options ...Option) *Poller {
+	result := &Poller{
+		url:      DefaultURL,
+		server:   server,
+		interval: defaultInterval,
+		client:   &http.Client{Timeout: requestTimeout},
+	}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+// Run polls the POTA API until the given context is done. Failed requests are logged and retried with the next poll.
+func (p *Poller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	// the API returns all current spots with every request, only the new ones are shown
+	seen := make(map[int64]bool)
+	for {
+		spots, err := p.poll(ctx)
+		if err != nil && ctx.Err() == nil {
+			p.server.Logger().Warn("cannot poll POTA spots", "error", err)
+		}
+		if err == nil {
+			current := make(map[int64]bool, len(spots))
+			for _, spot := range spots {
+				current[spot.ID] = true
+				if !seen[spot.ID] {
+					p.show(spot)
+				}
+			}
+			seen = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Start runs the poller in the background. The activator spots are emitted on the given channel instead of
+// being shown directly. Start, Stop and Status implement [godxmap.SpotSource] and [godxmap.SourceStatus].
+func (p *Poller) Start(spots chan<- godxmap.Spot) error {
+	return p.source.Start(spots, p.Run)
+}
+
+// Stop stops the poller that was started with Start.
+func (p *Poller) Stop() {
+	p.source.Stop()
+}
+
+// Status reports if the poller that was started with Start is still running.
+func (p *Poller) Status() (bool, error) {
+	return p.source.Status()
+}
+
+func (p *Poller) showSpot(spot godxmap.Spot) error {
+	if p.source.Emit(spot) {
+		return nil
+	}
+	return p.server.ShowSpot(spot)
+}
+
+func (p *Poller) poll(ctx context.Context) ([]Spot, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := p.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", response.Status)
+	}
+
+	var result []Spot
+	err = json.NewDecoder(response.Body).Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("invalid response: %v", err)
+	}
+	return result, nil
+}
+
+func (p *Poller) show(spot Spot) {
+	if spot.Invalid != nil && *spot.Invalid {
+		return
+	}
+	frequencyKHz, err := strconv.ParseFloat(strings.TrimSpace(spot.Frequency), 64)
+	if err != nil {
+		p.server.Logger().Warn("invalid frequency of POTA spot", "id", spot.ID, "frequency", spot.Frequency)
+		return
+	}
+
+	dxSpot := godxmap.Spot{
+		DX:           spot.Activator,
+		Spotter:      spot.Spotter,
+		FrequencyKHz: frequencyKHz,
+		Comments:     strings.TrimSpace(fmt.Sprintf("%s POTA %s %s %s", spot.Mode, spot.Reference, spot.ParkName, spot.Comments)),
+		Locator:      spot.Grid6,
+	}
+	if spot.Latitude != 0 || spot.Longitude != 0 {
+		dxSpot.Latitude, dxSpot.Longitude = &spot.Latitude, &spot.Longitude
+	}
+	err = p.showSpot(dxSpot)
+	if err != nil {
+		p.server.Logger().Warn("cannot show POTA spot", "call", spot.Activator, "error", err)
+	}
+}
@@ -0,0 +1,35 @@
+package godxmap
+
+import (
+	"net/http"
+	"net/http/pprof"
+)
+
+// ProfilingPrefix is the path prefix of the profiling endpoints, see [WithProfiling].
+const ProfilingPrefix = "/debug/pprof/"
+
+// WithProfiling provides the runtime profiles of the process on the same address as the websocket, served by the
+// handlers of net/http/pprof, e.g. to find out why the broadcast latency climbs during a contest:
+//
+//	go tool pprof http://localhost:8080/debug/pprof/heap
+//	go tool pprof http://localhost:8080/debug/pprof/profile?seconds=30
+//	curl -o trace.out http://localhost:8080/debug/pprof/trace?seconds=5
+//
+// The endpoints are served on the address of this server only, not through [http.DefaultServeMux].
+// The profiling endpoints require the same token as the websocket, if [WithTokenAuthentication] is used.
+func WithProfiling() Option {
+	return func(s *Server) {
+		s.profiling = true
+	}
+}
+
+// profilingHandler mounts the handlers of net/http/pprof on a private mux.
+func profilingHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(ProfilingPrefix, pprof.Index)
+	mux.HandleFunc(ProfilingPrefix+"cmdline", pprof.Cmdline)
+	mux.HandleFunc(ProfilingPrefix+"profile", pprof.Profile)
+	mux.HandleFunc(ProfilingPrefix+"symbol", pprof.Symbol)
+	mux.HandleFunc(ProfilingPrefix+"trace", pprof.Trace)
+	return mux
+}
@@ -0,0 +1,164 @@
+// The package pskreporter subscribes to the MQTT feed of PSK Reporter and shows the reception reports
+// as spots on the map of a [godxmap.Server].
+package pskreporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/ftl/godxmap"
+)
+
+// DefaultBroker is the address of the public MQTT broker that provides the PSK Reporter feed.
+const DefaultBroker = "tcp://mqtt.pskreporter.info:1883"
+
+const connectTimeout = 10 * time.Second
+
+// Report is a single reception report.
+type Report struct {
+	SenderCall      string `json:"sc"`
+	SenderLocator   string `json:"sl"`
+	ReceiverCall    string `json:"rc"`
+	ReceiverLocator string `json:"rl"`
+	FrequencyHz     int64  `json:"f"`
+	Mode            string `json:"md"`
+	// SNR is the reported signal to noise ratio in dB.
+	SNR int `json:"rp"`
+	// Timestamp in Unix seconds.
+	Timestamp int64  `json:"t"`
+	Band      string `json:"b"`
+}
+
+// Filter restricts the subscribed reports. Empty fields match everything.
+type Filter struct {
+	Band            godxmap.Band
+	Mode            godxmap.Mode
+	SenderCall      string
+	ReceiverCall    string
+	SenderLocator   string
+	ReceiverLocator string
+}
+
+// Topic returns the MQTT topic for this filter.
+// The topic structure is pskr/filter/v2/{band}/{mode}/{sendercall}/{receivercall}/{senderlocator}/{receiverlocator}/{sendercountry}/{receivercountry}.
+// The locators are given as four character squares.
+func (f Filter) Topic() string {
+	levels := []string{
+		string(f.Band),
+		string(f.Mode),
+		strings.ToUpper(f.SenderCall),
+		strings.ToUpper(f.ReceiverCall),
+		square(f.SenderLocator),
+		square(f.ReceiverLocator),
+		"",
+		"",
+	}
+	for i, level := range levels {
+		if level == "" {
+			levels[i] = "+"
+		}
+	}
+	return "pskr/filter/v2/" + strings.Join(levels, "/")
+}
+
+func square(locator string) string {
+	if len(locator) > 4 {
+		locator = locator[:4]
+	}
+	return strings.ToUpper(locator)
+}
+
+// Feed subscribes to the PSK Reporter MQTT feed and shows every report as spot on the map of a server.
+type Feed struct {
+	broker string
+	filter Filter
+	server *godxmap.Server
+	source godxmap.SourceRunner
+}
+
+// NewFeed creates a new feed for the given broker and filter. To actually subscribe, use the Run method.
+func NewFeed(broker string, filter Filter, server *godxmap.Server) *Feed {
+	return &Feed{
+		broker: broker,
+		filter: filter,
+		server: server,
+	}
+}
+
+// Run subscribes to the feed and shows the received reports until the given context is done.
+func (f *Feed) Run(ctx context.Context) error {
+	options := mqtt.NewClientOptions().
+		AddBroker(f.broker).
+		SetClientID(fmt.Sprintf("godxmap-%d", time.Now().UnixNano())).
+		SetAutoReconnect(true)
+	client := mqtt.NewClient(options)
+	token := client.Connect()
+	if !token.WaitTimeout(connectTimeout) {
+		return fmt.Errorf("cannot connect to %s: timeout", f.broker)
+	}
+	if err := token.Error(); err != nil {
+		return fmt.Errorf("cannot connect to %s: %v", f.broker, err)
+	}
+	defer client.Disconnect(250)
+
+	token = client.Subscribe(f.filter.Topic(), 0, func(_ mqtt.Client, message mqtt.Message) {
+		f.handle(message.Payload())
+	})
+	if !token.WaitTimeout(connectTimeout) {
+		return fmt.Errorf("cannot subscribe to %s: timeout", f.filter.Topic())
+	}
+	if err := token.Error(); err != nil {
+		return fmt.Errorf("cannot subscribe to %s: %v", f.filter.Topic(), err)
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
+// Start runs the feed in the background. The reports are emitted on the given channel instead of
+// being shown directly. Start, Stop and Status implement [godxmap.SpotSource] and [godxmap.SourceStatus].
+func (f *Feed) Start(spots chan<- godxmap.Spot) error {
+	return f.source.Start(spots, f.Run)
+}
+
+// Stop stops the feed that was started with Start.
+func (f *Feed) Stop() {
+	f.source.Stop()
+}
+
+// Status reports if the feed that was started with Start is still running.
+func (f *Feed) Status() (bool, error) {
+	return f.source.Status()
+}
+
+func (f *Feed) showSpot(spot godxmap.Spot) error {
+	if f.source.Emit(spot) {
+		return nil
+	}
+	return f.server.ShowSpot(spot)
+}
+
+func (f *Feed) handle(payload []byte) {
+	var report Report
+	err := json.Unmarshal(payload, &report)
+	if err != nil {
+		f.server.Logger().Warn("invalid PSK Reporter report", "error", err)
+		return
+	}
+
+	err = f.showSpot(godxmap.Spot{
+		Time:         time.Unix(report.Timestamp, 0),
+		DX:           report.SenderCall,
+		Spotter:      report.ReceiverCall,
+		FrequencyKHz: float64(report.FrequencyHz) / 1000,
+		Comments:     fmt.Sprintf("%s %+d dB", report.Mode, report.SNR),
+	})
+	if err != nil {
+		f.server.Logger().Warn("cannot show PSK Reporter report", "call", report.SenderCall, "error", err)
+	}
+}
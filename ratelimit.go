@@ -0,0 +1,248 @@
+package godxmap
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimit defines a token bucket: it allows Rate frames per second on average and bursts of up to Burst frames.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitConfig defines the rate limits of the broadcast path.
+type RateLimitConfig struct {
+	// RateLimit applies to all frames. If the rate is zero, there is no global limit.
+	RateLimit
+	// FrameTypes defines additional limits for single frame types, e.g. for DX spots.
+	FrameTypes map[string]RateLimit
+	// Drop drops the frames that exceed the rate limit. Otherwise, the frames are delayed until they fit in.
+	Drop bool
+}
+
+// WithRateLimit limits the rate of the frames that are broadcast to the clients. This smoothes out sudden bursts,
+// e.g. of cluster spots during a pileup, that would otherwise overwhelm the browsers. Under high memory pressure,
+// the rate limiter always drops the exceeding frames and halves the rates, under critical memory pressure, the rates
+// are reduced to a quarter.
+func WithRateLimit(config RateLimitConfig) Option {
+	return func(s *Server) {
+		s.rateLimiter = newRateLimiter(config)
+	}
+}
+
+type rateLimiter struct {
+	global     *tokenBucket
+	frameTypes map[string]*tokenBucket
+	drop       bool
+
+	mutex    sync.Mutex
+	pressure MemoryPressure
+
+	// takeLock makes taking the tokens from several buckets atomic
+	takeLock sync.Mutex
+}
+
+func newRateLimiter(config RateLimitConfig) *rateLimiter {
+	result := &rateLimiter{
+		frameTypes: make(map[string]*tokenBucket, len(config.FrameTypes)),
+		drop:       config.Drop,
+	}
+	if config.Rate > 0 {
+		result.global = newTokenBucket(config.RateLimit)
+	}
+	for frameType, limit := range config.FrameTypes {
+		if limit.Rate > 0 {
+			result.frameTypes[frameType] = newTokenBucket(limit)
+		}
+	}
+	return result
+}
+
+func (l *rateLimiter) dropping() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.drop || l.pressure > MemoryPressureNormal
+}
+
+func (l *rateLimiter) Shed(pressure MemoryPressure) {
+	l.mutex.Lock()
+	l.pressure = pressure
+	l.mutex.Unlock()
+
+	factor := 1.0
+	switch pressure {
+	case MemoryPressureHigh:
+		factor = 0.5
+	case MemoryPressureCritical:
+		factor = 0.25
+	}
+	if l.global != nil {
+		l.global.Scale(factor)
+	}
+	for _, bucket := range l.frameTypes {
+		bucket.Scale(factor)
+	}
+}
+
+// Limit returns the frames of the given message that may be sent, after waiting for the rate limit if necessary.
+// The wait ends early when the given done channel is closed. If no frame may be sent, Limit returns false.
+func (l *rateLimiter) Limit(m message, done <-chan struct{}) (message, bool) {
+	drop := l.dropping()
+	frames := make([]Frame, 0, len(m.frames))
+	var delay time.Duration
+	for _, f := range m.frames {
+		buckets := []*tokenBucket{l.frameTypes[f.FrameType()], l.global}
+		if drop {
+			if l.takeAll(buckets) {
+				frames = append(frames, f)
+			}
+			continue
+		}
+		delay = max(delay, l.reserveAll(buckets))
+		frames = append(frames, f)
+	}
+	if len(frames) == 0 {
+		return message{}, false
+	}
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-done:
+		}
+	}
+	return message{frames: frames, batch: m.batch, to: m.to}, true
+}
+
+// reserveAll reserves a token in all the given buckets and returns how long to wait until all tokens are available.
+func (l *rateLimiter) reserveAll(buckets []*tokenBucket) time.Duration {
+	l.takeLock.Lock()
+	defer l.takeLock.Unlock()
+
+	var result time.Duration
+	for _, bucket := range buckets {
+		if bucket != nil {
+			result = max(result, bucket.Reserve())
+		}
+	}
+	return result
+}
+
+// takeAll takes a token from all the given buckets, but only if every bucket has a token available.
+func (l *rateLimiter) takeAll(buckets []*tokenBucket) bool {
+	l.takeLock.Lock()
+	defer l.takeLock.Unlock()
+
+	for _, bucket := range buckets {
+		if bucket != nil && !bucket.Available() {
+			return false
+		}
+	}
+	for _, bucket := range buckets {
+		if bucket != nil && !bucket.Take() {
+			return false
+		}
+	}
+	return true
+}
+
+type tokenBucket struct {
+	limit float64
+	burst float64
+
+	mutex  sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	burst := float64(max(limit.Burst, 1))
+	return &tokenBucket{
+		limit:  limit.Rate,
+		burst:  burst,
+		rate:   limit.Rate,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// Scale sets the rate of this bucket to the given fraction of the configured rate.
+func (b *tokenBucket) Scale(factor float64) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill()
+	b.rate = b.limit * factor
+}
+
+// refill adds the tokens since the last refill. b.mutex must be held.
+func (b *tokenBucket) refill() {
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+func (b *tokenBucket) Available() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill()
+	return b.tokens >= 1
+}
+
+// Take takes a token if one is available.
+func (b *tokenBucket) Take() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Reserve takes a token and returns how long to wait until the token is actually available.
+func (b *tokenBucket) Reserve() time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill()
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// broadcast hands the given message over to the run loop, applying the rate limit if configured.
+// It returns the message that was actually sent, or false if the rate limiter dropped all frames of the message.
+// If the server is closed, broadcast returns [http.ErrServerClosed].
+func (s *Server) broadcast(m message) (message, bool, error) {
+	if s.rateLimiter != nil {
+		limited, ok := s.rateLimiter.Limit(m, s.closing)
+		s.stats.drop(len(m.frames) - len(limited.frames))
+		traceDropped(s.logger, m, limited, dropRateLimit)
+		if !ok {
+			return message{}, false, nil
+		}
+		m = limited
+	}
+	select {
+	case <-s.closing:
+		return message{}, false, http.ErrServerClosed
+	default:
+	}
+	select {
+	case s.inbound <- m:
+		return m, true, nil
+	case <-s.closing:
+		return message{}, false, http.ErrServerClosed
+	}
+}
@@ -0,0 +1,95 @@
+package godxmap_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ftl/godxmap"
+	"github.com/ftl/godxmap/godxmaptest"
+)
+
+func TestRateLimitDropsTheExceedingFrames(t *testing.T) {
+	clock := godxmaptest.NewClock(time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC))
+	server := godxmaptest.NewServer(t, godxmap.WithClock(clock.Now), godxmap.WithRateLimit(godxmap.RateLimitConfig{
+		RateLimit: godxmap.RateLimit{Rate: 0.001, Burst: 3},
+		FrameTypes: map[string]godxmap.RateLimit{
+			godxmap.DXSpotFrameType: {Rate: 0.001, Burst: 1},
+		},
+		Drop: true,
+	}))
+	recorder := godxmaptest.NewRecorder(t, server)
+
+	server.ShowDXSpot("DL1ABC", "W1AW", 14025, "")
+	server.ShowDXSpot("DL2ABC", "W1AW", 14025, "")
+	server.ShowGab("W1AW", "", "first")
+	// the pace of the rate limiter follows the wall clock, not the clock of the server
+	clock.Advance(time.Hour)
+	server.ShowGab("W1AW", "", "second")
+	server.ShowGab("W1AW", "", "third")
+
+	frames := recorder.Await(3)
+	if len(frames) != 3 {
+		t.Fatalf("expected 3 frames, got %v", frames)
+	}
+	if spot, ok := frames[0].(*godxmap.DXSpotFrame); !ok || spot.Spot != "DL1ABC" {
+		t.Errorf("unexpected first frame: %v", frames[0])
+	}
+	if gab, ok := frames[2].(*godxmap.GabFrame); !ok || gab.Message != "second" {
+		t.Errorf("unexpected last frame: %v", frames[2])
+	}
+	// give the dropped frames a chance to show up
+	time.Sleep(50 * time.Millisecond)
+	if frames := recorder.Frames(); len(frames) != 3 {
+		t.Errorf("expected the exceeding frames to be dropped, got %v", frames)
+	}
+}
+
+func TestRateLimitDelaysTheExceedingFrames(t *testing.T) {
+	server := godxmaptest.NewServer(t, godxmap.WithRateLimit(godxmap.RateLimitConfig{
+		RateLimit: godxmap.RateLimit{Rate: 20, Burst: 1},
+	}))
+	recorder := godxmaptest.NewRecorder(t, server)
+
+	start := time.Now()
+	for _, message := range []string{"first", "second", "third"} {
+		err := server.ShowGab("W1AW", "", message)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	frames := recorder.Await(3)
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected the frames to be delayed by the rate limit, took only %v", elapsed)
+	}
+	if gab := frames[2].(*godxmap.GabFrame); gab.Message != "third" {
+		t.Errorf("unexpected order of the frames: %v", frames)
+	}
+}
+
+func TestCloseInterruptsTheRateLimit(t *testing.T) {
+	server := godxmaptest.NewServer(t, godxmap.WithRateLimit(godxmap.RateLimitConfig{
+		RateLimit: godxmap.RateLimit{Rate: 0.001, Burst: 1},
+	}))
+
+	err := server.ShowGab("W1AW", "", "first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := make(chan error, 1)
+	go func() {
+		result <- server.ShowGab("W1AW", "", "second")
+	}()
+	time.Sleep(20 * time.Millisecond)
+	server.Close()
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, http.ErrServerClosed) {
+			t.Errorf("expected the server to be closed, got %v", err)
+		}
+	case <-time.After(godxmaptest.DefaultTimeout):
+		t.Fatal("the rate limit still waits after Close")
+	}
+}
@@ -0,0 +1,167 @@
+// The package rbn provides a client for the telnet service of the Reverse Beacon Network (RBN)
+// that feeds the received skimmer spots into a [godxmap.Server].
+package rbn
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/ftl/godxmap"
+	"github.com/ftl/godxmap/cluster"
+)
+
+// The addresses of the RBN telnet service.
+const (
+	CWAddr  = "telnet.reversebeacon.net:7000"
+	FT8Addr = "telnet.reversebeacon.net:7001"
+)
+
+// Spot is a skimmer spot received from the RBN.
+type Spot struct {
+	cluster.Spot
+	Mode godxmap.Mode
+	// SNR is the signal to noise ratio in dB.
+	SNR int
+	// WPM is the speed in words per minute, only for CW spots.
+	WPM int
+	// Type is the type of the transmission, e.g. "CQ", "BEACON" or "NCDXF B".
+	Type string
+}
+
+// FromClusterSpot extracts the RBN specific information from the comment of the given cluster spot,
+// e.g. "CW 24 dB 28 WPM CQ".
+func FromClusterSpot(spot cluster.Spot) Spot {
+	result := Spot{Spot: spot}
+	fields := strings.Fields(spot.Comment)
+	rest := make([]string, 0, len(fields))
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if i == 0 {
+			result.Mode = godxmap.Mode(strings.ToUpper(field))
+			continue
+		}
+		if i+1 < len(fields) {
+			unit := strings.ToUpper(fields[i+1])
+			value, err := strconv.Atoi(field)
+			if err == nil && unit == "DB" {
+				result.SNR = value
+				i++
+				continue
+			}
+			if err == nil && (unit == "WPM" || unit == "BPS") {
+				result.WPM = value
+				i++
+				continue
+			}
+		}
+		rest = append(rest, field)
+	}
+	result.Type = strings.Join(rest, " ")
+	return result
+}
+
+// DXSpot converts this spot into a [godxmap.Spot].
+func (s Spot) DXSpot() godxmap.Spot {
+	result := s.Spot.DXSpot()
+	result.Mode = s.Mode
+	return result
+}
+
+// Filter selects the spots that are forwarded. Empty lists match everything.
+type Filter struct {
+	Bands []godxmap.Band
+	Modes []godxmap.Mode
+	// Calls restricts the spots to the given spotted callsigns, e.g. the own callsign or needed stations.
+	Calls []string
+	// MinSNR is the minimum signal to noise ratio in dB. It is only applied if it is not zero.
+	MinSNR int
+}
+
+// Matches reports if the given spot passes this filter.
+func (f Filter) Matches(spot Spot) bool {
+	if len(f.Bands) > 0 && !contains(f.Bands, godxmap.BandOf(spot.FrequencyKHz)) {
+		return false
+	}
+	if len(f.Modes) > 0 && !contains(f.Modes, spot.Mode) {
+		return false
+	}
+	if len(f.Calls) > 0 && !containsFold(f.Calls, spot.DX) {
+		return false
+	}
+	return f.MinSNR == 0 || spot.SNR >= f.MinSNR
+}
+
+func contains[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// SpotHandler is called for every RBN spot that passes the filter.
+type SpotHandler func(Spot)
+
+// ToServer returns a [SpotHandler] that shows every spot on the map of the given server.
+func ToServer(server *godxmap.Server) SpotHandler {
+	return func(spot Spot) {
+		err := server.ShowSpot(spot.DXSpot())
+		if err != nil {
+			server.Logger().Warn("cannot show RBN spot", "call", spot.DX, "error", err)
+		}
+	}
+}
+
+// Client connects to the RBN telnet service, logs in with the given callsign and forwards all spots that pass the filter.
+type Client struct {
+	client *cluster.Client
+	source godxmap.SourceRunner
+}
+
+// NewClient creates a new RBN client. To actually connect to the RBN, use the Run or the Start method.
+// The handler may be nil if the client is only used as [godxmap.SpotSource].
+func NewClient(addr string, call string, filter Filter, handler SpotHandler, options ...cluster.Option) *Client {
+	result := &Client{}
+	result.client = cluster.NewClient(addr, call, func(clusterSpot cluster.Spot) {
+		spot := FromClusterSpot(clusterSpot)
+		if !filter.Matches(spot) {
+			return
+		}
+		if !result.source.Emit(spot.DXSpot()) && handler != nil {
+			handler(spot)
+		}
+	}, options...)
+	return result
+}
+
+// Run connects to the RBN and processes the received spots until the connection is closed or the given context is done.
+func (c *Client) Run(ctx context.Context) error {
+	return c.client.Run(ctx)
+}
+
+// Start runs the client in the background. The spots that pass the filter are emitted on the given channel instead of
+// being passed to the handler. Start, Stop and Status implement [godxmap.SpotSource] and [godxmap.SourceStatus].
+func (c *Client) Start(spots chan<- godxmap.Spot) error {
+	return c.source.Start(spots, c.Run)
+}
+
+// Stop stops the client that was started with Start.
+func (c *Client) Stop() {
+	c.source.Stop()
+}
+
+// Status reports if the client that was started with Start is still running.
+func (c *Client) Status() (bool, error) {
+	return c.source.Status()
+}
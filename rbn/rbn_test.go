@@ -0,0 +1,104 @@
+package rbn
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ftl/godxmap"
+	"github.com/ftl/godxmap/cluster"
+)
+
+func TestParseRBNSpot(t *testing.T) {
+	now := time.Date(2025, 10, 25, 12, 30, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name     string
+		line     string
+		valid    bool
+		expected Spot
+	}{
+		{
+			name:     "CW spot",
+			line:     "DX de EA5WU-#:    14004.9  OH0R           CW    19 dB  25 WPM  CQ      1229Z",
+			valid:    true,
+			expected: Spot{Mode: godxmap.ModeCW, SNR: 19, WPM: 25, Type: "CQ"},
+		},
+		{
+			name:     "RTTY spot",
+			line:     "DX de W3OA-#:     14082.5  K1TTT          RTTY  12 dB  45 BPS  CQ      1230Z",
+			valid:    true,
+			expected: Spot{Mode: godxmap.ModeRTTY, SNR: 12, WPM: 45, Type: "CQ"},
+		},
+		{
+			name:     "FT8 spot with negative SNR",
+			line:     "DX de DL8LAS-#:   14074.0  JA1ABC         FT8   -12 dB  1530 Hz        1230Z",
+			valid:    true,
+			expected: Spot{Mode: godxmap.ModeFT8, SNR: -12, Type: "1530 Hz"},
+		},
+		{
+			name:     "NCDXF beacon",
+			line:     "DX de VE6WZ-#:    14100.0  4U1UN          CW     6 dB  22 WPM  NCDXF B 1230Z",
+			valid:    true,
+			expected: Spot{Mode: godxmap.ModeCW, SNR: 6, WPM: 22, Type: "NCDXF B"},
+		},
+		{
+			name:     "truncated comment",
+			line:     "DX de EA5WU-#:    14004.9  OH0R           CW    19                    1229Z",
+			valid:    true,
+			expected: Spot{Mode: godxmap.ModeCW, Type: "19"},
+		},
+		{
+			name:     "missing unit",
+			line:     "DX de EA5WU-#:    14004.9  OH0R           CW    dB 25 WPM             1229Z",
+			valid:    true,
+			expected: Spot{Mode: godxmap.ModeCW, WPM: 25, Type: "dB"},
+		},
+		{name: "login prompt", line: "Please enter your call:"},
+		{name: "truncated line", line: "DX de EA5WU-#:    14004.9  OH0R           CW    19 dB"},
+		{name: "oversized line", line: "DX de EA5WU-#:    14004.9  OH0R  " + strings.Repeat("CW ", 50000)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clusterSpot, ok := cluster.ParseSpot(tc.line, now)
+			if ok != tc.valid {
+				t.Fatalf("expected valid %t, got %t", tc.valid, ok)
+			}
+			if !ok {
+				return
+			}
+			actual := FromClusterSpot(clusterSpot)
+			if actual.Spot != clusterSpot {
+				t.Errorf("the cluster spot was not kept: %+v", actual.Spot)
+			}
+			actual.Spot = cluster.Spot{}
+			if actual != tc.expected {
+				t.Errorf("expected\n%+v\ngot\n%+v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	spot := Spot{Spot: cluster.Spot{DX: "OH0R", FrequencyKHz: 14004.9}, Mode: godxmap.ModeCW, SNR: 19}
+	for _, tc := range []struct {
+		name     string
+		filter   Filter
+		expected bool
+	}{
+		{"empty filter", Filter{}, true},
+		{"matching band", Filter{Bands: []godxmap.Band{godxmap.Band40m, godxmap.Band20m}}, true},
+		{"other band", Filter{Bands: []godxmap.Band{godxmap.Band40m}}, false},
+		{"matching mode", Filter{Modes: []godxmap.Mode{godxmap.ModeCW}}, true},
+		{"other mode", Filter{Modes: []godxmap.Mode{godxmap.ModeFT8}}, false},
+		{"matching call", Filter{Calls: []string{"oh0r"}}, true},
+		{"other call", Filter{Calls: []string{"OH0RX"}}, false},
+		{"minimum SNR", Filter{MinSNR: 19}, true},
+		{"SNR too low", Filter{MinSNR: 20}, false},
+		{"all criteria", Filter{Bands: []godxmap.Band{godxmap.Band20m}, Modes: []godxmap.Mode{godxmap.ModeCW}, Calls: []string{"OH0R"}, MinSNR: 10}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.filter.Matches(spot); actual != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, actual)
+			}
+		})
+	}
+}
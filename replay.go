@@ -0,0 +1,241 @@
+package godxmap
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Replayer plays frames back through a server, e.g. the frames of a [FrameStore] for a training session or the
+// review of a contest. The playback can be paused, sped up or slowed down, and moved to another point in time
+// while it is running.
+type Replayer struct {
+	server       *Server
+	frames       []Frame
+	originalTime bool
+
+	lock     sync.Mutex
+	speed    float64
+	paused   bool
+	position int
+	// the replay time anchorTime (Unix milliseconds) corresponds to the wall clock time anchorWall
+	anchorTime int64
+	anchorWall time.Time
+	changed    chan struct{}
+}
+
+// ReplayOption configures a [Replayer] instance.
+type ReplayOption func(*Replayer)
+
+// WithReplaySpeed sets the initial speed of the playback, see [Replayer.SetSpeed]. The default is 1, the original speed.
+func WithReplaySpeed(speed float64) ReplayOption {
+	return func(r *Replayer) {
+		r.speed = max(0, speed)
+	}
+}
+
+// WithOriginalTime keeps the DateTime of the replayed frames. By default, the frames are sent with the time of
+// the playback, so the map shows and expires them as if they were live.
+func WithOriginalTime() ReplayOption {
+	return func(r *Replayer) {
+		r.originalTime = true
+	}
+}
+
+// NewReplayer creates a new replayer for the given frames. The frames are played in the order of their DateTime.
+// To actually start the playback, use the Run method.
+func NewReplayer(server *Server, frames []Frame, options ...ReplayOption) *Replayer {
+	result := &Replayer{
+		server:  server,
+		frames:  slices.Clone(frames),
+		speed:   1,
+		changed: make(chan struct{}),
+	}
+	slices.SortStableFunc(result.frames, func(a, b Frame) int {
+		return cmp.Compare(a.Header().DateTime, b.Header().DateTime)
+	})
+	if len(result.frames) > 0 {
+		result.anchorTime = result.frames[0].Header().DateTime
+	}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+// Run plays the frames back until all frames are sent or the given context is done.
+// Every frame is sent as a copy with a new ID.
+func (r *Replayer) Run(ctx context.Context) error {
+	r.lock.Lock()
+	r.anchorWall = time.Now()
+	r.lock.Unlock()
+
+	for {
+		r.lock.Lock()
+		if r.position >= len(r.frames) {
+			r.lock.Unlock()
+			return nil
+		}
+		changed := r.changed
+		paused := r.paused
+		var wait time.Duration
+		if r.speed > 0 {
+			offset := time.Duration(float64(r.frames[r.position].Header().DateTime-r.anchorTime) * float64(time.Millisecond) / r.speed)
+			wait = time.Until(r.anchorWall.Add(offset))
+		}
+		r.lock.Unlock()
+
+		if paused || wait > 0 {
+			woken, err := r.wait(ctx, paused, wait, changed)
+			if err != nil {
+				return err
+			}
+			if woken {
+				continue
+			}
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		r.lock.Lock()
+		if r.changed != changed {
+			r.lock.Unlock()
+			continue
+		}
+		f := r.frames[r.position]
+		r.position++
+		if r.speed == 0 {
+			r.anchorTime = f.Header().DateTime
+		}
+		r.lock.Unlock()
+
+		err := r.send(f)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// wait waits for the given duration, or until the playback is changed. While paused, wait waits only for a change.
+// It reports if the playback was changed.
+func (r *Replayer) wait(ctx context.Context, paused bool, duration time.Duration, changed <-chan struct{}) (bool, error) {
+	var timeout <-chan time.Time
+	if !paused {
+		timer := time.NewTimer(duration)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	case <-changed:
+		return true, nil
+	case <-timeout:
+		return false, nil
+	}
+}
+
+func (r *Replayer) send(f Frame) error {
+	data, err := EncodeFrame(f)
+	if err != nil {
+		return fmt.Errorf("cannot replay frame %s: %v", f.Header().ID, err)
+	}
+	replayed, err := DecodeFrame(data)
+	if err != nil {
+		return fmt.Errorf("cannot replay frame %s: %v", f.Header().ID, err)
+	}
+	header := replayed.Header()
+	header.ID = ""
+	if !r.originalTime {
+		header.DateTime = 0
+	}
+	err = r.server.Send(replayed)
+	if err != nil {
+		return fmt.Errorf("cannot replay frame %s: %v", f.Header().ID, err)
+	}
+	return nil
+}
+
+// currentTime returns the replay time in Unix milliseconds. The lock must be held.
+func (r *Replayer) currentTime() int64 {
+	if r.paused || r.anchorWall.IsZero() || r.speed == 0 {
+		return r.anchorTime
+	}
+	return r.anchorTime + int64(float64(time.Since(r.anchorWall).Milliseconds())*r.speed)
+}
+
+// reanchor continues the playback from the given replay time and wakes up the Run loop. The lock must be held.
+func (r *Replayer) reanchor(replayTime int64) {
+	r.anchorTime = replayTime
+	r.anchorWall = time.Now()
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// SetSpeed changes the speed of the playback: 1 is the original speed, 60 plays one hour in one minute.
+// With a speed of zero, all remaining frames are sent instantly.
+func (r *Replayer) SetSpeed(speed float64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.currentTime()
+	r.speed = max(0, speed)
+	r.reanchor(now)
+}
+
+// Pause pauses the playback until Resume is called.
+func (r *Replayer) Pause() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.paused {
+		return
+	}
+	now := r.currentTime()
+	r.paused = true
+	r.reanchor(now)
+}
+
+// Resume continues the paused playback.
+func (r *Replayer) Resume() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.paused {
+		return
+	}
+	r.paused = false
+	r.reanchor(r.anchorTime)
+}
+
+// Paused reports if the playback is paused.
+func (r *Replayer) Paused() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.paused
+}
+
+// Seek continues the playback with the first frame at or after the given time. Frames that were already
+// sent are not removed from the map.
+func (r *Replayer) Seek(t time.Time) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	replayTime := t.UnixMilli()
+	r.position, _ = slices.BinarySearchFunc(r.frames, replayTime, func(f Frame, t int64) int {
+		return cmp.Compare(f.Header().DateTime, t)
+	})
+	r.reanchor(replayTime)
+}
+
+// Position returns the current time of the playback.
+func (r *Replayer) Position() time.Time {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return time.UnixMilli(r.currentTime())
+}
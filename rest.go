@@ -0,0 +1,201 @@
+package godxmap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RESTPrefix is the path prefix of the REST API.
+const RESTPrefix = "/api/"
+
+const maxRESTBodySize = 64 * 1024
+
+// restEndpoints maps the REST resources to the frame type that is expected in the request body.
+var restEndpoints = map[string]string{
+	"spots":     DXSpotFrameType,
+	"gab":       GabFrameType,
+	"calls":     LoggedCallFrameType,
+	"partials":  PartialCallFrameType,
+	"clear":     ClearCallFrameType,
+	"status":    StatusFrameType,
+	"heading":   HeadingFrameType,
+	"qth":       StationQTHFrameType,
+	"exchanges": ContestExchangeFrameType,
+}
+
+// WithRESTAPI provides a REST API on the same address as the websocket, so scripts can put things on the map with curl, e.g.:
+//
+//	curl -d '{"Spot":"DL1ABC","Spotter":"K1XYZ","Frequency":14025,"Comments":"CW"}' http://localhost:8080/api/spots
+//
+// The body of a POST request contains the fields of the corresponding frame, the Frame field may be omitted:
+//   - /api/spots: DXSpot
+//   - /api/gab: Gab
+//   - /api/calls: LoggedCall
+//   - /api/partials: PartialCall
+//   - /api/clear: ClearCall
+//   - /api/status: Status
+//   - /api/heading: Heading
+//   - /api/qth: StationQTH
+//   - /api/exchanges: ContestExchange
+//   - /api/frames: any frame, including the Frame field
+//
+// The frames are validated at least with [ValidateRequired].
+//
+// With [WithFrameStore], a GET request to /api/frames returns the stored frames as JSON array. The query parameters
+// since and until limit the time range (RFC 3339 or Unix milliseconds), type limits the frame types (comma separated),
+// and limit returns only the most recent frames.
+//
+// The REST API requires the same token as the websocket, if [WithTokenAuthentication] is used.
+func WithRESTAPI() Option {
+	return func(s *Server) {
+		s.restAPI = true
+	}
+}
+
+func (s *Server) serveREST(w http.ResponseWriter, r *http.Request) {
+	resource := strings.TrimPrefix(r.URL.Path, RESTPrefix)
+	if r.Method == http.MethodGet && resource == "frames" && s.store != nil {
+		s.serveStoredFrames(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	f, err := decodeRESTFrame(resource, http.MaxBytesReader(w, r.Body, maxRESTBodySize))
+	if err == errUnknownResource {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.fillHeader(f)
+	if spot, ok := f.(*DXSpotFrame); ok && spot.Mode == "" {
+		spot.Mode = string(inferMode(spot.Frequency, spot.Comments))
+	}
+	if s.validation < ValidateRequired {
+		err = ValidateFrame(f, ValidateRequired)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if spot, ok := f.(*DXSpotFrame); ok {
+		err = s.sendDXSpot(spot)
+	} else {
+		err = s.send(f)
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(struct {
+		ID string `json:"ID"`
+	}{f.Header().ID})
+}
+
+func (s *Server) serveStoredFrames(w http.ResponseWriter, r *http.Request) {
+	query, err := parseFrameQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	frames, err := s.store.Query(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if frames == nil {
+		frames = []Frame{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(frames)
+}
+
+func parseFrameQuery(values url.Values) (FrameQuery, error) {
+	var result FrameQuery
+	var err error
+	result.Since, err = parseQueryTime(values.Get("since"))
+	if err != nil {
+		return FrameQuery{}, err
+	}
+	result.Until, err = parseQueryTime(values.Get("until"))
+	if err != nil {
+		return FrameQuery{}, err
+	}
+	if frameTypes := values.Get("type"); frameTypes != "" {
+		for _, frameType := range strings.Split(frameTypes, ",") {
+			result.FrameTypes = append(result.FrameTypes, strings.TrimSpace(frameType))
+		}
+	}
+	if limit := values.Get("limit"); limit != "" {
+		result.Limit, err = strconv.Atoi(limit)
+		if err != nil || result.Limit < 0 {
+			return FrameQuery{}, fmt.Errorf("invalid limit: %s", limit)
+		}
+	}
+	return result, nil
+}
+
+// parseQueryTime parses a time in RFC 3339 format or as Unix milliseconds.
+func parseQueryTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if millis, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMilli(millis), nil
+	}
+	result, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time: %s", s)
+	}
+	return result, nil
+}
+
+var errUnknownResource = errors.New("unknown resource")
+
+func decodeRESTFrame(resource string, body io.Reader) (Frame, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read body: %v", err)
+	}
+	if resource == "frames" {
+		return DecodeFrame(data)
+	}
+
+	frameType, ok := restEndpoints[resource]
+	if !ok {
+		return nil, errUnknownResource
+	}
+	result := frameFactories[frameType]()
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %s frame: %v", frameType, err)
+	}
+	if result.Header().Frame != "" && result.Header().Frame != frameType {
+		return nil, fmt.Errorf("cannot send %s frame as %s", result.Header().Frame, frameType)
+	}
+	return result, nil
+}
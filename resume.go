@@ -0,0 +1,92 @@
+package godxmap
+
+import (
+	"net/http"
+	"time"
+)
+
+// ResumeParameter is the name of the query parameter that a reconnecting client uses to resume its session.
+// The value is the ID of the last frame the client received. The server re-sends all retained frames that were
+// broadcast after this frame. If the frame is not retained anymore, the server re-sends all retained frames.
+const ResumeParameter = "after"
+
+// WithResume retains the broadcast frames of the given period, but at most the given number of frames,
+// to re-send them to reconnecting clients that present the [ResumeParameter] during the handshake.
+func WithResume(retention time.Duration, capacity int) Option {
+	return func(s *Server) {
+		s.resume = &resumeBuffer{
+			retention: retention,
+			capacity:  capacity,
+		}
+	}
+}
+
+func resumeAfter(r *http.Request) string {
+	if r == nil {
+		return ""
+	}
+	return r.URL.Query().Get(ResumeParameter)
+}
+
+// resumeBuffer is only used from within the run loop of the server, it does not need any synchronization.
+type resumeBuffer struct {
+	retention time.Duration
+	capacity  int
+	frames    []retainedFrame
+	pressure  MemoryPressure
+}
+
+// retainedFrame is retained by the time it was broadcast, not by its DateTime, which may be in the past or in the future.
+type retainedFrame struct {
+	frame Frame
+	added time.Time
+}
+
+func (b *resumeBuffer) Add(f Frame, now time.Time) {
+	if b.pressure == MemoryPressureCritical {
+		return
+	}
+	b.frames = append(b.frames, retainedFrame{frame: f, added: now})
+	capacity := b.capacity
+	if b.pressure == MemoryPressureHigh {
+		capacity = max(1, capacity/2)
+	}
+	if capacity > 0 && len(b.frames) > capacity {
+		b.frames = b.frames[len(b.frames)-capacity:]
+	}
+
+	oldest := now.Add(-b.retention)
+	expired := 0
+	for expired < len(b.frames) && b.frames[expired].added.Before(oldest) {
+		expired++
+	}
+	b.frames = b.frames[expired:]
+}
+
+func (b *resumeBuffer) Shed(pressure MemoryPressure) {
+	b.pressure = pressure
+	switch pressure {
+	case MemoryPressureHigh:
+		b.frames = append([]retainedFrame(nil), b.frames[len(b.frames)/2:]...)
+	case MemoryPressureCritical:
+		b.frames = nil
+	}
+}
+
+// After returns the retained frames that were broadcast after the frame with the given ID. The aggregation of spots
+// re-sends frames with the same ID, the frames are returned from the first occurrence on, so no update is missed.
+// If the ID is not retained, After returns all retained frames.
+func (b *resumeBuffer) After(id string) []Frame {
+	start := 0
+	for i, retained := range b.frames {
+		if retained.frame.Header().ID == id {
+			start = i + 1
+			break
+		}
+	}
+	result := make([]Frame, 0, len(b.frames)-start)
+	for _, retained := range b.frames[start:] {
+		result = append(result, retained.frame)
+	}
+	return result
+}
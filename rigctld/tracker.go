@@ -0,0 +1,159 @@
+// The package rigctld tracks the frequency and mode of a radio through a Hamlib rigctld instance
+// and shows them as station status on the map of a [godxmap.Server].
+package rigctld
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+// DefaultAddr is the default address of rigctld.
+const DefaultAddr = "localhost:4532"
+
+const (
+	defaultInterval = time.Second
+	ioTimeout       = 2 * time.Second
+)
+
+// Tracker polls rigctld for the current frequency and mode and shows every change as station status on the map.
+type Tracker struct {
+	addr     string
+	station  string
+	operator string
+	server   *godxmap.Server
+	interval time.Duration
+}
+
+// Option configures a [Tracker] instance.
+type Option func(*Tracker)
+
+// WithInterval sets the polling interval. The default is one second.
+func WithInterval(interval time.Duration) Option {
+	return func(t *Tracker) {
+		t.interval = interval
+	}
+}
+
+// WithOperator sets the operator callsign that is shown in the station status.
+func WithOperator(operator string) Option {
+	return func(t *Tracker) {
+		t.operator = operator
+	}
+}
+
+// NewTracker creates a new tracker for the rigctld instance at the given address. The status is shown for the given station name.
+// To actually start tracking, use the Run method.
+func NewTracker(addr string, station string, server *godxmap.Server, options ...Option) *Tracker {
+	result := &Tracker{
+		addr:     addr,
+		station:  station,
+		server:   server,
+		interval: defaultInterval,
+	}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+// Run polls rigctld until the given context is done or the connection fails.
+func (t *Tracker) Run(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: ioTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", t.addr)
+	if err != nil {
+		return fmt.Errorf("cannot connect to rigctld at %s: %v", t.addr, err)
+	}
+	defer conn.Close()
+	responses := bufio.NewReader(conn)
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	var lastFrequency float64
+	var lastMode string
+	for {
+		frequencyKHz, mode, err := t.poll(conn, responses)
+		if err != nil {
+			return err
+		}
+		if frequencyKHz != lastFrequency || mode != lastMode {
+			lastFrequency, lastMode = frequencyKHz, mode
+			err = t.server.ShowStatus(t.station, t.operator, frequencyKHz, mode)
+			if err != nil {
+				t.server.Logger().Warn("cannot show the rig status", "station", t.station, "error", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *Tracker) poll(conn net.Conn, responses *bufio.Reader) (float64, string, error) {
+	frequency, err := t.request(conn, responses, "f", 1)
+	if err != nil {
+		return 0, "", err
+	}
+	frequencyHz, err := strconv.ParseFloat(frequency[0], 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid frequency from rigctld: %q", frequency[0])
+	}
+
+	// the mode response consists of the mode and the passband
+	mode, err := t.request(conn, responses, "m", 2)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return frequencyHz / 1000, normalizeMode(mode[0]), nil
+}
+
+func (t *Tracker) request(conn net.Conn, responses *bufio.Reader, command string, lines int) ([]string, error) {
+	conn.SetDeadline(time.Now().Add(ioTimeout))
+	_, err := fmt.Fprintf(conn, "%s\n", command)
+	if err != nil {
+		return nil, fmt.Errorf("cannot send %q to rigctld: %v", command, err)
+	}
+	result := make([]string, lines)
+	for i := range result {
+		line, err := responses.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("cannot read response to %q from rigctld: %v", command, err)
+		}
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "RPRT ") {
+			return nil, fmt.Errorf("rigctld reported an error for %q: %s", command, line)
+		}
+		result[i] = line
+	}
+	return result, nil
+}
+
+// normalizeMode maps the Hamlib mode names to the modes known to godxmap.
+func normalizeMode(mode string) string {
+	switch mode {
+	case "USB", "LSB":
+		return string(godxmap.ModeSSB)
+	case "PKTUSB", "PKTLSB":
+		// some digital mode, we cannot tell which one
+		return string(godxmap.NoMode)
+	case "CW", "CWR":
+		return string(godxmap.ModeCW)
+	case "RTTY", "RTTYR":
+		return string(godxmap.ModeRTTY)
+	case "FM", "WFM", "PKTFM":
+		return string(godxmap.ModeFM)
+	default:
+		return mode
+	}
+}
@@ -0,0 +1,51 @@
+package godxmap
+
+import (
+	"math"
+	"time"
+)
+
+// earthRadiusKm is the mean radius of the earth.
+const earthRadiusKm = 6371.0
+
+// FootprintRadius returns the radius in km of the area on the ground from where a satellite at the given altitude in km
+// is above the horizon.
+func FootprintRadius(altitudeKm float64) float64 {
+	if altitudeKm <= 0 {
+		return 0
+	}
+	return earthRadiusKm * math.Acos(earthRadiusKm/(earthRadiusKm+altitudeKm))
+}
+
+// ShowSatellite shows the given satellite at its current position with its footprint on the map. The ground track
+// is optional, it may contain past and upcoming points, e.g. computed by the satellite package from a TLE or
+// precomputed by a tracking program.
+func (s *Server) ShowSatellite(name string, position SatellitePoint, track []SatellitePoint) error {
+	return s.send(s.satelliteFrame(name, position, track))
+}
+
+func (s *Server) satelliteFrame(name string, position SatellitePoint, track []SatellitePoint) *SatelliteFrame {
+	result := &SatelliteFrame{
+		FrameHeader:     s.newHeader(SatelliteFrameType),
+		Name:            name,
+		Latitude:        position.Latitude,
+		Longitude:       position.Longitude,
+		Altitude:        position.Altitude,
+		FootprintRadius: FootprintRadius(position.Altitude),
+		Track:           track,
+	}
+	if position.Time != 0 {
+		result.DateTime = position.Time
+	}
+	return result
+}
+
+// SatellitePointAt returns a point of a ground track at the given time.
+func SatellitePointAt(t time.Time, latitude float64, longitude float64, altitudeKm float64) SatellitePoint {
+	return SatellitePoint{
+		Time:      t.UnixMilli(),
+		Latitude:  latitude,
+		Longitude: longitude,
+		Altitude:  altitudeKm,
+	}
+}
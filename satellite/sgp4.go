@@ -0,0 +1,296 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// The constants of the SGP4 model, using the WGS72 earth model.
+const (
+	earthRadiusKm = 6378.135
+	xke           = 0.0743669161
+	ck2           = 5.413080e-4
+	ck4           = 0.62098875e-6
+	j3            = -0.253881e-5
+	qoms2t        = 1.880279e-09
+	s0            = 1.012229
+	minutesPerDay = 1440.0
+	flattening    = 1 / 298.26
+)
+
+// ErrDeepSpace is returned for satellites with an orbital period of 225 minutes or more, which need the SDP4 model.
+var ErrDeepSpace = errors.New("deep space orbits are not supported")
+
+// ErrDecayed is returned when the orbit of the satellite has decayed at the requested time.
+var ErrDecayed = errors.New("the orbit has decayed")
+
+// Satellite propagates the orbit of a near earth satellite with the SGP4 model.
+type Satellite struct {
+	tle TLE
+
+	// mean elements in radians and radians per minute
+	n0, e0, i0, omega0, node0, m0 float64
+
+	simple                                   bool
+	aodp, xnodp, eta, c1, c4, c5, d2, d3, d4 float64
+	cosio, sinio, x3thm1, x1mth2, x7thm1     float64
+	xmdot, omgdot, xnodot, omgcof, xmcof     float64
+	xnodcf, t2cof, t3cof, t4cof, t5cof       float64
+	xlcof, aycof, delmo, sinmo               float64
+}
+
+// New initializes the SGP4 model for the orbit that is described by the given TLE.
+func New(tle TLE) (*Satellite, error) {
+	deg := math.Pi / 180
+	s := &Satellite{
+		tle:    tle,
+		n0:     tle.MeanMotion * 2 * math.Pi / minutesPerDay,
+		e0:     tle.Eccentricity,
+		i0:     tle.Inclination * deg,
+		omega0: tle.ArgumentOfPerigee * deg,
+		node0:  tle.RightAscension * deg,
+		m0:     tle.MeanAnomaly * deg,
+	}
+	if s.n0 <= 0 {
+		return nil, errors.New("invalid mean motion")
+	}
+	if 2*math.Pi/s.n0 >= 225 {
+		return nil, ErrDeepSpace
+	}
+
+	// recover the original mean motion and semi major axis from the input elements
+	a1 := math.Pow(xke/s.n0, 2.0/3.0)
+	s.cosio = math.Cos(s.i0)
+	theta2 := s.cosio * s.cosio
+	s.x3thm1 = 3*theta2 - 1
+	eosq := s.e0 * s.e0
+	betao2 := 1 - eosq
+	betao := math.Sqrt(betao2)
+	del1 := 1.5 * ck2 * s.x3thm1 / (a1 * a1 * betao * betao2)
+	ao := a1 * (1 - del1*(1.0/3.0+del1*(1+134.0/81.0*del1)))
+	delo := 1.5 * ck2 * s.x3thm1 / (ao * ao * betao * betao2)
+	s.xnodp = s.n0 / (1 + delo)
+	s.aodp = ao / (1 - delo)
+
+	// for a perigee below 220 km, the equations are truncated
+	s.simple = s.aodp*(1-s.e0) < 220/earthRadiusKm+1
+
+	// for a perigee below 156 km, the values of s and qoms2t are altered
+	s4 := s0
+	qoms24 := qoms2t
+	perigee := (s.aodp*(1-s.e0) - 1) * earthRadiusKm
+	if perigee < 156 {
+		s4 = perigee - 78
+		if perigee <= 98 {
+			s4 = 20
+		}
+		qoms24 = math.Pow((120-s4)/earthRadiusKm, 4)
+		s4 = s4/earthRadiusKm + 1
+	}
+	pinvsq := 1 / (s.aodp * s.aodp * betao2 * betao2)
+	tsi := 1 / (s.aodp - s4)
+	s.eta = s.aodp * s.e0 * tsi
+	etasq := s.eta * s.eta
+	eeta := s.e0 * s.eta
+	psisq := math.Abs(1 - etasq)
+	coef := qoms24 * math.Pow(tsi, 4)
+	coef1 := coef / math.Pow(psisq, 3.5)
+	c2 := coef1 * s.xnodp * (s.aodp*(1+1.5*etasq+eeta*(4+etasq)) + 0.75*ck2*tsi/psisq*s.x3thm1*(8+3*etasq*(8+etasq)))
+	s.c1 = tle.BStar * c2
+	s.sinio = math.Sin(s.i0)
+	a3ovk2 := -j3 / ck2
+	c3 := 0.0
+	if s.e0 > 1e-4 {
+		c3 = coef * tsi * a3ovk2 * s.xnodp * s.sinio / s.e0
+	}
+	s.x1mth2 = 1 - theta2
+	s.c4 = 2 * s.xnodp * coef1 * s.aodp * betao2 * (s.eta*(2+0.5*etasq) + s.e0*(0.5+2*etasq) -
+		2*ck2*tsi/(s.aodp*psisq)*(-3*s.x3thm1*(1-2*eeta+etasq*(1.5-0.5*eeta))+0.75*s.x1mth2*(2*etasq-eeta*(1+etasq))*math.Cos(2*s.omega0)))
+	s.c5 = 2 * coef1 * s.aodp * betao2 * (1 + 2.75*(etasq+eeta) + eeta*etasq)
+	theta4 := theta2 * theta2
+	temp1 := 3 * ck2 * pinvsq * s.xnodp
+	temp2 := temp1 * ck2 * pinvsq
+	temp3 := 1.25 * ck4 * pinvsq * pinvsq * s.xnodp
+	s.xmdot = s.xnodp + 0.5*temp1*betao*s.x3thm1 + 0.0625*temp2*betao*(13-78*theta2+137*theta4)
+	x1m5th := 1 - 5*theta2
+	s.omgdot = -0.5*temp1*x1m5th + 0.0625*temp2*(7-114*theta2+395*theta4) + temp3*(3-36*theta2+49*theta4)
+	xhdot1 := -temp1 * s.cosio
+	s.xnodot = xhdot1 + (0.5*temp2*(4-19*theta2)+2*temp3*(3-7*theta2))*s.cosio
+	s.omgcof = tle.BStar * c3 * math.Cos(s.omega0)
+	if s.e0 > 1e-4 {
+		s.xmcof = -2.0 / 3.0 * coef * tle.BStar / eeta
+	}
+	s.xnodcf = 3.5 * betao2 * xhdot1 * s.c1
+	s.t2cof = 1.5 * s.c1
+	s.xlcof = 0.125 * a3ovk2 * s.sinio * (3 + 5*s.cosio) / (1 + s.cosio)
+	s.aycof = 0.25 * a3ovk2 * s.sinio
+	s.delmo = math.Pow(1+s.eta*math.Cos(s.m0), 3)
+	s.sinmo = math.Sin(s.m0)
+	s.x7thm1 = 7*theta2 - 1
+	if !s.simple {
+		c1sq := s.c1 * s.c1
+		s.d2 = 4 * s.aodp * tsi * c1sq
+		temp := s.d2 * tsi * s.c1 / 3
+		s.d3 = (17*s.aodp + s4) * temp
+		s.d4 = 0.5 * temp * s.aodp * tsi * (221*s.aodp + 31*s4) * s.c1
+		s.t3cof = s.d2 + 2*c1sq
+		s.t4cof = 0.25 * (3*s.d3 + s.c1*(12*s.d2+10*c1sq))
+		s.t5cof = 0.2 * (3*s.d4 + 12*s.c1*s.d3 + 6*s.d2*s.d2 + 15*c1sq*(2*s.d2+c1sq))
+	}
+	return s, nil
+}
+
+// Name returns the name of the satellite as given in the TLE.
+func (s *Satellite) Name() string {
+	return s.tle.Name
+}
+
+// eci returns the position of the satellite in the true equator, mean equinox inertial frame in km
+// at the given minutes since the epoch of the TLE.
+func (s *Satellite) eci(tsince float64) ([3]float64, error) {
+	// secular effects of the atmospheric drag and the gravitation
+	xmdf := s.m0 + s.xmdot*tsince
+	omgadf := s.omega0 + s.omgdot*tsince
+	xnoddf := s.node0 + s.xnodot*tsince
+	omega := omgadf
+	xmp := xmdf
+	tsq := tsince * tsince
+	xnode := xnoddf + s.xnodcf*tsq
+	tempa := 1 - s.c1*tsince
+	tempe := s.tle.BStar * s.c4 * tsince
+	templ := s.t2cof * tsq
+	if !s.simple {
+		delomg := s.omgcof * tsince
+		delm := s.xmcof * (math.Pow(1+s.eta*math.Cos(xmdf), 3) - s.delmo)
+		temp := delomg + delm
+		xmp = xmdf + temp
+		omega = omgadf - temp
+		tcube := tsq * tsince
+		tfour := tsince * tcube
+		tempa = tempa - s.d2*tsq - s.d3*tcube - s.d4*tfour
+		tempe = tempe + s.tle.BStar*s.c5*(math.Sin(xmp)-s.sinmo)
+		templ = templ + s.t3cof*tcube + tfour*(s.t4cof+tsince*s.t5cof)
+	}
+	a := s.aodp * tempa * tempa
+	e := s.e0 - tempe
+	if a < 1 || e >= 1 || e < -0.001 {
+		return [3]float64{}, ErrDecayed
+	}
+	e = max(e, 1e-6)
+	xl := xmp + omega + xnode + s.xnodp*templ
+	beta := math.Sqrt(1 - e*e)
+
+	// long period periodics
+	axn := e * math.Cos(omega)
+	temp := 1 / (a * beta * beta)
+	xll := temp * s.xlcof * axn
+	aynl := temp * s.aycof
+	xlt := xl + xll
+	ayn := e*math.Sin(omega) + aynl
+
+	// solve Kepler's equation
+	capu := math.Mod(xlt-xnode, 2*math.Pi)
+	epw := capu
+	var sinepw, cosepw, temp3, temp4, temp5, temp6 float64
+	for i := 0; i < 10; i++ {
+		sinepw, cosepw = math.Sin(epw), math.Cos(epw)
+		temp3, temp4 = axn*sinepw, ayn*cosepw
+		temp5, temp6 = axn*cosepw, ayn*sinepw
+		next := (capu-temp4+temp3-epw)/(1-temp5-temp6) + epw
+		if math.Abs(next-epw) <= 1e-6 {
+			epw = next
+			break
+		}
+		epw = next
+	}
+	sinepw, cosepw = math.Sin(epw), math.Cos(epw)
+	temp3, temp4 = axn*sinepw, ayn*cosepw
+	temp5, temp6 = axn*cosepw, ayn*sinepw
+
+	// short period preliminary quantities
+	ecose := temp5 + temp6
+	esine := temp3 - temp4
+	elsq := axn*axn + ayn*ayn
+	temp = 1 - elsq
+	pl := a * temp
+	if pl <= 0 {
+		return [3]float64{}, ErrDecayed
+	}
+	r := a * (1 - ecose)
+	temp1 := 1 / r
+	temp2 := a * temp1
+	betal := math.Sqrt(temp)
+	temp3 = 1 / (1 + betal)
+	cosu := temp2 * (cosepw - axn + ayn*esine*temp3)
+	sinu := temp2 * (sinepw - ayn - axn*esine*temp3)
+	u := math.Atan2(sinu, cosu)
+	sin2u := 2 * sinu * cosu
+	cos2u := 2*cosu*cosu - 1
+	temp = 1 / pl
+	temp1 = ck2 * temp
+	temp2 = temp1 * temp
+
+	// short periodics
+	rk := r*(1-1.5*temp2*betal*s.x3thm1) + 0.5*temp1*s.x1mth2*cos2u
+	uk := u - 0.25*temp2*s.x7thm1*sin2u
+	xnodek := xnode + 1.5*temp2*s.cosio*sin2u
+	xinck := s.i0 + 1.5*temp2*s.cosio*s.sinio*cos2u
+	if rk < 1 {
+		return [3]float64{}, ErrDecayed
+	}
+
+	// orientation vectors
+	sinuk, cosuk := math.Sin(uk), math.Cos(uk)
+	sinik, cosik := math.Sin(xinck), math.Cos(xinck)
+	sinnok, cosnok := math.Sin(xnodek), math.Cos(xnodek)
+	xmx := -sinnok * cosik
+	xmy := cosnok * cosik
+	ux := xmx*sinuk + cosnok*cosuk
+	uy := xmy*sinuk + sinnok*cosuk
+	uz := sinik * sinuk
+	return [3]float64{rk * ux * earthRadiusKm, rk * uy * earthRadiusKm, rk * uz * earthRadiusKm}, nil
+}
+
+// Position returns the sub-satellite point in degrees and the altitude in km at the given time.
+func (s *Satellite) Position(t time.Time) (latitude float64, longitude float64, altitudeKm float64, err error) {
+	position, err := s.eci(t.Sub(s.tle.Epoch).Minutes())
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	x, y, z := position[0], position[1], position[2]
+
+	longitude = math.Atan2(y, x) - gmst(t)
+	longitude = math.Mod(longitude+3*math.Pi, 2*math.Pi) - math.Pi
+
+	// geodetic latitude on the WGS72 ellipsoid
+	r := math.Hypot(x, y)
+	e2 := flattening * (2 - flattening)
+	latitude = math.Atan2(z, r)
+	var c float64
+	for i := 0; i < 10; i++ {
+		sinLat := math.Sin(latitude)
+		c = 1 / math.Sqrt(1-e2*sinLat*sinLat)
+		next := math.Atan2(z+earthRadiusKm*c*e2*sinLat, r)
+		if math.Abs(next-latitude) < 1e-10 {
+			latitude = next
+			break
+		}
+		latitude = next
+	}
+	altitudeKm = r/math.Cos(latitude) - earthRadiusKm*c
+
+	return latitude * 180 / math.Pi, longitude * 180 / math.Pi, altitudeKm, nil
+}
+
+// gmst returns the Greenwich mean sidereal time in radians at the given time.
+func gmst(t time.Time) float64 {
+	julianDate := float64(t.UnixNano())/float64(24*time.Hour) + 2440587.5
+	centuries := (julianDate - 2451545.0) / 36525
+	seconds := 67310.54841 + (876600*3600+8640184.812866)*centuries + 0.093104*centuries*centuries - 6.2e-6*centuries*centuries*centuries
+	result := math.Mod(seconds*2*math.Pi/86400, 2*math.Pi)
+	if result < 0 {
+		result += 2 * math.Pi
+	}
+	return result
+}
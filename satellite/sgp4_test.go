@@ -0,0 +1,193 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// the test case of the Spacetrack Report #3
+const (
+	testLine1 = "1 88888U          80275.98708465  .00073094  13844-3  66816-4 0    8"
+	testLine2 = "2 88888  72.8435 115.9689 0086731  52.6988 110.5714 16.05824518  105"
+)
+
+func TestParseTLE(t *testing.T) {
+	tle, err := ParseTLE("0 TEST SAT", testLine1, testLine2+"\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := TLE{
+		Name:              "TEST SAT",
+		Epoch:             time.Date(1980, time.October, 1, 23, 41, 24, 113760000, time.UTC),
+		BStar:             0.66816e-4,
+		Inclination:       72.8435,
+		RightAscension:    115.9689,
+		Eccentricity:      0.0086731,
+		ArgumentOfPerigee: 52.6988,
+		MeanAnomaly:       110.5714,
+		MeanMotion:        16.05824518,
+	}
+	if tle.Epoch.Sub(expected.Epoch).Abs() > time.Millisecond {
+		t.Errorf("expected the epoch %v, got %v", expected.Epoch, tle.Epoch)
+	}
+	tle.Epoch = expected.Epoch
+	if tle != expected {
+		t.Errorf("expected\n%+v\ngot\n%+v", expected, tle)
+	}
+}
+
+func TestParseInvalidTLE(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		line1 string
+		line2 string
+	}{
+		{"empty", "", ""},
+		{"swapped lines", testLine2, testLine1},
+		{"truncated line 1", testLine1[:60], testLine2},
+		{"truncated line 2", testLine1, testLine2[:62]},
+		{"invalid epoch", strings.Replace(testLine1, "80275.98708465", "80275.9870846x", 1), testLine2},
+		{"invalid drag term", strings.Replace(testLine1, " 66816-4", " 66816x4", 1), testLine2},
+		{"drag term without exponent", strings.Replace(testLine1, " 66816-4", "  668164", 1), testLine2},
+		{"invalid inclination", testLine1, strings.Replace(testLine2, "72.8435", "72.84x5", 1)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tle, err := ParseTLE("", tc.line1, tc.line2)
+			if err == nil {
+				t.Errorf("expected an error, got %+v", tle)
+			}
+		})
+	}
+}
+
+func TestParseExponent(t *testing.T) {
+	for _, tc := range []struct {
+		value    string
+		expected float64
+	}{
+		{" 66816-4", 0.66816e-4},
+		{"-11606-4", -0.11606e-4},
+		{"+12345+1", 1.2345},
+		{" 00000-0", 0},
+		{"        ", 0},
+	} {
+		actual, err := parseExponent(tc.value)
+		if err != nil {
+			t.Errorf("%q: %v", tc.value, err)
+			continue
+		}
+		if math.Abs(actual-tc.expected) > 1e-12 {
+			t.Errorf("%q: expected %g, got %g", tc.value, tc.expected, actual)
+		}
+	}
+}
+
+func TestReadTLEs(t *testing.T) {
+	data := "ISS (ZARYA)\r\n" +
+		"1 25544U 98067A   25298.50000000  .00016717  00000-0  30375-3 0  9991\r\n" +
+		"2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.50377579 12345\r\n" +
+		"\n" +
+		testLine1 + "\n" +
+		testLine2 + "\n" +
+		"a name without TLE\n"
+	tles, err := ReadTLEs(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tles) != 2 || tles[0].Name != "ISS (ZARYA)" || tles[1].Name != "" {
+		t.Errorf("unexpected TLEs: %+v", tles)
+	}
+
+	_, err = ReadTLEs(strings.NewReader("NAME\n" + strings.Repeat("1", 100000) + "\n"))
+	if err == nil {
+		t.Error("expected an error for an oversized line")
+	}
+}
+
+func TestSGP4(t *testing.T) {
+	tle, err := ParseTLE("", testLine1, testLine2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	satellite, err := New(tle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the positions of the Spacetrack Report #3 in km
+	for _, tc := range []struct {
+		tsince   float64
+		expected [3]float64
+	}{
+		{0, [3]float64{2328.97048951, -5995.22076416, 1719.97067261}},
+		{360, [3]float64{2456.10705566, -6071.93853760, 1222.89727783}},
+		{720, [3]float64{2567.56195068, -6112.50384522, 713.96397400}},
+		{1080, [3]float64{2663.09078980, -6115.48229980, 196.39640427}},
+		{1440, [3]float64{2742.55133057, -6079.67144775, -326.38095856}},
+	} {
+		actual, err := satellite.eci(tc.tsince)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range actual {
+			if math.Abs(actual[i]-tc.expected[i]) > 1 {
+				t.Errorf("%.0f minutes: expected %v, got %v", tc.tsince, tc.expected, actual)
+				break
+			}
+		}
+	}
+}
+
+func TestSGP4Errors(t *testing.T) {
+	tle, err := ParseTLE("", testLine1, testLine2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deepSpace := tle
+	deepSpace.MeanMotion = 2
+	_, err = New(deepSpace)
+	if !errors.Is(err, ErrDeepSpace) {
+		t.Errorf("expected ErrDeepSpace, got %v", err)
+	}
+
+	invalid := tle
+	invalid.MeanMotion = 0
+	_, err = New(invalid)
+	if err == nil {
+		t.Error("expected an error for an invalid mean motion")
+	}
+
+	satellite, err := New(tle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, _, err = satellite.Position(tle.Epoch.Add(365 * 24 * time.Hour))
+	if !errors.Is(err, ErrDecayed) {
+		t.Errorf("expected ErrDecayed a year after the epoch, got %v", err)
+	}
+}
+
+func TestPosition(t *testing.T) {
+	tle, err := ParseTLE("", testLine1, testLine2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	satellite, err := New(tle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for minutes := 0; minutes <= 1440; minutes += 10 {
+		latitude, longitude, altitude, err := satellite.Position(tle.Epoch.Add(time.Duration(minutes) * time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the inclination limits the latitude, the eccentric orbit stays in the low earth orbit
+		if math.Abs(latitude) > 73 || math.Abs(longitude) > 180 || altitude < 100 || altitude > 400 {
+			t.Errorf("%d minutes: unexpected position %f, %f, %fkm", minutes, latitude, longitude, altitude)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// The package satellite computes the ground tracks of satellites from their two-line element sets (TLE)
+// and shows them on the map of a [godxmap.Server], so satellite operators can see upcoming passes alongside terrestrial spots.
+package satellite
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TLE is a two-line element set that describes the orbit of a satellite.
+type TLE struct {
+	Name  string
+	Epoch time.Time
+	// BStar is the drag term in 1/earth radii.
+	BStar float64
+	// Inclination, RightAscension, ArgumentOfPerigee and MeanAnomaly in degrees.
+	Inclination       float64
+	RightAscension    float64
+	Eccentricity      float64
+	ArgumentOfPerigee float64
+	MeanAnomaly       float64
+	// MeanMotion in revolutions per day.
+	MeanMotion float64
+}
+
+// ParseTLE parses a two-line element set. The name may be empty.
+func ParseTLE(name string, line1 string, line2 string) (TLE, error) {
+	line1, line2 = strings.TrimRight(line1, "\r\n "), strings.TrimRight(line2, "\r\n ")
+	if len(line1) < 61 || !strings.HasPrefix(line1, "1 ") {
+		return TLE{}, fmt.Errorf("invalid TLE line 1: %q", line1)
+	}
+	if len(line2) < 63 || !strings.HasPrefix(line2, "2 ") {
+		return TLE{}, fmt.Errorf("invalid TLE line 2: %q", line2)
+	}
+
+	result := TLE{Name: strings.TrimSpace(strings.TrimPrefix(name, "0 "))}
+	var err error
+	field := func(line string, from int, to int) float64 {
+		if err != nil {
+			return 0
+		}
+		var value float64
+		value, err = strconv.ParseFloat(strings.TrimSpace(line[from:min(to, len(line))]), 64)
+		if err != nil {
+			err = fmt.Errorf("invalid TLE field %q: %v", line[from:min(to, len(line))], err)
+		}
+		return value
+	}
+
+	epoch := field(line1, 18, 32)
+	if err == nil {
+		result.BStar, err = parseExponent(line1[53:61])
+	}
+	result.Inclination = field(line2, 8, 16)
+	result.RightAscension = field(line2, 17, 25)
+	result.Eccentricity = field(line2, 26, 33) / 1e7
+	result.ArgumentOfPerigee = field(line2, 34, 42)
+	result.MeanAnomaly = field(line2, 43, 51)
+	result.MeanMotion = field(line2, 52, 63)
+	if err != nil {
+		return TLE{}, err
+	}
+
+	year := int(epoch / 1000)
+	if year < 57 {
+		year += 2000
+	} else {
+		year += 1900
+	}
+	days := math.Mod(epoch, 1000)
+	result.Epoch = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration((days - 1) * float64(24*time.Hour)))
+	return result, nil
+}
+
+// parseExponent parses a number in the exponential notation of TLEs with an implied decimal point, e.g. " 12345-3" for 0.12345e-3.
+func parseExponent(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	sign := ""
+	if s[0] == '-' || s[0] == '+' {
+		sign, s = s[:1], s[1:]
+	}
+	split := strings.LastIndexAny(s, "+-")
+	if split <= 0 {
+		return 0, fmt.Errorf("invalid TLE exponent %q", s)
+	}
+	result, err := strconv.ParseFloat(sign+"0."+s[:split]+"e"+s[split:], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid TLE exponent %q: %v", s, err)
+	}
+	return result, nil
+}
+
+// ReadTLEs reads a list of two-line element sets, either with or without a name line before each set,
+// e.g. as provided by CelesTrak or AMSAT.
+func ReadTLEs(r io.Reader) ([]TLE, error) {
+	var result []TLE
+	var name, line1 string
+	lines := bufio.NewScanner(r)
+	for lines.Scan() {
+		line := strings.TrimRight(lines.Text(), "\r ")
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "1 ") && len(line) >= 61:
+			line1 = line
+		case strings.HasPrefix(line, "2 ") && len(line) >= 63 && line1 != "":
+			tle, err := ParseTLE(name, line1, line)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, tle)
+			name, line1 = "", ""
+		default:
+			name = line
+		}
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read TLEs: %v", err)
+	}
+	return result, nil
+}
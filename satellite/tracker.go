@@ -0,0 +1,110 @@
+package satellite
+
+import (
+	"context"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+const (
+	defaultInterval    = 30 * time.Second
+	defaultTrackBefore = 10 * time.Minute
+	defaultTrackAfter  = 90 * time.Minute
+	defaultTrackStep   = time.Minute
+)
+
+// Track returns the ground track of the satellite from the given time for the given duration, with one point per step.
+// Points where the orbit cannot be propagated are left out.
+func (s *Satellite) Track(from time.Time, duration time.Duration, step time.Duration) []godxmap.SatellitePoint {
+	if step <= 0 {
+		return nil
+	}
+	result := make([]godxmap.SatellitePoint, 0, int(duration/step)+1)
+	for t := from; !t.After(from.Add(duration)); t = t.Add(step) {
+		latitude, longitude, altitude, err := s.Position(t)
+		if err != nil {
+			continue
+		}
+		result = append(result, godxmap.SatellitePointAt(t, latitude, longitude, altitude))
+	}
+	return result
+}
+
+// Tracker periodically shows the current position, the footprint and the ground track of satellites on the map of a server.
+type Tracker struct {
+	satellites  []*Satellite
+	server      *godxmap.Server
+	interval    time.Duration
+	trackBefore time.Duration
+	trackAfter  time.Duration
+	trackStep   time.Duration
+}
+
+// Option configures a [Tracker] instance.
+type Option func(*Tracker)
+
+// WithInterval sets the interval in which the positions are updated. The default is 30 seconds.
+func WithInterval(interval time.Duration) Option {
+	return func(t *Tracker) {
+		t.interval = interval
+	}
+}
+
+// WithTrack sets the part of the ground track that is shown: the given time before and after the current position,
+// with one point per step. The default is 10 minutes before and 90 minutes after, i.e. about one orbit of a LEO satellite,
+// with one point per minute.
+func WithTrack(before time.Duration, after time.Duration, step time.Duration) Option {
+	return func(t *Tracker) {
+		t.trackBefore = before
+		t.trackAfter = after
+		t.trackStep = step
+	}
+}
+
+// NewTracker creates a new tracker for the given satellites. To actually show the satellites, use the Run method.
+func NewTracker(satellites []*Satellite, server *godxmap.Server, options ...Option) *Tracker {
+	result := &Tracker{
+		satellites:  satellites,
+		server:      server,
+		interval:    defaultInterval,
+		trackBefore: defaultTrackBefore,
+		trackAfter:  defaultTrackAfter,
+		trackStep:   defaultTrackStep,
+	}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+// Run shows the satellites until the given context is done.
+func (t *Tracker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		now := time.Now()
+		for _, satellite := range t.satellites {
+			t.show(satellite, now)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *Tracker) show(satellite *Satellite, now time.Time) {
+	latitude, longitude, altitude, err := satellite.Position(now)
+	if err != nil {
+		t.server.Logger().Warn("cannot compute the position of satellite", "satellite", satellite.Name(), "error", err)
+		return
+	}
+	track := satellite.Track(now.Add(-t.trackBefore), t.trackBefore+t.trackAfter, t.trackStep)
+	err = t.server.ShowSatellite(satellite.Name(), godxmap.SatellitePointAt(now, latitude, longitude, altitude), track)
+	if err != nil {
+		t.server.Logger().Warn("cannot show satellite", "satellite", satellite.Name(), "error", err)
+	}
+}
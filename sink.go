@@ -0,0 +1,69 @@
+package godxmap
+
+import "sync"
+
+// Sink receives every frame that is broadcast to the websocket clients, e.g. to mirror the frames into other systems.
+// The sinks are called from the broadcasting goroutine, so they must not block.
+type Sink interface {
+	Publish(f Frame)
+}
+
+// SinkFunc adapts a function to the [Sink] interface.
+type SinkFunc func(f Frame)
+
+// Publish implements [Sink].
+func (fn SinkFunc) Publish(f Frame) {
+	fn(f)
+}
+
+// WithSink adds the given sink to the server.
+func WithSink(sink Sink) Option {
+	return func(s *Server) {
+		s.sinks.add(sink)
+	}
+}
+
+// Subscribe adds the given sink to the running server. The sink receives all frames that are broadcast
+// until the returned function is called.
+func (s *Server) Subscribe(sink Sink) (unsubscribe func()) {
+	id := s.sinks.add(sink)
+	return func() {
+		s.sinks.remove(id)
+	}
+}
+
+type sinkRegistry struct {
+	lock   sync.RWMutex
+	nextID int
+	sinks  map[int]Sink
+}
+
+func (r *sinkRegistry) add(sink Sink) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.sinks == nil {
+		r.sinks = make(map[int]Sink)
+	}
+	r.nextID++
+	r.sinks[r.nextID] = sink
+	return r.nextID
+}
+
+func (r *sinkRegistry) remove(id int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.sinks, id)
+}
+
+func (s *Server) publish(m message) {
+	s.sinks.lock.RLock()
+	defer s.sinks.lock.RUnlock()
+
+	for _, sink := range s.sinks.sinks {
+		for _, f := range m.frames {
+			sink.Publish(f)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// The package skimmer provides a client for the telnet feed of a local CW Skimmer or Skimmer Server
+// that shows the decoded stations as DX spots on the map of a [godxmap.Server].
+package skimmer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ftl/godxmap"
+	"github.com/ftl/godxmap/cluster"
+	"github.com/ftl/godxmap/rbn"
+)
+
+// DefaultAddr is the default address of the telnet server of CW Skimmer and Skimmer Server.
+const DefaultAddr = "localhost:7300"
+
+// Client connects to the telnet feed of a skimmer and translates the decodes into DX spots.
+// The skimmer spots have the same format as the spots of the Reverse Beacon Network, e.g. "CW 24 dB 28 WPM CQ",
+// so they are filtered with an [rbn.Filter].
+type Client struct {
+	call           string
+	server         *godxmap.Server
+	filter         rbn.Filter
+	showOwnCall    bool
+	clusterOptions []cluster.Option
+	client         *cluster.Client
+	source         godxmap.SourceRunner
+}
+
+// Option configures a [Client] instance.
+type Option func(*Client)
+
+// WithFilter only shows the decodes that pass the given filter.
+func WithFilter(filter rbn.Filter) Option {
+	return func(c *Client) {
+		c.filter = filter
+	}
+}
+
+// WithOwnCall also shows the decodes of the own callsign. By default, the own signal, which the skimmer
+// usually decodes while transmitting, is not shown.
+func WithOwnCall() Option {
+	return func(c *Client) {
+		c.showOwnCall = true
+	}
+}
+
+// WithClusterOptions passes the given options to the underlying telnet client.
+func WithClusterOptions(options ...cluster.Option) Option {
+	return func(c *Client) {
+		c.clusterOptions = append(c.clusterOptions, options...)
+	}
+}
+
+// NewClient creates a new client for the skimmer at the given address that logs in with the given own callsign.
+// To actually connect to the skimmer, use the Run method.
+func NewClient(addr string, call string, server *godxmap.Server, options ...Option) *Client {
+	result := &Client{
+		call:   call,
+		server: server,
+	}
+	for _, option := range options {
+		option(result)
+	}
+	result.client = cluster.NewClient(addr, call, result.handle, result.clusterOptions...)
+	return result
+}
+
+// Run connects to the skimmer and processes the decodes until the connection is closed or the given context is done.
+func (c *Client) Run(ctx context.Context) error {
+	return c.client.Run(ctx)
+}
+
+// Start runs the client in the background. The decodes are emitted on the given channel instead of
+// being shown directly. Start, Stop and Status implement [godxmap.SpotSource] and [godxmap.SourceStatus].
+func (c *Client) Start(spots chan<- godxmap.Spot) error {
+	return c.source.Start(spots, c.Run)
+}
+
+// Stop stops the client that was started with Start.
+func (c *Client) Stop() {
+	c.source.Stop()
+}
+
+// Status reports if the client that was started with Start is still running.
+func (c *Client) Status() (bool, error) {
+	return c.source.Status()
+}
+
+func (c *Client) handle(clusterSpot cluster.Spot) {
+	spot := rbn.FromClusterSpot(clusterSpot)
+	if !c.showOwnCall && isOwnCall(spot.DX, c.call) {
+		return
+	}
+	if !c.filter.Matches(spot) {
+		return
+	}
+	if c.source.Emit(spot.DXSpot()) {
+		return
+	}
+	err := c.server.ShowSpot(spot.DXSpot())
+	if err != nil {
+		c.server.Logger().Warn("cannot show skimmer spot", "call", spot.DX, "error", err)
+	}
+}
+
+// isOwnCall reports if the given callsign is the own callsign, also with prefix or suffix, e.g. DL1ABC/P.
+func isOwnCall(call string, ownCall string) bool {
+	call, ownCall = strings.ToUpper(call), strings.ToUpper(ownCall)
+	if call == ownCall {
+		return true
+	}
+	for _, part := range strings.Split(call, "/") {
+		if part == ownCall {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,217 @@
+// The package sota polls the spots of Summits on the Air (SOTA) and shows them at the position of the summit
+// on the map of a [godxmap.Server].
+package sota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+// DefaultURL is the address of the SOTA API.
+const DefaultURL = "https://api2.sota.org.uk/api"
+
+const (
+	defaultInterval = time.Minute
+	requestTimeout  = 10 * time.Second
+	spotsPerPoll    = 50
+)
+
+// Spot is a spot as it is provided by the SOTA API.
+type Spot struct {
+	ID                int64  `json:"id"`
+	TimeStamp         string `json:"timeStamp"`
+	Comments          string `json:"comments"`
+	Spotter           string `json:"callsign"`
+	AssociationCode   string `json:"associationCode"`
+	SummitCode        string `json:"summitCode"`
+	ActivatorCallsign string `json:"activatorCallsign"`
+	Frequency         string `json:"frequency"`
+	Mode              string `json:"mode"`
+	SummitDetails     string `json:"summitDetails"`
+}
+
+// Reference returns the full summit reference, e.g. "W7W/LC-001".
+func (s Spot) Reference() string {
+	return s.AssociationCode + "/" + s.SummitCode
+}
+
+// Summit describes the position of a summit.
+type Summit struct {
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Locator   string  `json:"locator"`
+}
+
+// Poller polls the SOTA API and shows every new spot as DX spot at the position of the summit.
+// The positions of the summits are looked up once and cached.
+type Poller struct {
+	url      string
+	server   *godxmap.Server
+	interval time.Duration
+	client   *http.Client
+	source   godxmap.SourceRunner
+
+	mutex   sync.Mutex
+	summits map[string]*Summit
+}
+
+// Option configures a [Poller] instance.
+type Option func(*Poller)
+
+// WithInterval sets the polling interval. The default is one minute.
+func WithInterval(interval time.Duration) Option {
+	return func(p *Poller) {
+		p.interval = interval
+	}
+}
+
+// WithURL uses the SOTA API at the given URL instead of [DefaultURL].
+func WithURL(url string) Option {
+	return func(p *Poller) {
+		p.url = url
+	}
+}
+
+// NewPoller creates a new poller that feeds the given server. To actually start polling, use the Run method.
+func NewPoller(server *godxmap.Server, options ...Option) *Poller {
+	result := &Poller{
+		url:      DefaultURL,
+		server:   server,
+		interval: defaultInterval,
+		client:   &http.Client{Timeout: requestTimeout},
+		summits:  make(map[string]*Summit),
+	}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+// Run polls the SOTA API until the given context is done. Failed requests are logged and retried with the next poll.
+func (p *Poller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	// the API returns the latest spots with every request, only the new ones are shown
+	var lastID int64
+	for {
+		var spots []Spot
+		err := p.get(ctx, fmt.Sprintf("%s/spots/%d/all", p.url, spotsPerPoll), &spots)
+		if err != nil && ctx.Err() == nil {
+			p.server.Logger().Warn("cannot poll SOTA spots", "error", err)
+		}
+		maxID := lastID
+		for _, spot := range spots {
+			if spot.ID > lastID {
+				p.show(ctx, spot)
+			}
+			maxID = max(maxID, spot.ID)
+		}
+		lastID = maxID
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Start runs the poller in the background. The spots are emitted on the given channel instead of
+// being shown directly. Start, Stop and Status implement [godxmap.SpotSource] and [godxmap.SourceStatus].
+func (p *Poller) Start(spots chan<- godxmap.Spot) error {
+	return p.source.Start(spots, p.Run)
+}
+
+// Stop stops the poller that was started with Start.
+func (p *Poller) Stop() {
+	p.source.Stop()
+}
+
+// Status reports if the poller that was started with Start is still running.
+func (p *Poller) Status() (bool, error) {
+	return p.source.Status()
+}
+
+func (p *Poller) showSpot(spot godxmap.Spot) error {
+	if p.source.Emit(spot) {
+		return nil
+	}
+	return p.server.ShowSpot(spot)
+}
+
+func (p *Poller) get(ctx context.Context, url string, v any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	response, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s", response.Status)
+	}
+	err = json.NewDecoder(response.Body).Decode(v)
+	if err != nil {
+		return fmt.Errorf("invalid response: %v", err)
+	}
+	return nil
+}
+
+// summit returns the position of the given summit, or nil if it is unknown.
+func (p *Poller) summit(ctx context.Context, spot Spot) *Summit {
+	reference := spot.Reference()
+	p.mutex.Lock()
+	summit, ok := p.summits[reference]
+	p.mutex.Unlock()
+	if ok {
+		return summit
+	}
+
+	summit = new(Summit)
+	err := p.get(ctx, fmt.Sprintf("%s/summits/%s/%s", p.url, spot.AssociationCode, spot.SummitCode), summit)
+	if err != nil {
+		p.server.Logger().Warn("cannot get the position of SOTA summit", "summit", reference, "error", err)
+		// try again with the next spot of this summit
+		return nil
+	}
+	p.mutex.Lock()
+	p.summits[reference] = summit
+	p.mutex.Unlock()
+	return summit
+}
+
+func (p *Poller) show(ctx context.Context, spot Spot) {
+	frequencyMHz, err := strconv.ParseFloat(strings.TrimSpace(spot.Frequency), 64)
+	if err != nil {
+		p.server.Logger().Warn("invalid frequency of SOTA spot", "id", spot.ID, "frequency", spot.Frequency)
+		return
+	}
+	frequencyKHz := frequencyMHz * 1000
+
+	dxSpot := godxmap.Spot{
+		DX:           spot.ActivatorCallsign,
+		Spotter:      spot.Spotter,
+		FrequencyKHz: frequencyKHz,
+		Comments:     strings.TrimSpace(fmt.Sprintf("%s SOTA %s %s", strings.ToUpper(spot.Mode), spot.Reference(), spot.Comments)),
+	}
+	summit := p.summit(ctx, spot)
+	if summit != nil && (summit.Latitude != 0 || summit.Longitude != 0) {
+		dxSpot.Latitude, dxSpot.Longitude = &summit.Latitude, &summit.Longitude
+	}
+	err = p.showSpot(dxSpot)
+	if err != nil {
+		p.server.Logger().Warn("cannot show SOTA spot", "call", spot.ActivatorCallsign, "error", err)
+	}
+}
@@ -0,0 +1,294 @@
+package godxmap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Spot is a DX spot as it is emitted by a [SpotSource].
+type Spot struct {
+	// Time is the time of the spot. If it is zero, the time when the spot is shown is used.
+	Time         time.Time
+	DX           string
+	Spotter      string
+	FrequencyKHz float64
+	Comments     string
+	// Mode is the mode of the spot. If it is empty, it is inferred like in [Server.ShowDXSpot].
+	Mode Mode
+	// Locator is the Maidenhead locator of the DX station, if known.
+	Locator string
+	// Latitude and Longitude are the position of the DX station in degrees, if known. They take precedence over the locator.
+	Latitude  *float64
+	Longitude *float64
+}
+
+// ShowSpot adds the given DX spot to the map.
+func (s *Server) ShowSpot(spot Spot) error {
+	mode := spot.Mode
+	if mode == "" {
+		mode = inferMode(spot.FrequencyKHz, spot.Comments)
+	}
+	f := s.dxSpotFrame(spot.DX, spot.Spotter, spot.FrequencyKHz, spot.Comments, mode)
+	if !spot.Time.IsZero() {
+		f.DateTime = spot.Time.UnixMilli()
+	}
+	switch {
+	case spot.Latitude != nil && spot.Longitude != nil:
+		f.Latitude, f.Longitude = spot.Latitude, spot.Longitude
+		f.Locator = spot.Locator
+	case spot.Locator != "":
+		var err error
+		f.Locator, f.Latitude, f.Longitude, err = located(spot.Locator)
+		if err != nil {
+			return fmt.Errorf("cannot show %s: %v", spot.DX, err)
+		}
+	}
+	return s.sendDXSpot(f)
+}
+
+// SpotSource provides DX spots, e.g. a DX cluster, the RBN or WSJT-X.
+// A source can be attached to a server with [Server.AttachSource].
+type SpotSource interface {
+	// Start starts the source in the background. The source emits its spots on the given channel until Stop is called.
+	Start(spots chan<- Spot) error
+	// Stop stops the source and waits until it has terminated.
+	Stop()
+}
+
+// SourceStatus is optionally implemented by a [SpotSource] to report if it is still running,
+// and the error that made it terminate.
+type SourceStatus interface {
+	Status() (running bool, err error)
+}
+
+// SourceRunner implements the Start, Stop and Status methods of a [SpotSource] for sources that are
+// driven by a blocking Run function.
+type SourceRunner struct {
+	lock   sync.Mutex
+	spots  chan<- Spot
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+// Start calls the given run function in the background until Stop is called. The spots passed to Emit are sent to the given channel.
+func (r *SourceRunner) Start(spots chan<- Spot, run func(context.Context) error) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.cancel != nil {
+		return fmt.Errorf("source already started")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	r.spots, r.ctx, r.cancel, r.done, r.err = spots, ctx, cancel, done, nil
+	go func() {
+		defer close(done)
+		err := run(ctx)
+		r.lock.Lock()
+		r.err = err
+		r.lock.Unlock()
+	}()
+	return nil
+}
+
+// Stop stops the run function and waits until it has returned.
+func (r *SourceRunner) Stop() {
+	r.lock.Lock()
+	cancel, done := r.cancel, r.done
+	r.lock.Unlock()
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+
+	r.lock.Lock()
+	r.spots, r.cancel = nil, nil
+	r.lock.Unlock()
+}
+
+// Status reports if the run function is still running and the error it returned.
+func (r *SourceRunner) Status() (bool, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.done == nil {
+		return false, nil
+	}
+	select {
+	case <-r.done:
+		return false, r.err
+	default:
+		return true, nil
+	}
+}
+
+// Emit sends the given spot to the channel passed to Start. It reports false if the source was not started.
+func (r *SourceRunner) Emit(spot Spot) bool {
+	r.lock.Lock()
+	spots, ctx := r.spots, r.ctx
+	r.lock.Unlock()
+	if spots == nil {
+		return false
+	}
+	if ctx.Err() != nil {
+		return true
+	}
+
+	select {
+	case spots <- spot:
+	case <-ctx.Done():
+	}
+	return true
+}
+
+// SourceHealth describes the state of an attached [SpotSource].
+type SourceHealth struct {
+	Name    string
+	Running bool
+	// Err is the error that made the source terminate.
+	Err      error
+	Started  time.Time
+	Spots    int
+	Rejected int
+	LastSpot time.Time
+}
+
+const sourceBufferSize = 100
+
+type attachedSource struct {
+	source SpotSource
+	// spots is never closed, because the source may still emit spots while it stops
+	spots    chan Spot
+	stopping chan struct{}
+	done     chan struct{}
+
+	lock   sync.Mutex
+	health SourceHealth
+}
+
+type sourceRegistry struct {
+	lock    sync.Mutex
+	sources map[string]*attachedSource
+}
+
+// AttachSource starts the given source and shows all its spots on the map. Multiple sources can be attached
+// concurrently, each with a unique name. The health of the sources is reported by [Server.SourceHealth].
+// All sources are stopped when the server is closed. After [Server.Close], AttachSource returns [http.ErrServerClosed].
+func (s *Server) AttachSource(name string, source SpotSource) error {
+	s.sources.lock.Lock()
+	defer s.sources.lock.Unlock()
+	s.lifecycle.Lock()
+	closed := s.closeCalled
+	s.lifecycle.Unlock()
+	if closed {
+		return http.ErrServerClosed
+	}
+	if _, ok := s.sources.sources[name]; ok {
+		return fmt.Errorf("source %s already attached", name)
+	}
+
+	attached := &attachedSource{
+		source:   source,
+		spots:    make(chan Spot, sourceBufferSize),
+		stopping: make(chan struct{}),
+		done:     make(chan struct{}),
+		health:   SourceHealth{Name: name, Started: s.now()},
+	}
+	err := source.Start(attached.spots)
+	if err != nil {
+		return fmt.Errorf("cannot start source %s: %v", name, err)
+	}
+	go s.showSpots(attached)
+
+	if s.sources.sources == nil {
+		s.sources.sources = make(map[string]*attachedSource)
+	}
+	s.sources.sources[name] = attached
+	return nil
+}
+
+func (s *Server) showSpots(source *attachedSource) {
+	defer close(source.done)
+	for {
+		select {
+		case spot := <-source.spots:
+			s.showSourceSpot(source, spot)
+		case <-source.stopping:
+			// show the spots that were emitted before the source stopped
+			for range len(source.spots) {
+				s.showSourceSpot(source, <-source.spots)
+			}
+			return
+		}
+	}
+}
+
+func (s *Server) showSourceSpot(source *attachedSource, spot Spot) {
+	err := s.ShowSpot(spot)
+	source.lock.Lock()
+	source.health.Spots++
+	source.health.LastSpot = s.now()
+	if err != nil {
+		source.health.Rejected++
+	}
+	source.lock.Unlock()
+	if err != nil {
+		s.logger.Warn("cannot show spot", "source", source.health.Name, "call", spot.DX, "error", err)
+	}
+}
+
+// DetachSource stops the source with the given name and removes it from this server.
+func (s *Server) DetachSource(name string) {
+	s.sources.lock.Lock()
+	attached, ok := s.sources.sources[name]
+	delete(s.sources.sources, name)
+	s.sources.lock.Unlock()
+	if ok {
+		attached.stop()
+	}
+}
+
+func (s *Server) detachAllSources() {
+	s.sources.lock.Lock()
+	sources := s.sources.sources
+	s.sources.sources = nil
+	s.sources.lock.Unlock()
+	for _, attached := range sources {
+		attached.stop()
+	}
+}
+
+func (a *attachedSource) stop() {
+	a.source.Stop()
+	close(a.stopping)
+	<-a.done
+}
+
+// SourceHealth reports the health of all attached sources, ordered by name.
+func (s *Server) SourceHealth() []SourceHealth {
+	s.sources.lock.Lock()
+	defer s.sources.lock.Unlock()
+
+	result := make([]SourceHealth, 0, len(s.sources.sources))
+	for _, attached := range s.sources.sources {
+		attached.lock.Lock()
+		health := attached.health
+		attached.lock.Unlock()
+		health.Running = true
+		if status, ok := attached.source.(SourceStatus); ok {
+			health.Running, health.Err = status.Status()
+		}
+		result = append(result, health)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
@@ -0,0 +1,157 @@
+package godxmap
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithMapState keeps track of the markers that are currently shown on the map, i.e. the logged calls, partial calls
+// and DX spots that were neither cleared nor expired, and of the own station's location and antenna heading.
+// The state is available with [Server.Snapshot] and is sent to every newly connected client, so a map that is opened
+// in the middle of a contest immediately shows the current picture.
+//
+// Clients that resume their session with the [ResumeParameter] get the frames of the resume buffer instead,
+// see [WithResume]. With map state, the history of [WithHistory] is not replayed.
+func WithMapState() Option {
+	return func(s *Server) {
+		s.state = newMapState(s.now)
+	}
+}
+
+// Snapshot returns the frames of the markers that are currently shown on the map, ordered by their DateTime.
+// Without [WithMapState], the snapshot is empty.
+func (s *Server) Snapshot() []Frame {
+	if s.state == nil {
+		return nil
+	}
+	return s.state.Snapshot(s.now())
+}
+
+// markerKey identifies a marker on the map.
+type markerKey struct {
+	frameType string
+	call      string
+	frequency int64
+}
+
+// frequencyKey rounds the frequency to 100 Hz, so small differences of the reported frequencies do not produce separate markers.
+func frequencyKey(frequencyKHz float64) int64 {
+	return int64(math.Round(frequencyKHz * 10))
+}
+
+type mapState struct {
+	lock       sync.Mutex
+	markers    map[markerKey]Frame
+	stationQTH Frame
+	heading    Frame
+	pressure   MemoryPressure
+	now        Clock
+}
+
+func newMapState(now Clock) *mapState {
+	return &mapState{
+		markers: make(map[markerKey]Frame),
+		now:     now,
+	}
+}
+
+// Update applies the given frame to the state.
+func (m *mapState) Update(f Frame) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	switch f := f.(type) {
+	case *LoggedCallFrame:
+		m.addMarker(markerKey{LoggedCallFrameType, strings.ToUpper(f.Call), frequencyKey(f.Frequency)}, f)
+	case *PartialCallFrame:
+		m.addMarker(markerKey{PartialCallFrameType, strings.ToUpper(f.Call), 0}, f)
+	case *DXSpotFrame:
+		m.addMarker(markerKey{DXSpotFrameType, strings.ToUpper(f.Spot), frequencyKey(f.Frequency)}, f)
+	case *ClearCallFrame:
+		m.removeMarkers(f.Call, f.Frequency, f.Marker)
+	case *DeletedCallFrame:
+		m.removeMarkers(f.Call, f.Frequency, LoggedCallFrameType)
+	case *StationQTHFrame:
+		m.stationQTH = f
+	case *HeadingFrame:
+		m.heading = f
+	}
+}
+
+func (m *mapState) addMarker(key markerKey, f Frame) {
+	if m.pressure == MemoryPressureCritical {
+		return
+	}
+	m.markers[key] = f
+}
+
+func (m *mapState) removeMarkers(call string, frequencyKHz float64, frameType string) {
+	call = strings.ToUpper(call)
+	frequency := frequencyKey(frequencyKHz)
+	for key := range m.markers {
+		if key.call != call {
+			continue
+		}
+		if frameType != "" && key.frameType != frameType {
+			continue
+		}
+		if frequencyKHz != 0 && key.frequency != 0 && key.frequency != frequency {
+			continue
+		}
+		delete(m.markers, key)
+	}
+}
+
+func expired(f Frame, now time.Time) bool {
+	expiring, ok := f.(ExpiringFrame)
+	if !ok || expiring.TimeToLive() <= 0 {
+		return false
+	}
+	return now.After(time.UnixMilli(f.Header().DateTime).Add(expiring.TimeToLive()))
+}
+
+// Snapshot removes the expired markers and returns the remaining frames.
+func (m *mapState) Snapshot(now time.Time) []Frame {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	result := make([]Frame, 0, len(m.markers)+2)
+	for _, f := range []Frame{m.stationQTH, m.heading} {
+		if f != nil {
+			result = append(result, f)
+		}
+	}
+	for key, f := range m.markers {
+		if expired(f, now) {
+			delete(m.markers, key)
+			continue
+		}
+		result = append(result, f)
+	}
+	slices.SortStableFunc(result, func(a, b Frame) int {
+		return cmp.Compare(a.Header().DateTime, b.Header().DateTime)
+	})
+	return result
+}
+
+func (m *mapState) Shed(pressure MemoryPressure) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.pressure = pressure
+	switch pressure {
+	case MemoryPressureHigh:
+		now := m.now()
+		for key, f := range m.markers {
+			if expired(f, now) {
+				delete(m.markers, key)
+			}
+		}
+	case MemoryPressureCritical:
+		clear(m.markers)
+	}
+}
@@ -0,0 +1,94 @@
+package godxmap
+
+import (
+	"maps"
+	"sync"
+	"time"
+)
+
+// Stats is a snapshot of the statistics of a server, e.g. to show a health panel in the host application.
+type Stats struct {
+	Started time.Time
+	Uptime  time.Duration
+	// Clients is the number of currently connected clients.
+	Clients int
+	// FramesSent counts the frames that were broadcast to the clients, by frame type.
+	FramesSent map[string]int
+	// SendErrors counts the failed attempts to send a message to a client.
+	SendErrors int
+	// Dropped counts the frames that were not broadcast, because they were dropped by the filters, the middleware,
+	// the deduplication or the rate limiter.
+	Dropped int
+}
+
+// TotalFramesSent returns the number of broadcast frames of all frame types.
+func (s Stats) TotalFramesSent() int {
+	result := 0
+	for _, count := range s.FramesSent {
+		result += count
+	}
+	return result
+}
+
+type serverStats struct {
+	lock       sync.Mutex
+	started    time.Time
+	clients    int
+	framesSent map[string]int
+	sendErrors int
+	dropped    int
+}
+
+func newServerStats(started time.Time) *serverStats {
+	return &serverStats{
+		started:    started,
+		framesSent: make(map[string]int),
+	}
+}
+
+func (s *serverStats) clientConnected() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.clients++
+}
+
+func (s *serverStats) clientDisconnected() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.clients--
+}
+
+func (s *serverStats) sent(m message) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, f := range m.frames {
+		s.framesSent[f.FrameType()]++
+	}
+}
+
+func (s *serverStats) sendFailed() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.sendErrors++
+}
+
+func (s *serverStats) drop(count int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.dropped += count
+}
+
+// Stats returns a snapshot of the statistics of this server.
+func (s *Server) Stats() Stats {
+	s.stats.lock.Lock()
+	defer s.stats.lock.Unlock()
+
+	return Stats{
+		Started:    s.stats.started,
+		Uptime:     s.now().Sub(s.stats.started),
+		Clients:    s.stats.clients,
+		FramesSent: maps.Clone(s.stats.framesSent),
+		SendErrors: s.stats.sendErrors,
+		Dropped:    s.stats.dropped,
+	}
+}
@@ -0,0 +1,186 @@
+package godxmap
+
+import (
+	"log/slog"
+	"time"
+)
+
+const storeQueueSize = 1024
+
+// FrameQuery selects frames from a [FrameStore].
+type FrameQuery struct {
+	// Since and Until limit the DateTime of the frames. A zero value means no limit.
+	Since time.Time
+	Until time.Time
+	// FrameTypes limits the result to the given frame types. Without frame types, all frames are selected.
+	FrameTypes []string
+	// Limit limits the result to the most recent frames. Zero means no limit.
+	Limit int
+}
+
+// Matches reports if the given frame matches the frame types and the time range of the query.
+func (q FrameQuery) Matches(f Frame) bool {
+	t := f.Header().DateTime
+	if !q.Since.IsZero() && t < q.Since.UnixMilli() {
+		return false
+	}
+	if !q.Until.IsZero() && t > q.Until.UnixMilli() {
+		return false
+	}
+	if len(q.FrameTypes) == 0 {
+		return true
+	}
+	for _, frameType := range q.FrameTypes {
+		if f.FrameType() == frameType {
+			return true
+		}
+	}
+	return false
+}
+
+// FrameStore persists the broadcast frames, e.g. to keep a record of what was spotted when during a contest.
+// The package store provides an implementation based on bbolt.
+type FrameStore interface {
+	// Add persists the given frames.
+	Add(frames []Frame) error
+	// Query returns the persisted frames that match the given query, ordered by their DateTime.
+	Query(query FrameQuery) ([]Frame, error)
+	// Prune removes the oldest frames until only the given fraction of the frames is left,
+	// e.g. 0.5 removes the older half of the frames.
+	Prune(fraction float64) error
+}
+
+// WithFrameStore persists all broadcast frames in the given store. The frames are written in the background,
+// the broadcast is not delayed by a slow store. The store is not closed when the server is closed.
+//
+// With [WithHistory], the history is filled from the store when the server is created, so the clients that connect
+// after a restart still get the current picture. With [WithRESTAPI], the stored frames can be queried, e.g.:
+//
+//	curl 'http://localhost:8080/api/frames?since=2025-10-25T00:00:00Z&type=DXSpot'
+func WithFrameStore(store FrameStore) Option {
+	return func(s *Server) {
+		s.store = store
+	}
+}
+
+// QueryFrames returns the frames from the store that match the given query. Without a store, the result is empty.
+func (s *Server) QueryFrames(query FrameQuery) ([]Frame, error) {
+	if s.store == nil {
+		return nil, nil
+	}
+	return s.store.Query(query)
+}
+
+// storeWriter writes the broadcast frames to the store in the background.
+type storeWriter struct {
+	store  FrameStore
+	logger *slog.Logger
+	queue  chan []Frame
+	prune  chan float64
+	done   chan struct{}
+}
+
+func newStoreWriter(store FrameStore, logger *slog.Logger) *storeWriter {
+	result := &storeWriter{
+		store:  store,
+		logger: logger,
+		queue:  make(chan []Frame, storeQueueSize),
+		prune:  make(chan float64, 1),
+		done:   make(chan struct{}),
+	}
+	go result.run()
+	return result
+}
+
+func (w *storeWriter) run() {
+	defer close(w.done)
+	for {
+		select {
+		case frames, ok := <-w.queue:
+			if !ok {
+				return
+			}
+			err := w.store.Add(frames)
+			if err != nil {
+				w.logger.Warn("cannot store frames", "frames", len(frames), "error", err)
+			}
+		case fraction := <-w.prune:
+			err := w.store.Prune(fraction)
+			if err != nil {
+				w.logger.Warn("cannot prune the store", "error", err)
+			}
+		}
+	}
+}
+
+// Add queues the given frames. If the queue is full, the frames are dropped.
+func (w *storeWriter) Add(frames []Frame) {
+	select {
+	case w.queue <- frames:
+	default:
+		w.logger.Warn("cannot store frames, queue is full", "frames", len(frames))
+	}
+}
+
+// Shed removes the oldest frames from the store: the older half with high memory pressure,
+// all but the most recent quarter with critical pressure. The incoming frames are still stored.
+func (w *storeWriter) Shed(pressure MemoryPressure) {
+	var fraction float64
+	switch pressure {
+	case MemoryPressureHigh:
+		fraction = 0.5
+	case MemoryPressureCritical:
+		fraction = 0.25
+	default:
+		return
+	}
+	select {
+	case w.prune <- fraction:
+	default:
+		// a prune is already pending
+	}
+}
+
+// Close waits until all queued frames are written to the store.
+func (w *storeWriter) Close() {
+	close(w.queue)
+	<-w.done
+}
+
+// startStore fills the history from the store and starts the background writer. It is called before the run loop is started.
+func (s *Server) startStore() {
+	if s.store == nil {
+		return
+	}
+	if s.history != nil {
+		query := FrameQuery{Limit: len(s.history.frames)}
+		if s.history.maxAge > 0 {
+			query.Since = s.now().Add(-s.history.maxAge)
+		}
+		frames, err := s.store.Query(query)
+		if err != nil {
+			s.logger.Warn("cannot load history from store", "error", err)
+		}
+		for _, f := range frames {
+			s.history.Add(f)
+		}
+	}
+
+	s.storeWriter = newStoreWriter(s.store, s.logger)
+}
+
+// persist queues the frames of the given message for the store. It is called from within the run loop.
+func (s *Server) persist(m message) {
+	if s.storeWriter == nil {
+		return
+	}
+	s.storeWriter.Add(m.frames)
+}
+
+// stopStore waits until all queued frames are written to the store. It is called when the run loop terminates.
+func (s *Server) stopStore() {
+	if s.storeWriter == nil {
+		return
+	}
+	s.storeWriter.Close()
+}
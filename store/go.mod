@@ -0,0 +1,13 @@
+module github.com/ftl/godxmap/store
+
+go 1.22.3
+
+require (
+	github.com/ftl/godxmap v0.1.0
+	go.etcd.io/bbolt v1.3.11
+)
+
+require (
+	golang.org/x/net v0.33.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+)
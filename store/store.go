@@ -0,0 +1,196 @@
+// The package store provides a persistent [godxmap.FrameStore] based on bbolt, an embedded key/value database.
+// The broadcast frames survive restarts and can be queried after the fact, e.g. to find out what was spotted when during a contest.
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/ftl/godxmap"
+)
+
+const (
+	openTimeout   = time.Second
+	pruneInterval = time.Minute
+)
+
+var framesBucket = []byte("frames")
+
+// Store persists frames in a bbolt database file. The frames are ordered by their DateTime.
+type Store struct {
+	db        *bolt.DB
+	retention time.Duration
+
+	pruneLock  sync.Mutex
+	lastPruned time.Time
+}
+
+// Option configures a [Store] instance.
+type Option func(*Store)
+
+// WithRetention removes the frames that are older than the given period. By default, the frames are kept forever.
+func WithRetention(retention time.Duration) Option {
+	return func(s *Store) {
+		s.retention = retention
+	}
+}
+
+// Open opens the database in the given file, or creates it if it does not exist yet.
+// The file is locked as long as the store is open.
+func Open(filename string, options ...Option) (*Store, error) {
+	db, err := bolt.Open(filename, 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("cannot open store %s: %v", filename, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(framesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("cannot initialize store %s: %v", filename, err)
+	}
+
+	result := &Store{db: db}
+	for _, option := range options {
+		option(result)
+	}
+	return result, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// frameKey orders the frames by their DateTime. The ID makes the key unique.
+func frameKey(dateTime int64, id string) []byte {
+	result := make([]byte, 8, 8+len(id))
+	binary.BigEndian.PutUint64(result, uint64(max(0, dateTime)))
+	return append(result, id...)
+}
+
+// Add implements [godxmap.FrameStore].
+func (s *Store) Add(frames []godxmap.Frame) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(framesBucket)
+		for _, f := range frames {
+			data, err := godxmap.EncodeFrame(f)
+			if err != nil {
+				return err
+			}
+			header := f.Header()
+			err = bucket.Put(frameKey(header.DateTime, header.ID), data)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot store frames: %v", err)
+	}
+	return s.prune()
+}
+
+// prune removes the expired frames, at most once per minute.
+func (s *Store) prune() error {
+	if s.retention <= 0 {
+		return nil
+	}
+	s.pruneLock.Lock()
+	defer s.pruneLock.Unlock()
+	now := time.Now()
+	if now.Sub(s.lastPruned) < pruneInterval {
+		return nil
+	}
+	s.lastPruned = now
+
+	oldest := frameKey(now.Add(-s.retention).UnixMilli(), "")
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(framesBucket).Cursor()
+		// the cursor skips the key after a deleted one, so always start over at the oldest remaining frame
+		for key, _ := cursor.First(); key != nil && bytes.Compare(key, oldest) < 0; key, _ = cursor.First() {
+			err := cursor.Delete()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot remove expired frames: %v", err)
+	}
+	return nil
+}
+
+// Prune implements [godxmap.FrameStore].
+func (s *Store) Prune(fraction float64) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(framesBucket)
+		count := bucket.Stats().KeyN
+		remove := count - int(float64(count)*max(0, min(fraction, 1)))
+
+		cursor := bucket.Cursor()
+		for key, _ := cursor.First(); key != nil && remove > 0; key, _ = cursor.First() {
+			err := cursor.Delete()
+			if err != nil {
+				return err
+			}
+			remove--
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot prune frames: %v", err)
+	}
+	return nil
+}
+
+// Query implements [godxmap.FrameStore].
+func (s *Store) Query(query godxmap.FrameQuery) ([]godxmap.Frame, error) {
+	var result []godxmap.Frame
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(framesBucket).Cursor()
+
+		// walk backwards from the end of the time range, so the limit keeps the most recent frames
+		var key, value []byte
+		if query.Until.IsZero() {
+			key, value = cursor.Last()
+		} else if key, _ = cursor.Seek(frameKey(query.Until.UnixMilli()+1, "")); key == nil {
+			key, value = cursor.Last()
+		} else {
+			key, value = cursor.Prev()
+		}
+
+		var since []byte
+		if !query.Since.IsZero() {
+			since = frameKey(query.Since.UnixMilli(), "")
+		}
+		for ; key != nil && bytes.Compare(key, since) >= 0; key, value = cursor.Prev() {
+			f, err := godxmap.DecodeFrame(value)
+			if err != nil {
+				return err
+			}
+			if !query.Matches(f) {
+				continue
+			}
+			result = append(result, f)
+			if query.Limit > 0 && len(result) == query.Limit {
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot query frames: %v", err)
+	}
+	slices.Reverse(result)
+	return result, nil
+}
@@ -0,0 +1,102 @@
+package store
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+func openStore(t *testing.T, options ...Option) *Store {
+	t.Helper()
+	result, err := Open(filepath.Join(t.TempDir(), "frames.db"), options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { result.Close() })
+	return result
+}
+
+func spots(start time.Time, count int) []godxmap.Frame {
+	result := make([]godxmap.Frame, count)
+	for i := range result {
+		result[i] = &godxmap.GabFrame{
+			FrameHeader: godxmap.FrameHeader{
+				Frame:    godxmap.GabFrameType,
+				ID:       fmt.Sprintf("%d", i),
+				DateTime: start.Add(time.Duration(i) * time.Minute).UnixMilli(),
+			},
+			Message: fmt.Sprintf("gab %d", i),
+		}
+	}
+	return result
+}
+
+func TestPruneRemovesTheOldestFrames(t *testing.T) {
+	start := time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC)
+	tt := []struct {
+		fraction float64
+		expected int
+	}{
+		{1, 8},
+		{0.5, 4},
+		{0.25, 2},
+		{0, 0},
+		{-1, 0},
+		{2, 8},
+	}
+	for _, tc := range tt {
+		t.Run(fmt.Sprintf("%v", tc.fraction), func(t *testing.T) {
+			store := openStore(t)
+			err := store.Add(spots(start, 8))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = store.Prune(tc.fraction)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			frames, err := store.Query(godxmap.FrameQuery{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(frames) != tc.expected {
+				t.Fatalf("expected %d frames, got %d", tc.expected, len(frames))
+			}
+			if len(frames) > 0 && frames[len(frames)-1].Header().ID != "7" {
+				t.Errorf("the most recent frame was removed: %v", frames)
+			}
+		})
+	}
+}
+
+func TestAddRemovesExpiredFrames(t *testing.T) {
+	store := openStore(t, WithRetention(time.Hour))
+	now := time.Now()
+
+	err := store.Add(spots(now.Add(-2*time.Hour), 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Add(spots(now, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frames, err := store.Query(godxmap.FrameQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 2 {
+		t.Errorf("expected 2 frames, got %d", len(frames))
+	}
+	for _, f := range frames {
+		if time.UnixMilli(f.Header().DateTime).Before(now.Add(-time.Hour)) {
+			t.Errorf("expired frame was not removed: %v", f)
+		}
+	}
+}
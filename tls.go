@@ -0,0 +1,96 @@
+package godxmap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+)
+
+// WithTLS serves the websocket connections over TLS (wss://) using the given configuration.
+func WithTLS(config *tls.Config) Option {
+	return func(s *Server) {
+		s.tlsConfig = config
+	}
+}
+
+// WithClientCertificates requires every client to present a certificate that is signed by one of the given CAs.
+// This option only has an effect in combination with [WithTLS]. The GetConfigForClient and VerifyPeerCertificate
+// callbacks of the TLS configuration are still called, the client certificates are verified first.
+func WithClientCertificates(clientCAs *x509.CertPool) Option {
+	return func(s *Server) {
+		s.clientCAs = clientCAs
+	}
+}
+
+func (s *Server) serverTLSConfig() *tls.Config {
+	result := s.tlsConfig.Clone()
+	if s.clientCAs == nil {
+		return result
+	}
+
+	// the client certificates are verified manually to report the remote address of failed attempts
+	result.ClientCAs = s.clientCAs
+	result.ClientAuth = tls.RequireAnyClientCert
+	getConfigForClient := result.GetConfigForClient
+	result.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		config := result
+		if getConfigForClient != nil {
+			// the configuration of the application for this client still requires the client certificate
+			clientConfig, err := getConfigForClient(hello)
+			if err != nil {
+				return nil, err
+			}
+			if clientConfig != nil {
+				config = clientConfig
+			}
+		}
+		config = config.Clone()
+		config.GetConfigForClient = nil
+		config.ClientCAs = s.clientCAs
+		config.ClientAuth = tls.RequireAnyClientCert
+
+		remoteAddr := hello.Conn.RemoteAddr().String()
+		verifyPeerCertificate := config.VerifyPeerCertificate
+		config.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+			err := verifyClientCertificate(rawCerts, s.clientCAs)
+			if err == nil && verifyPeerCertificate != nil {
+				err = verifyPeerCertificate(rawCerts, verifiedChains)
+			}
+			if err != nil {
+				s.audit(AuditEvent{Type: AuditAuthFailure, RemoteAddr: remoteAddr, Message: err.Error()})
+			}
+			return err
+		}
+		return config, nil
+	}
+	return result
+}
+
+func verifyClientCertificate(rawCerts [][]byte, clientCAs *x509.CertPool) error {
+	if len(rawCerts) == 0 {
+		return errors.New("no client certificate")
+	}
+	certificates := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		certificate, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("cannot parse client certificate: %v", err)
+		}
+		certificates[i] = certificate
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, certificate := range certificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+	_, err := certificates[0].Verify(x509.VerifyOptions{
+		Roots:         clientCAs,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return fmt.Errorf("invalid client certificate %q: %v", certificates[0].Subject.CommonName, err)
+	}
+	return nil
+}
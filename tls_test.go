@@ -0,0 +1,46 @@
+package godxmap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"testing"
+)
+
+func TestClientCertificatesKeepGetConfigForClient(t *testing.T) {
+	var called bool
+	clientConfig := &tls.Config{MinVersion: tls.VersionTLS13}
+	server := &Server{
+		tlsConfig: &tls.Config{
+			GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+				called = true
+				return clientConfig, nil
+			},
+		},
+		clientCAs: x509.NewCertPool(),
+	}
+	conn, other := net.Pipe()
+	defer conn.Close()
+	defer other.Close()
+
+	config, err := server.serverTLSConfig().GetConfigForClient(&tls.ClientHelloInfo{Conn: conn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !called {
+		t.Error("the GetConfigForClient callback of the application is not called")
+	}
+	if config.MinVersion != tls.VersionTLS13 {
+		t.Error("the configuration of the application for the client is not used")
+	}
+	if config.ClientAuth != tls.RequireAnyClientCert || config.ClientCAs != server.clientCAs || config.VerifyPeerCertificate == nil {
+		t.Error("the client certificate is not required anymore")
+	}
+	if clientConfig.VerifyPeerCertificate != nil {
+		t.Error("the configuration of the application is modified")
+	}
+	err = config.VerifyPeerCertificate(nil, nil)
+	if err == nil {
+		t.Error("a client without certificate is accepted")
+	}
+}
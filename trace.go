@@ -0,0 +1,66 @@
+package godxmap
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// LevelTrace is the log level of the trace entries that follow every frame from its ingestion through enrichment,
+// middleware, filtering and rate limiting to the delivery to each client. The entries carry the ID of the frame as
+// "frame_id", so a single spot can be followed by its ID. To see them, use a logger with a handler that is enabled
+// for this level, see [WithLogger]:
+//
+//	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: godxmap.LevelTrace}))
+const LevelTrace = slog.LevelDebug - 4
+
+// The reasons why a frame was not sent, as reported in the "reason" field of the trace entries.
+const (
+	dropMiddleware   = "middleware"
+	dropFilter       = "filter"
+	dropInvalid      = "invalid"
+	dropAggregated   = "aggregated"
+	dropDuplicate    = "duplicate"
+	dropCoalesced    = "coalesced"
+	dropRateLimit    = "rate_limit"
+	dropClientFilter = "client_filter"
+)
+
+func tracing(logger *slog.Logger) bool {
+	return logger.Enabled(context.Background(), LevelTrace)
+}
+
+func traceFrame(logger *slog.Logger, f Frame, msg string, args ...any) {
+	if !tracing(logger) {
+		return
+	}
+	args = append([]any{"frame_id", f.Header().ID, "frame_type", f.FrameType()}, args...)
+	logger.Log(context.Background(), LevelTrace, msg, args...)
+}
+
+// traceDropped traces all frames of the original message that are missing in the remaining message.
+func traceDropped(logger *slog.Logger, original message, remaining message, reason string) {
+	if !tracing(logger) {
+		return
+	}
+	for _, f := range original.frames {
+		if !slices.Contains(remaining.frames, f) {
+			traceFrame(logger, f, "frame dropped", "reason", reason)
+		}
+	}
+}
+
+// traceDelivery traces the delivery of the frames of the given message to a client.
+func traceDelivery(logger *slog.Logger, original message, sent message, err error) {
+	if !tracing(logger) {
+		return
+	}
+	traceDropped(logger, original, sent, dropClientFilter)
+	for _, f := range sent.frames {
+		if err != nil {
+			traceFrame(logger, f, "frame not delivered", "error", err)
+		} else {
+			traceFrame(logger, f, "frame delivered")
+		}
+	}
+}
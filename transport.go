@@ -0,0 +1,40 @@
+package godxmap
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// MaxIncomingMessageSize limits the size of the messages that a [Transport] accepts from the clients.
+const MaxIncomingMessageSize = 64 * 1024
+
+// Transport abstracts the websocket implementation that is used to accept connections from map clients.
+// The default transport uses golang.org/x/net/websocket. The packages transport/gorilla and transport/nhooyr
+// provide transports based on github.com/gorilla/websocket and nhooyr.io/websocket in their own modules,
+// so their dependencies are only pulled in when they are used.
+type Transport interface {
+	// Handler returns an http.Handler that upgrades incoming requests to websocket connections
+	// and calls serve for each new connection with its handshake request. serve blocks until the connection is closed.
+	// Failed upgrades are logged to the given logger.
+	Handler(serve func(TransportConn, *http.Request), logger *slog.Logger) http.Handler
+}
+
+// TransportConn is a single websocket connection of a specific [Transport] implementation.
+type TransportConn interface {
+	// WriteMessage sends the given JSON encoded data as text message.
+	WriteMessage(data []byte, timeout time.Duration) error
+	// ReadMessage blocks until the next data message is received from the client. Control messages are handled internally.
+	ReadMessage() ([]byte, error)
+	Close() error
+	RemoteAddr() string
+}
+
+// WithTransport uses the given websocket implementation to handle websocket connections, e.g.:
+//
+//	server := godxmap.NewServer(":12345", godxmap.WithTransport(gorilla.NewTransport()))
+func WithTransport(transport Transport) Option {
+	return func(s *Server) {
+		s.transport = transport
+	}
+}
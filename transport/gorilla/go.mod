@@ -0,0 +1,10 @@
+module github.com/ftl/godxmap/transport/gorilla
+
+go 1.22.3
+
+require (
+	github.com/ftl/godxmap v0.1.0
+	github.com/gorilla/websocket v1.5.3
+)
+
+require golang.org/x/net v0.33.0 // indirect
@@ -0,0 +1,73 @@
+// The package gorilla provides a [godxmap.Transport] based on github.com/gorilla/websocket.
+// It is a separate module, so the dependency is only pulled in by the applications that use it:
+//
+//	server := godxmap.NewServer(":12345", godxmap.WithTransport(gorilla.NewTransport()))
+package gorilla
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ftl/godxmap"
+)
+
+// closeTimeout limits the time to send the close message when a connection is closed.
+const closeTimeout = 100 * time.Millisecond
+
+// Transport handles the websocket connections of a [godxmap.Server] with github.com/gorilla/websocket.
+type Transport struct{}
+
+// NewTransport creates a new transport based on github.com/gorilla/websocket.
+func NewTransport() *Transport {
+	return &Transport{}
+}
+
+// Handler implements [godxmap.Transport].
+func (*Transport) Handler(serve func(godxmap.TransportConn, *http.Request), logger *slog.Logger) http.Handler {
+	upgrader := websocket.Upgrader{
+		EnableCompression: true,
+		// HamDXMap is loaded from its own site, so we cannot restrict the origin
+		CheckOrigin: func(*http.Request) bool { return true },
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			logger.Warn("cannot upgrade websocket connection", "remote_addr", r.RemoteAddr, "error", err)
+			return
+		}
+		conn.SetReadLimit(godxmap.MaxIncomingMessageSize)
+		serve(connection{conn}, r)
+	})
+}
+
+type connection struct {
+	conn *websocket.Conn
+}
+
+// ReadMessage also processes control messages like ping and close while waiting for the next data message.
+func (c connection) ReadMessage() ([]byte, error) {
+	_, data, err := c.conn.ReadMessage()
+	return data, err
+}
+
+func (c connection) WriteMessage(data []byte, timeout time.Duration) error {
+	err := c.conn.SetWriteDeadline(time.Now().Add(timeout))
+	if err != nil {
+		return err
+	}
+	return c.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func (c connection) Close() error {
+	deadline := time.Now().Add(closeTimeout)
+	message := websocket.FormatCloseMessage(websocket.CloseGoingAway, "")
+	c.conn.WriteControl(websocket.CloseMessage, message, deadline)
+	return c.conn.Close()
+}
+
+func (c connection) RemoteAddr() string {
+	return c.conn.RemoteAddr().String()
+}
@@ -0,0 +1,10 @@
+module github.com/ftl/godxmap/transport/nhooyr
+
+go 1.22.3
+
+require (
+	github.com/ftl/godxmap v0.1.0
+	nhooyr.io/websocket v1.8.17
+)
+
+require golang.org/x/net v0.33.0 // indirect
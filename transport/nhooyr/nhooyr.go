@@ -0,0 +1,75 @@
+// The package nhooyr provides a [godxmap.Transport] based on nhooyr.io/websocket.
+// It is a separate module, so the dependency is only pulled in by the applications that use it:
+//
+//	server := godxmap.NewServer(":12345", godxmap.WithTransport(nhooyr.NewTransport()))
+package nhooyr
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"nhooyr.io/websocket"
+
+	"github.com/ftl/godxmap"
+)
+
+// Transport handles the websocket connections of a [godxmap.Server] with nhooyr.io/websocket.
+type Transport struct{}
+
+// NewTransport creates a new transport based on nhooyr.io/websocket.
+func NewTransport() *Transport {
+	return &Transport{}
+}
+
+// Handler implements [godxmap.Transport].
+func (*Transport) Handler(serve func(godxmap.TransportConn, *http.Request), logger *slog.Logger) http.Handler {
+	options := &websocket.AcceptOptions{
+		// HamDXMap is loaded from its own site, so we cannot restrict the origin
+		OriginPatterns:  []string{"*"},
+		CompressionMode: websocket.CompressionContextTakeover,
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, options)
+		if err != nil {
+			logger.Warn("cannot accept websocket connection", "remote_addr", r.RemoteAddr, "error", err)
+			return
+		}
+		conn.SetReadLimit(godxmap.MaxIncomingMessageSize)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		serve(connection{ctx: ctx, cancel: cancel, conn: conn, remoteAddr: r.RemoteAddr}, r)
+	})
+}
+
+type connection struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	conn       *websocket.Conn
+	remoteAddr string
+}
+
+func (c connection) WriteMessage(data []byte, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(c.ctx, timeout)
+	defer cancel()
+	return c.conn.Write(ctx, websocket.MessageText, data)
+}
+
+// ReadMessage also processes the control messages while waiting for the next data message.
+// If reading fails, the connection is broken and all pending writes are cancelled.
+func (c connection) ReadMessage() ([]byte, error) {
+	_, data, err := c.conn.Read(c.ctx)
+	if err != nil {
+		c.cancel()
+	}
+	return data, err
+}
+
+func (c connection) Close() error {
+	return c.conn.Close(websocket.StatusGoingAway, "")
+}
+
+func (c connection) RemoteAddr() string {
+	return c.remoteAddr
+}
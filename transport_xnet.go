@@ -0,0 +1,47 @@
+package godxmap
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// xnetTransport uses golang.org/x/net/websocket. This is the default transport.
+type xnetTransport struct{}
+
+func (xnetTransport) Handler(serve func(TransportConn, *http.Request), _ *slog.Logger) http.Handler {
+	return websocket.Handler(func(conn *websocket.Conn) {
+		conn.MaxPayloadBytes = MaxIncomingMessageSize
+		serve(xnetConn{conn}, conn.Request())
+	})
+}
+
+type xnetConn struct {
+	conn *websocket.Conn
+}
+
+// WriteMessage sends a text message, as the payload type of the connection is text by default.
+func (c xnetConn) WriteMessage(data []byte, timeout time.Duration) error {
+	err := c.conn.SetWriteDeadline(time.Now().Add(timeout))
+	if err != nil {
+		return err
+	}
+	_, err = c.conn.Write(data)
+	return err
+}
+
+func (c xnetConn) ReadMessage() ([]byte, error) {
+	var result []byte
+	err := websocket.Message.Receive(c.conn, &result)
+	return result, err
+}
+
+func (c xnetConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c xnetConn) RemoteAddr() string {
+	return c.conn.Request().RemoteAddr
+}
@@ -0,0 +1,91 @@
+// The package udpjson receives wtSock frames as JSON datagrams via UDP and broadcasts them through a [godxmap.Server].
+// This way, scripts in any language can put things on the map without speaking websockets, e.g.:
+//
+//	echo '{"Frame":"Gab","From":"script","Message":"hello"}' | nc -u -w0 127.0.0.1 12080
+package udpjson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/ftl/godxmap"
+)
+
+// DefaultAddr is the default address to receive the datagrams. It only accepts datagrams from the local host.
+const DefaultAddr = "127.0.0.1:12080"
+
+// Listener receives JSON datagrams that contain either a single frame or an array of frames.
+// Empty header fields of the frames are filled in by the server. Arrays are broadcast as batch.
+type Listener struct {
+	addr   string
+	server *godxmap.Server
+}
+
+// NewListener creates a new listener for the given UDP address that feeds the given server.
+// To actually receive datagrams, use the Run method.
+func NewListener(addr string, server *godxmap.Server) *Listener {
+	return &Listener{
+		addr:   addr,
+		server: server,
+	}
+}
+
+// Run receives and processes the datagrams until the given context is done.
+func (l *Listener) Run(ctx context.Context) error {
+	conn, err := net.ListenPacket("udp", l.addr)
+	if err != nil {
+		return fmt.Errorf("cannot listen for JSON datagrams on %s: %v", l.addr, err)
+	}
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	buffer := make([]byte, 64*1024)
+	for {
+		n, sender, err := conn.ReadFrom(buffer)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("cannot receive JSON datagram: %v", err)
+		}
+		err = l.handle(buffer[:n])
+		if err != nil {
+			l.server.Logger().Warn("cannot broadcast JSON datagram", "remote_addr", sender.String(), "error", err)
+		}
+	}
+}
+
+func (l *Listener) handle(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil
+	}
+	if data[0] != '[' {
+		f, err := godxmap.DecodeFrame(data)
+		if err != nil {
+			return err
+		}
+		return l.server.Send(f)
+	}
+
+	var rawFrames []json.RawMessage
+	err := json.Unmarshal(data, &rawFrames)
+	if err != nil {
+		return fmt.Errorf("cannot decode frames: %v", err)
+	}
+	frames := make([]godxmap.Frame, 0, len(rawFrames))
+	for _, rawFrame := range rawFrames {
+		f, err := godxmap.DecodeFrame(rawFrame)
+		if err != nil {
+			return err
+		}
+		frames = append(frames, f)
+	}
+	return l.server.SendBatch(frames)
+}
@@ -0,0 +1,174 @@
+package godxmap
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationLevel controls how strictly outgoing frames are validated before they are broadcast.
+type ValidationLevel int
+
+// The validation levels.
+const (
+	// ValidateNothing sends all frames as they are. This is the default.
+	ValidateNothing ValidationLevel = iota
+	// ValidateRequired checks that the required fields are present, frequencies are positive and messages are not too long.
+	ValidateRequired
+	// ValidateStrict additionally checks the callsign syntax and that frequencies are within the amateur radio bands.
+	ValidateStrict
+)
+
+// MaxMessageLength is the maximum length of gab messages in characters, not in bytes.
+const MaxMessageLength = 255
+
+// WithValidation validates all outgoing frames with the given level. Invalid frames are not sent,
+// instead the sending method returns a [ValidationError].
+func WithValidation(level ValidationLevel) Option {
+	return func(s *Server) {
+		s.validation = level
+	}
+}
+
+// ValidationError describes why a frame is invalid.
+type ValidationError struct {
+	FrameType string
+	Field     string
+	Reason    string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s frame: %s %s", e.FrameType, e.Field, e.Reason)
+}
+
+var (
+	// a complete callsign has at least one digit and ends with a letter, optionally with prefix and suffix
+	callsignExpression = regexp.MustCompile(`^([A-Z0-9]+/)?[A-Z0-9]*[0-9][A-Z0-9]*[A-Z](/[A-Z0-9]+)?$`)
+	// a partial callsign can be any part of a callsign
+	partialCallExpression = regexp.MustCompile(`^[A-Z0-9/]+$`)
+)
+
+// ValidateFrame checks the given frame with the given validation level.
+func ValidateFrame(f Frame, level ValidationLevel) error {
+	if level == ValidateNothing {
+		return nil
+	}
+	v := &frameValidator{frameType: f.FrameType(), level: level}
+	if f.Header().Frame == "" {
+		v.fail("Frame", "is missing")
+	}
+
+	switch f := f.(type) {
+	case *LoggedCallFrame:
+		v.callsign("Call", f.Call)
+		v.frequency("Frequency", f.Frequency)
+		v.position(f.Locator, f.Latitude, f.Longitude)
+	case *PartialCallFrame:
+		v.partialCall("Call", f.Call)
+		v.position(f.Locator, f.Latitude, f.Longitude)
+	case *DXSpotFrame:
+		v.callsign("Spot", f.Spot)
+		v.required("Spotter", f.Spotter)
+		v.frequency("Frequency", f.Frequency)
+		v.position(f.Locator, f.Latitude, f.Longitude)
+	case *GabFrame:
+		v.required("Message", f.Message)
+		v.maxLength("Message", f.Message, MaxMessageLength)
+	case *ClearCallFrame:
+		v.partialCall("Call", f.Call)
+	case *DeletedCallFrame:
+		v.callsign("Call", f.Call)
+	case *ContestExchangeFrame:
+		v.callsign("Call", f.Call)
+	case *SatelliteFrame:
+		v.required("Name", f.Name)
+		v.position("", &f.Latitude, &f.Longitude)
+	case *HeadingFrame:
+		if f.Azimuth < 0 || f.Azimuth >= 360 {
+			v.fail("Azimuth", "is out of range")
+		}
+	case *StationQTHFrame:
+		v.callsign("Call", f.Call)
+		if f.Locator == "" && (f.Latitude == nil || f.Longitude == nil) {
+			v.fail("Locator", "or position is missing")
+		}
+		v.position(f.Locator, f.Latitude, f.Longitude)
+	case *BandmapFrame:
+		v.required("Band", f.Band)
+		for _, entry := range f.Entries {
+			v.callsign("Entries.Call", entry.Call)
+			v.frequency("Entries.Frequency", entry.Frequency)
+		}
+	case *BandmapUpdateFrame:
+		v.required("Band", f.Band)
+	case *ZonesFrame:
+		v.required("System", string(f.System))
+	}
+
+	return v.err
+}
+
+type frameValidator struct {
+	frameType string
+	level     ValidationLevel
+	err       error
+}
+
+func (v *frameValidator) fail(field string, reason string) {
+	if v.err != nil {
+		return
+	}
+	v.err = &ValidationError{FrameType: v.frameType, Field: field, Reason: reason}
+}
+
+func (v *frameValidator) required(field string, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.fail(field, "is missing")
+	}
+}
+
+func (v *frameValidator) maxLength(field string, value string, length int) {
+	if utf8.RuneCountInString(value) > length {
+		v.fail(field, fmt.Sprintf("is longer than %d characters", length))
+	}
+}
+
+func (v *frameValidator) callsign(field string, value string) {
+	v.required(field, value)
+	if v.level >= ValidateStrict && value != "" && !callsignExpression.MatchString(strings.ToUpper(value)) {
+		v.fail(field, fmt.Sprintf("%q is not a valid callsign", value))
+	}
+}
+
+func (v *frameValidator) partialCall(field string, value string) {
+	v.required(field, value)
+	if v.level >= ValidateStrict && value != "" && !partialCallExpression.MatchString(strings.ToUpper(value)) {
+		v.fail(field, fmt.Sprintf("%q is not a valid partial callsign", value))
+	}
+}
+
+func (v *frameValidator) frequency(field string, value float64) {
+	if value <= 0 {
+		v.fail(field, "must be positive")
+		return
+	}
+	if v.level >= ValidateStrict && BandOf(value) == NoBand {
+		v.fail(field, fmt.Sprintf("%.1fkHz is outside of the amateur radio bands", value))
+	}
+}
+
+func (v *frameValidator) position(locator string, latitude *float64, longitude *float64) {
+	if v.level < ValidateStrict {
+		return
+	}
+	if locator != "" && !ValidLocator(locator) {
+		v.fail("Locator", fmt.Sprintf("%q is not a valid Maidenhead locator", locator))
+	}
+	if latitude != nil && (*latitude < -90 || *latitude > 90) {
+		v.fail("Latitude", "is out of range")
+	}
+	if longitude != nil && (*longitude < -180 || *longitude > 180) {
+		v.fail("Longitude", "is out of range")
+	}
+}
@@ -0,0 +1,84 @@
+package godxmap_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ftl/godxmap"
+	"github.com/ftl/godxmap/godxmaptest"
+)
+
+func TestValidateFrame(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		frame godxmap.Frame
+		level godxmap.ValidationLevel
+		field string
+	}{
+		{"nothing", &godxmap.DXSpotFrame{}, godxmap.ValidateNothing, ""},
+		{"valid spot", dxSpot("DL1ABC", "W1AW", 14025), godxmap.ValidateStrict, ""},
+		{"missing spotter", dxSpot("DL1ABC", "", 14025), godxmap.ValidateRequired, "Spotter"},
+		{"negative frequency", dxSpot("DL1ABC", "W1AW", -1), godxmap.ValidateRequired, "Frequency"},
+		{"frequency outside the bands", dxSpot("DL1ABC", "W1AW", 12000), godxmap.ValidateRequired, ""},
+		{"strict frequency outside the bands", dxSpot("DL1ABC", "W1AW", 12000), godxmap.ValidateStrict, "Frequency"},
+		{"invalid callsign", dxSpot("DL1-ABC", "W1AW", 14025), godxmap.ValidateStrict, "Spot"},
+		{"portable callsign", dxSpot("EA8/DL1ABC/P", "W1AW", 14025), godxmap.ValidateStrict, ""},
+		{"missing frame type", &godxmap.GabFrame{Message: "hello"}, godxmap.ValidateRequired, "Frame"},
+		{"maximum message", gab(strings.Repeat("a", godxmap.MaxMessageLength)), godxmap.ValidateRequired, ""},
+		{"long message", gab(strings.Repeat("a", godxmap.MaxMessageLength+1)), godxmap.ValidateRequired, "Message"},
+		{"multibyte message", gab(strings.Repeat("ä", godxmap.MaxMessageLength)), godxmap.ValidateRequired, ""},
+		{"long multibyte message", gab(strings.Repeat("ä", godxmap.MaxMessageLength+1)), godxmap.ValidateRequired, "Message"},
+		{"azimuth out of range", &godxmap.HeadingFrame{FrameHeader: godxmap.FrameHeader{Frame: godxmap.HeadingFrameType}, Azimuth: 360}, godxmap.ValidateRequired, "Azimuth"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := godxmap.ValidateFrame(tc.frame, tc.level)
+			if tc.field == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			var validationError *godxmap.ValidationError
+			if !errors.As(err, &validationError) {
+				t.Fatalf("expected a validation error, got %v", err)
+			}
+			if validationError.Field != tc.field {
+				t.Errorf("expected an invalid field %s, got %v", tc.field, err)
+			}
+		})
+	}
+}
+
+func TestInvalidFramesAreNotSent(t *testing.T) {
+	clock := godxmaptest.NewClock(time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC))
+	server := godxmaptest.NewServer(t, godxmap.WithValidation(godxmap.ValidateStrict), godxmap.WithClock(clock.Now))
+	recorder := godxmaptest.NewRecorder(t, server)
+
+	var validationError *godxmap.ValidationError
+	err := server.ShowDXSpot("DL1ABC", "W1AW", 12000, "")
+	if !errors.As(err, &validationError) {
+		t.Errorf("expected a validation error, got %v", err)
+	}
+	err = server.ShowGab("W1AW", "", strings.Repeat("ü", godxmap.MaxMessageLength))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	frames := recorder.Await(1)
+	if len(frames) != 1 || frames[0].FrameType() != godxmap.GabFrameType {
+		t.Errorf("unexpected frames: %v", frames)
+	}
+	if frames[0].Header().DateTime != clock.Now().UnixMilli() {
+		t.Errorf("the frame is not dated by the clock of the server: %d", frames[0].Header().DateTime)
+	}
+}
+
+func dxSpot(spot string, spotter string, frequency float64) *godxmap.DXSpotFrame {
+	return &godxmap.DXSpotFrame{FrameHeader: godxmap.FrameHeader{Frame: godxmap.DXSpotFrameType}, Spot: spot, Spotter: spotter, Frequency: frequency}
+}
+
+func gab(message string) *godxmap.GabFrame {
+	return &godxmap.GabFrame{FrameHeader: godxmap.FrameHeader{Frame: godxmap.GabFrameType}, From: "W1AW", Message: message}
+}
@@ -0,0 +1,36 @@
+package godxmap
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// ViewerPath is the path of the viewer page, see [WithViewer].
+const ViewerPath = "/viewer/"
+
+//go:embed viewer
+var viewerFiles embed.FS
+
+// WithViewer provides a simple map page on the same address as the websocket. It connects to the websocket and shows
+// the spots and calls on a plain world map, and the gab messages in a list, e.g. as end-to-end demo or for a quick check
+// without configuring HamDXMap:
+//
+//	http://localhost:8080/viewer/
+//
+// The page is bundled with the server and does not load any external resources, so it also works offline. It only
+// shows markers for frames with a position, e.g. with a locator or enriched by [WithEnrichers]. The viewer requires
+// the same token as the websocket, if [WithTokenAuthentication] is used; the token is passed on to the websocket.
+func WithViewer() Option {
+	return func(s *Server) {
+		s.viewer = true
+	}
+}
+
+func viewerHandler() http.Handler {
+	files, err := fs.Sub(viewerFiles, "viewer")
+	if err != nil {
+		panic(err)
+	}
+	return http.StripPrefix(ViewerPath, http.FileServerFS(files))
+}
@@ -0,0 +1,79 @@
+//go:build js && wasm
+
+// This program exposes the frame handling of godxmap to JavaScript, so custom map frontends
+// running in the browser use the same code to decode frames as the server uses to encode them.
+//
+// Build it with:
+//
+//	GOOS=js GOARCH=wasm go build -o godxmap.wasm ./wasm
+//
+// After loading godxmap.wasm with Go's wasm_exec.js, the global object godxmap provides:
+//
+//	godxmap.decodeFrame(json) // returns the decoded frame as object, or an Error if the frame cannot be decoded
+//	godxmap.decodeMessage(json) // returns the frames of a message as array, the message may be a single frame or a batch
+//	godxmap.bandOf(frequencyKHz) // returns the name of the band, e.g. "20m", or "" if out of band
+package main
+
+import (
+	"encoding/json"
+	"syscall/js"
+
+	"github.com/ftl/godxmap"
+)
+
+func main() {
+	api := js.Global().Get("Object").New()
+	api.Set("decodeFrame", js.FuncOf(decodeFrame))
+	api.Set("decodeMessage", js.FuncOf(decodeMessage))
+	api.Set("bandOf", js.FuncOf(bandOf))
+	js.Global().Set("godxmap", api)
+
+	// keep the exported functions alive
+	select {}
+}
+
+func decodeFrame(_ js.Value, args []js.Value) any {
+	if len(args) != 1 || args[0].Type() != js.TypeString {
+		return jsError("decodeFrame expects exactly one string argument")
+	}
+	frame, err := godxmap.DecodeFrame([]byte(args[0].String()))
+	if err != nil {
+		return jsError(err.Error())
+	}
+	return toJS(frame)
+}
+
+func decodeMessage(_ js.Value, args []js.Value) any {
+	if len(args) != 1 || args[0].Type() != js.TypeString {
+		return jsError("decodeMessage expects exactly one string argument")
+	}
+	frames, _, err := godxmap.DecodeMessage([]byte(args[0].String()))
+	if err != nil {
+		return jsError(err.Error())
+	}
+	if frames == nil {
+		frames = []godxmap.Frame{}
+	}
+	return toJS(frames)
+}
+
+// toJS hands the typed frames over to JavaScript as plain objects. The frames are decoded, but not validated.
+func toJS(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return jsError(err.Error())
+	}
+	return js.Global().Get("JSON").Call("parse", string(data))
+}
+
+func bandOf(_ js.Value, args []js.Value) any {
+	if len(args) != 1 || args[0].Type() != js.TypeNumber {
+		return jsError("bandOf expects exactly one number argument")
+	}
+	return string(godxmap.BandOf(args[0].Float()))
+}
+
+// jsError creates a JavaScript Error object. Panics in Go callbacks would terminate the whole wasm instance.
+func jsError(message string) any {
+	return js.Global().Get("Error").New(message)
+}
@@ -0,0 +1,183 @@
+// The package webhook forwards selected frames of a [godxmap.Server] to an HTTP endpoint, e.g. to trigger
+// external automations when interesting spots arrive.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+const (
+	defaultRetries = 3
+	defaultBackoff = time.Second
+	requestTimeout = 10 * time.Second
+	queueSize      = 256
+)
+
+// Filter decides if a frame is forwarded.
+type Filter func(godxmap.Frame) bool
+
+// Forwarder is a [godxmap.Sink] that POSTs the selected frames as JSON to a webhook URL.
+// Failed requests are retried with exponential backoff. If the endpoint cannot keep up, frames are dropped
+// instead of slowing down the server.
+type Forwarder struct {
+	url        string
+	frameTypes map[string]bool
+	filter     Filter
+	header     http.Header
+	retries    int
+	backoff    time.Duration
+	client     *http.Client
+	logger     *slog.Logger
+
+	queue chan godxmap.Frame
+}
+
+// Option configures a [Forwarder] instance.
+type Option func(*Forwarder)
+
+// WithFrameTypes only forwards frames of the given types. By default, all frames are forwarded.
+func WithFrameTypes(frameTypes ...string) Option {
+	return func(f *Forwarder) {
+		for _, frameType := range frameTypes {
+			f.frameTypes[frameType] = true
+		}
+	}
+}
+
+// WithFilter only forwards the frames that are accepted by the given filter.
+func WithFilter(filter Filter) Option {
+	return func(f *Forwarder) {
+		f.filter = filter
+	}
+}
+
+// WithHeader adds the given header to every request, e.g. for authentication.
+func WithHeader(key string, value string) Option {
+	return func(f *Forwarder) {
+		f.header.Add(key, value)
+	}
+}
+
+// WithRetries sets how often a failed request is retried. The default is three times.
+func WithRetries(retries int) Option {
+	return func(f *Forwarder) {
+		f.retries = retries
+	}
+}
+
+// WithBackoff sets the delay before the first retry. The delay is doubled with every retry. The default is one second.
+func WithBackoff(backoff time.Duration) Option {
+	return func(f *Forwarder) {
+		f.backoff = backoff
+	}
+}
+
+// WithLogger writes the log messages of the forwarder to the given logger. By default, [slog.Default] is used.
+func WithLogger(logger *slog.Logger) Option {
+	return func(f *Forwarder) {
+		f.logger = logger
+	}
+}
+
+// NewForwarder creates a new forwarder for the given webhook URL.
+// Register it with [godxmap.WithSink] and use the Run method to actually forward the frames.
+func NewForwarder(url string, options ...Option) *Forwarder {
+	result := &Forwarder{
+		url:        url,
+		frameTypes: make(map[string]bool),
+		header:     make(http.Header),
+		retries:    defaultRetries,
+		backoff:    defaultBackoff,
+		client:     &http.Client{Timeout: requestTimeout},
+		logger:     slog.Default(),
+		queue:      make(chan godxmap.Frame, queueSize),
+	}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+// Publish implements [godxmap.Sink].
+func (f *Forwarder) Publish(frame godxmap.Frame) {
+	if len(f.frameTypes) > 0 && !f.frameTypes[frame.FrameType()] {
+		return
+	}
+	if f.filter != nil && !f.filter(frame) {
+		return
+	}
+	select {
+	case f.queue <- frame:
+	default:
+		f.logger.Warn("cannot forward frame, queue is full", "url", f.url, "frame_type", frame.FrameType(), "frame_id", frame.Header().ID)
+	}
+}
+
+// Run forwards the frames until the given context is done.
+func (f *Forwarder) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case frame := <-f.queue:
+			err := f.forward(ctx, frame)
+			if err != nil && ctx.Err() == nil {
+				f.logger.Warn("cannot forward frame", "url", f.url, "frame_type", frame.FrameType(), "frame_id", frame.Header().ID, "error", err)
+			}
+		}
+	}
+}
+
+func (f *Forwarder) forward(ctx context.Context, frame godxmap.Frame) error {
+	payload, err := godxmap.EncodeFrame(frame)
+	if err != nil {
+		return err
+	}
+
+	backoff := f.backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := f.post(ctx, payload)
+		if err == nil || !retry || attempt >= f.retries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post sends the payload and reports if a failed request should be retried.
+func (f *Forwarder) post(ctx context.Context, payload []byte) (bool, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, f.url, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	for key, values := range f.header {
+		request.Header[key] = values
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := f.client.Do(request)
+	if err != nil {
+		return true, err
+	}
+	response.Body.Close()
+	switch {
+	case response.StatusCode < 300:
+		return false, nil
+	case response.StatusCode == http.StatusTooManyRequests, response.StatusCode >= 500:
+		return true, fmt.Errorf("%s", response.Status)
+	default:
+		return false, fmt.Errorf("%s", response.Status)
+	}
+}
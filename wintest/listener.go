@@ -0,0 +1,125 @@
+// The package wintest listens for the UDP network broadcasts of Win-Test and relays them as wtSock frames
+// through a [godxmap.Server]. This way, godxmap acts as a bridge between a Win-Test LAN and HamDXMap.
+package wintest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/ftl/godxmap"
+)
+
+// DefaultAddr is the default address of the Win-Test network broadcasts.
+const DefaultAddr = ":9871"
+
+// Listener receives the UDP broadcasts of Win-Test and translates them into frames:
+//   - GAB messages are shown as gab,
+//   - ADDQSO messages are shown as logged call,
+//   - DELQSO messages are shown as deleted call.
+//
+// All other messages are ignored.
+type Listener struct {
+	addr   string
+	server *godxmap.Server
+}
+
+// NewListener creates a new listener for the given UDP address that feeds the given server.
+// To actually receive broadcasts, use the Run method.
+func NewListener(addr string, server *godxmap.Server) *Listener {
+	return &Listener{
+		addr:   addr,
+		server: server,
+	}
+}
+
+// Run receives and processes the Win-Test broadcasts until the given context is done.
+func (l *Listener) Run(ctx context.Context) error {
+	conn, err := net.ListenPacket("udp", l.addr)
+	if err != nil {
+		return fmt.Errorf("cannot listen for Win-Test broadcasts on %s: %v", l.addr, err)
+	}
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	buffer := make([]byte, 64*1024)
+	for {
+		n, _, err := conn.ReadFrom(buffer)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("cannot receive Win-Test broadcast: %v", err)
+		}
+		message, err := parseMessage(buffer[:n])
+		if err != nil {
+			l.server.Logger().Warn("invalid Win-Test broadcast", "error", err)
+			continue
+		}
+		err = l.handle(message)
+		if err != nil {
+			l.server.Logger().Warn("cannot relay Win-Test message", "command", message.Command, "error", err)
+		}
+	}
+}
+
+func (l *Listener) handle(m message) error {
+	switch m.Command {
+	case "GAB":
+		// GAB: "from" "to" "text"
+		if len(m.Arguments) < 3 {
+			return nil
+		}
+		return l.server.ShowGab(m.Arguments[0], m.Arguments[1], strings.Join(m.Arguments[2:], " "))
+	case "ADDQSO":
+		call := qsoCall(m.Arguments)
+		if call == "" {
+			return nil
+		}
+		return l.server.ShowLoggedCall(call, 0)
+	case "DELQSO":
+		call := qsoCall(m.Arguments)
+		if call == "" {
+			return nil
+		}
+		return l.server.ShowDeletedCall(call, 0)
+	}
+	return nil
+}
+
+// qsoCall returns the callsign of a QSO message. The first two arguments are the source and the destination station,
+// the callsign is the first of the following arguments that looks like a callsign.
+func qsoCall(arguments []string) string {
+	if len(arguments) < 3 {
+		return ""
+	}
+	for _, argument := range arguments[2:] {
+		if looksLikeCallsign(argument) {
+			return strings.ToUpper(argument)
+		}
+	}
+	return ""
+}
+
+func looksLikeCallsign(s string) bool {
+	if len(s) < 3 {
+		return false
+	}
+	hasDigit, hasLetter := false, false
+	for _, r := range strings.ToUpper(s) {
+		switch {
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		case r >= 'A' && r <= 'Z':
+			hasLetter = true
+		case r == '/':
+		default:
+			return false
+		}
+	}
+	return hasDigit && hasLetter
+}
@@ -0,0 +1,67 @@
+package wintest
+
+import (
+	"errors"
+	"strings"
+)
+
+// message is a single Win-Test network message, e.g. `GAB: "STN1" "" "hello"`.
+type message struct {
+	Command   string
+	Arguments []string
+}
+
+// parseMessage parses a Win-Test broadcast datagram. The datagram ends with a checksum byte and a null byte.
+// The checksum is the sum of all preceding bytes with the highest bit set.
+func parseMessage(data []byte) (message, error) {
+	data = []byte(strings.TrimRight(string(data), "\x00"))
+	if len(data) < 2 {
+		return message{}, errors.New("message too short")
+	}
+	checksum := data[len(data)-1]
+	data = data[:len(data)-1]
+	var sum byte
+	for _, b := range data {
+		sum += b
+	}
+	if sum|0x80 != checksum {
+		return message{}, errors.New("invalid checksum")
+	}
+
+	command, arguments, found := strings.Cut(string(data), ":")
+	if !found {
+		return message{}, errors.New("missing command")
+	}
+	return message{
+		Command:   strings.ToUpper(strings.TrimSpace(command)),
+		Arguments: splitArguments(arguments),
+	}, nil
+}
+
+// splitArguments splits the arguments of a message at whitespace. Quoted arguments may contain whitespace.
+func splitArguments(s string) []string {
+	var result []string
+	var current strings.Builder
+	quoted := false
+	inArgument := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inArgument = true
+		case (r == ' ' || r == '\t') && !quoted:
+			if inArgument {
+				result = append(result, current.String())
+				current.Reset()
+				inArgument = false
+			}
+		default:
+			current.WriteRune(r)
+			inArgument = true
+		}
+	}
+	if inArgument {
+		result = append(result, current.String())
+	}
+	return result
+}
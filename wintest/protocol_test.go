@@ -0,0 +1,110 @@
+package wintest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// datagram appends the checksum and the null byte to the given message, like Win-Test does.
+func datagram(s string) []byte {
+	var sum byte
+	for _, b := range []byte(s) {
+		sum += b
+	}
+	return append([]byte(s), sum|0x80, 0)
+}
+
+func TestParseMessage(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		data     []byte
+		expected message
+	}{
+		{
+			name:     "gab",
+			data:     datagram(`GAB: "STN1" "" "hello world"`),
+			expected: message{Command: "GAB", Arguments: []string{"STN1", "", "hello world"}},
+		},
+		{
+			name:     "add QSO",
+			data:     datagram(`ADDQSO: "STN1" "" 1761394530 140250 0 1 0 "w1aw" "599" "05" "" 0 0`),
+			expected: message{Command: "ADDQSO", Arguments: []string{"STN1", "", "1761394530", "140250", "0", "1", "0", "w1aw", "599", "05", "", "0", "0"}},
+		},
+		{
+			name:     "lower case command and tabs",
+			data:     datagram("delqso:\t\"STN1\"\t\"\"\tW1AW"),
+			expected: message{Command: "DELQSO", Arguments: []string{"STN1", "", "W1AW"}},
+		},
+		{
+			name:     "without null byte",
+			data:     datagram(`GAB: "STN1" "" "hi"`)[:len(`GAB: "STN1" "" "hi"`)+1],
+			expected: message{Command: "GAB", Arguments: []string{"STN1", "", "hi"}},
+		},
+		{
+			name:     "without arguments",
+			data:     datagram(`SUMMARY:`),
+			expected: message{Command: "SUMMARY"},
+		},
+		{
+			name:     "maximum datagram",
+			data:     datagram(`GAB: "STN1" "" "` + strings.Repeat("x", 65000) + `"`),
+			expected: message{Command: "GAB", Arguments: []string{"STN1", "", strings.Repeat("x", 65000)}},
+		},
+		{
+			name:     "unterminated quote",
+			data:     datagram(`GAB: "STN1" "" "hello`),
+			expected: message{Command: "GAB", Arguments: []string{"STN1", "", "hello"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := parseMessage(tc.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("expected\n%#v\ngot\n%#v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseInvalidMessage(t *testing.T) {
+	invalidChecksum := datagram(`GAB: "STN1" "" "hello"`)
+	invalidChecksum[len(invalidChecksum)-2]++
+	for _, tc := range []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"only null bytes", []byte{0, 0, 0}},
+		{"only checksum", []byte{0x80, 0}},
+		{"invalid checksum", invalidChecksum},
+		{"truncated", datagram(`GAB: "STN1" "" "hello"`)[:10]},
+		{"missing command", datagram(`hello world`)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := parseMessage(tc.data)
+			if err == nil {
+				t.Errorf("expected an error, got %#v", actual)
+			}
+		})
+	}
+}
+
+func TestQSOCall(t *testing.T) {
+	for _, tc := range []struct {
+		arguments []string
+		expected  string
+	}{
+		{[]string{"STN1", "", "1761394530", "140250", "0", "1", "0", "w1aw", "599"}, "W1AW"},
+		{[]string{"STN1", "", "DL1ABC/P"}, "DL1ABC/P"},
+		{[]string{"STN1", "", "599", "14", "K1"}, ""},
+		{[]string{"W1AW", "DL1ABC"}, ""},
+		{nil, ""},
+	} {
+		if actual := qsoCall(tc.arguments); actual != tc.expected {
+			t.Errorf("%v: expected %q, got %q", tc.arguments, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// The package wsjtx listens for the UDP messages of WSJT-X and shows the decoded and logged stations on the map of a [godxmap.Server].
+package wsjtx
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+// DefaultAddr is the default address where WSJT-X sends its UDP messages to.
+const DefaultAddr = "127.0.0.1:2237"
+
+// Listener receives the UDP messages of WSJT-X and translates them into frames:
+//   - the DX call of a status message is shown as partial call,
+//   - every decoded station is shown as DX spot, spotted by the own station, placed at its grid square if known,
+//   - every logged QSO is shown as logged call.
+type Listener struct {
+	addr   string
+	server *godxmap.Server
+	source godxmap.SourceRunner
+
+	mutex  sync.Mutex
+	status map[string]Status
+}
+
+// NewListener creates a new listener for the given UDP address that feeds the given server.
+// To actually receive messages, use the Run method.
+func NewListener(addr string, server *godxmap.Server) *Listener {
+	return &Listener{
+		addr:   addr,
+		server: server,
+		status: make(map[string]Status),
+	}
+}
+
+// Run receives and processes the WSJT-X messages until the given context is done.
+func (l *Listener) Run(ctx context.Context) error {
+	conn, err := net.ListenPacket("udp", l.addr)
+	if err != nil {
+		return fmt.Errorf("cannot listen for WSJT-X messages on %s: %v", l.addr, err)
+	}
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	buffer := make([]byte, 64*1024)
+	for {
+		n, _, err := conn.ReadFrom(buffer)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("cannot receive WSJT-X message: %v", err)
+		}
+		message, err := parseMessage(buffer[:n])
+		if err == errUnsupportedMessage {
+			continue
+		}
+		if err != nil {
+			l.server.Logger().Warn("invalid WSJT-X message", "error", err)
+			continue
+		}
+		l.handle(message)
+	}
+}
+
+// Start runs the listener in the background. The decoded stations are emitted on the given channel instead of
+// being shown directly. Start, Stop and Status implement [godxmap.SpotSource] and [godxmap.SourceStatus].
+func (l *Listener) Start(spots chan<- godxmap.Spot) error {
+	return l.source.Start(spots, l.Run)
+}
+
+// Stop stops the listener that was started with Start.
+func (l *Listener) Stop() {
+	l.source.Stop()
+}
+
+// Status reports if the listener that was started with Start is still running.
+func (l *Listener) Status() (bool, error) {
+	return l.source.Status()
+}
+
+func (l *Listener) showSpot(spot godxmap.Spot) error {
+	if l.source.Emit(spot) {
+		return nil
+	}
+	return l.server.ShowSpot(spot)
+}
+
+func (l *Listener) handle(message any) {
+	var err error
+	switch message := message.(type) {
+	case Status:
+		l.mutex.Lock()
+		previous := l.status[message.ID]
+		l.status[message.ID] = message
+		l.mutex.Unlock()
+
+		if message.DXCall != "" && (message.DXCall != previous.DXCall || message.DXGrid != previous.DXGrid) {
+			err = l.server.ShowPartialCallInfo(message.DXCall, godxmap.CallInfo{Locator: message.DXGrid})
+		}
+	case Decode:
+		err = l.handleDecode(message)
+	case QSOLogged:
+		err = l.server.ShowLoggedQSO(godxmap.QSO{
+			Call:         message.DXCall,
+			FrequencyKHz: float64(message.TXFrequencyHz) / 1000,
+			Mode:         godxmap.Mode(message.Mode),
+			Exchange:     message.ExchangeReceived,
+			Operator:     message.OperatorCall,
+			Time:         message.TimeOff,
+			Locator:      message.DXGrid,
+		})
+	}
+	if err != nil {
+		l.server.Logger().Warn("cannot show WSJT-X message", "error", err)
+	}
+}
+
+func (l *Listener) handleDecode(decode Decode) error {
+	if !decode.New || decode.OffAir {
+		return nil
+	}
+	call := senderOf(decode.Message)
+	if call == "" {
+		return nil
+	}
+
+	l.mutex.Lock()
+	status, ok := l.status[decode.ID]
+	l.mutex.Unlock()
+	if !ok || status.DialFrequencyHz == 0 {
+		// without the dial frequency, we cannot place the spot
+		return nil
+	}
+
+	frequencyKHz := float64(status.DialFrequencyHz+uint64(decode.DeltaFrequency)) / 1000
+	return l.showSpot(godxmap.Spot{
+		Time:         decodeTime(l.server.Now(), decode.Time),
+		DX:           call,
+		Spotter:      status.DECall,
+		FrequencyKHz: frequencyKHz,
+		Comments:     fmt.Sprintf("%s %+d dB", status.Mode, decode.SNR),
+		// if the exact position is known, there is no need to place the spot at the center of its DXCC entity
+		Locator: gridOf(decode.Message),
+	})
+}
+
+// decodeTime returns the point in time of a decode that happened at the given time since midnight UTC.
+// A decode from shortly before midnight that is received after midnight belongs to the previous day.
+func decodeTime(now time.Time, sinceMidnight time.Duration) time.Time {
+	now = now.UTC()
+	result := now.Truncate(24 * time.Hour).Add(sinceMidnight)
+	// tolerate that the clock of WSJT-X is a bit ahead of ours
+	if result.Sub(now) > 12*time.Hour {
+		result = result.Add(-24 * time.Hour)
+	}
+	return result
+}
+
+// senderOf extracts the callsign of the sending station from a decoded standard message,
+// e.g. "CQ DX K1ABC FN42" or "K1ABC W9XYZ -12".
+func senderOf(message string) string {
+	words := strings.Fields(strings.ToUpper(message))
+	if len(words) < 2 {
+		return ""
+	}
+	if words[0] == "CQ" {
+		// CQ with optional directed call, e.g. "CQ DX K1ABC FN42" or "CQ POTA K1ABC FN42"
+		for _, word := range words[1:] {
+			if isCallsign(word) {
+				return strings.Trim(word, "<>")
+			}
+		}
+		return ""
+	}
+	if isCallsign(words[1]) {
+		return strings.Trim(words[1], "<>")
+	}
+	return ""
+}
+
+// gridOf extracts the grid square of the sending station from a decoded standard message, e.g. "CQ DX K1ABC FN42"
+// or "W9XYZ K1ABC FN42". It returns an empty string if the message does not end with a grid square.
+func gridOf(message string) string {
+	words := strings.Fields(strings.ToUpper(message))
+	if len(words) < 3 {
+		return ""
+	}
+	grid := words[len(words)-1]
+	if len(grid) != 4 || grid == "RR73" || !godxmap.ValidLocator(grid) {
+		return ""
+	}
+	return grid
+}
+
+func isCallsign(word string) bool {
+	word = strings.Trim(word, "<>")
+	if len(word) < 3 || word == "..." {
+		return false
+	}
+	hasDigit, hasLetter := false, false
+	for _, r := range word {
+		switch {
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		case r >= 'A' && r <= 'Z':
+			hasLetter = true
+		case r == '/':
+		default:
+			return false
+		}
+	}
+	return hasDigit && hasLetter
+}
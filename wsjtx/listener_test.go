@@ -0,0 +1,48 @@
+package wsjtx
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecodeTime(t *testing.T) {
+	tt := []struct {
+		name          string
+		now           time.Time
+		sinceMidnight time.Duration
+		expected      time.Time
+	}{
+		{
+			name:          "same day",
+			now:           time.Date(2025, 10, 25, 12, 0, 3, 0, time.UTC),
+			sinceMidnight: 12 * time.Hour,
+			expected:      time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "received after midnight",
+			now:           time.Date(2025, 10, 26, 0, 0, 1, 0, time.UTC),
+			sinceMidnight: 23*time.Hour + 59*time.Minute + 45*time.Second,
+			expected:      time.Date(2025, 10, 25, 23, 59, 45, 0, time.UTC),
+		},
+		{
+			name:          "clock of WSJT-X slightly ahead",
+			now:           time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC),
+			sinceMidnight: 12*time.Hour + 500*time.Millisecond,
+			expected:      time.Date(2025, 10, 25, 12, 0, 0, int(500*time.Millisecond), time.UTC),
+		},
+		{
+			name:          "local time zone",
+			now:           time.Date(2025, 10, 26, 1, 0, 1, 0, time.FixedZone("CET", 3600)),
+			sinceMidnight: 23*time.Hour + 59*time.Minute + 45*time.Second,
+			expected:      time.Date(2025, 10, 25, 23, 59, 45, 0, time.UTC),
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := decodeTime(tc.now, tc.sinceMidnight)
+			if !actual.Equal(tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package wsjtx
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const magic = 0xadbccbda
+
+// The message types of the WSJT-X network protocol that are handled by this package.
+const (
+	statusMessageType    = 1
+	decodeMessageType    = 2
+	qsoLoggedMessageType = 5
+)
+
+// Status is sent by WSJT-X whenever its state changes.
+type Status struct {
+	ID               string
+	DialFrequencyHz  uint64
+	Mode             string
+	DXCall           string
+	Report           string
+	TXMode           string
+	TXEnabled        bool
+	Transmitting     bool
+	Decoding         bool
+	RXDeltaFrequency uint32
+	TXDeltaFrequency uint32
+	DECall           string
+	DEGrid           string
+	DXGrid           string
+}
+
+// Decode is sent by WSJT-X for every decoded message.
+type Decode struct {
+	ID             string
+	New            bool
+	Time           time.Duration // since midnight UTC
+	SNR            int32
+	DeltaTime      float64
+	DeltaFrequency uint32
+	Mode           string
+	Message        string
+	LowConfidence  bool
+	OffAir         bool
+}
+
+// QSOLogged is sent by WSJT-X when a QSO was logged.
+type QSOLogged struct {
+	ID               string
+	TimeOff          time.Time
+	DXCall           string
+	DXGrid           string
+	TXFrequencyHz    uint64
+	Mode             string
+	ReportSent       string
+	ReportReceived   string
+	TXPower          string
+	Comments         string
+	Name             string
+	TimeOn           time.Time
+	OperatorCall     string
+	MyCall           string
+	MyGrid           string
+	ExchangeSent     string
+	ExchangeReceived string
+}
+
+var errUnsupportedMessage = errors.New("unsupported message type")
+
+// parseMessage parses a single datagram. It returns errUnsupportedMessage for all message types that are not handled by this package.
+func parseMessage(data []byte) (any, error) {
+	r := &reader{data: data}
+	if r.uint32() != magic {
+		return nil, errors.New("invalid magic number")
+	}
+	r.uint32() // schema
+	messageType := r.uint32()
+	id := r.utf8()
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var result any
+	switch messageType {
+	case statusMessageType:
+		result = Status{
+			ID:               id,
+			DialFrequencyHz:  r.uint64(),
+			Mode:             r.utf8(),
+			DXCall:           r.utf8(),
+			Report:           r.utf8(),
+			TXMode:           r.utf8(),
+			TXEnabled:        r.bool(),
+			Transmitting:     r.bool(),
+			Decoding:         r.bool(),
+			RXDeltaFrequency: r.uint32(),
+			TXDeltaFrequency: r.uint32(),
+			DECall:           r.utf8(),
+			DEGrid:           r.utf8(),
+			DXGrid:           r.utf8(),
+		}
+	case decodeMessageType:
+		result = Decode{
+			ID:             id,
+			New:            r.bool(),
+			Time:           time.Duration(r.uint32()) * time.Millisecond,
+			SNR:            int32(r.uint32()),
+			DeltaTime:      r.float64(),
+			DeltaFrequency: r.uint32(),
+			Mode:           r.utf8(),
+			Message:        r.utf8(),
+			LowConfidence:  r.bool(),
+			OffAir:         r.bool(),
+		}
+	case qsoLoggedMessageType:
+		result = QSOLogged{
+			ID:               id,
+			TimeOff:          r.dateTime(),
+			DXCall:           r.utf8(),
+			DXGrid:           r.utf8(),
+			TXFrequencyHz:    r.uint64(),
+			Mode:             r.utf8(),
+			ReportSent:       r.utf8(),
+			ReportReceived:   r.utf8(),
+			TXPower:          r.utf8(),
+			Comments:         r.utf8(),
+			Name:             r.utf8(),
+			TimeOn:           r.dateTime(),
+			OperatorCall:     r.utf8(),
+			MyCall:           r.utf8(),
+			MyGrid:           r.utf8(),
+			ExchangeSent:     r.utf8(),
+			ExchangeReceived: r.utf8(),
+		}
+	default:
+		return nil, errUnsupportedMessage
+	}
+
+	if r.err != nil {
+		return nil, fmt.Errorf("cannot parse message of type %d: %v", messageType, r.err)
+	}
+	return result, nil
+}
+
+// reader decodes the big endian QDataStream encoding used by WSJT-X. Missing trailing fields of older WSJT-X versions
+// are tolerated, they keep their zero value.
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) next(n int) []byte {
+	if r.err != nil || len(r.data) < n {
+		if r.err == nil && len(r.data) > 0 {
+			r.err = errors.New("truncated message")
+		}
+		r.data = nil
+		return nil
+	}
+	result := r.data[:n]
+	r.data = r.data[n:]
+	return result
+}
+
+func (r *reader) uint8() uint8 {
+	b := r.next(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (r *reader) bool() bool {
+	return r.uint8() != 0
+}
+
+func (r *reader) uint32() uint32 {
+	b := r.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (r *reader) uint64() uint64 {
+	b := r.next(8)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+func (r *reader) float64() float64 {
+	return math.Float64frombits(r.uint64())
+}
+
+func (r *reader) utf8() string {
+	length := r.uint32()
+	if length == 0xffffffff || length == 0 {
+		return ""
+	}
+	// compare before the conversion, a large length would become negative as int on 32-bit platforms
+	if uint64(length) > uint64(len(r.data)) {
+		if r.err == nil {
+			r.err = fmt.Errorf("string of %d bytes exceeds the message", length)
+		}
+		r.data = nil
+		return ""
+	}
+	b := r.next(int(length))
+	return string(b)
+}
+
+// julianDayOfUnixEpoch is the julian day number of 1970-01-01
+const julianDayOfUnixEpoch = 2440588
+
+func (r *reader) dateTime() time.Time {
+	julianDay := int64(r.uint64())
+	milliseconds := r.uint32()
+	timeSpec := r.uint8()
+	if timeSpec == 2 {
+		r.uint32() // offset from UTC in seconds, not needed
+	}
+	if julianDay == 0 {
+		return time.Time{}
+	}
+	days := julianDay - julianDayOfUnixEpoch
+	return time.Unix(days*24*60*60, 0).UTC().Add(time.Duration(milliseconds) * time.Millisecond)
+}
@@ -0,0 +1,129 @@
+package wsjtx
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+// datagram builds WSJT-X messages in the QDataStream encoding.
+type datagram []byte
+
+func newDatagram(messageType uint32, id string) datagram {
+	return datagram(nil).uint32(magic).uint32(2).uint32(messageType).utf8(id)
+}
+
+func (d datagram) uint32(v uint32) datagram {
+	return binary.BigEndian.AppendUint32(d, v)
+}
+
+func (d datagram) uint64(v uint64) datagram {
+	return binary.BigEndian.AppendUint64(d, v)
+}
+
+func (d datagram) bool(v bool) datagram {
+	if v {
+		return append(d, 1)
+	}
+	return append(d, 0)
+}
+
+func (d datagram) utf8(s string) datagram {
+	return append(d.uint32(uint32(len(s))), s...)
+}
+
+func decodeDatagram(message string) datagram {
+	return newDatagram(decodeMessageType, "WSJT-X").
+		bool(true).
+		uint32(uint32((12*time.Hour + 30*time.Minute + 15*time.Second) / time.Millisecond)).
+		uint32(uint32(0xfffffff4)). // -12 dB
+		uint64(math.Float64bits(0.2)).
+		uint32(1234).
+		utf8("~").
+		utf8(message).
+		bool(false).
+		bool(false)
+}
+
+func TestParseMessage(t *testing.T) {
+	tt := []struct {
+		name     string
+		data     datagram
+		expected any
+	}{
+		{
+			name: "status",
+			data: newDatagram(statusMessageType, "WSJT-X").
+				uint64(14074000).utf8("FT8").utf8("K1ABC").utf8("-12").utf8("FT8").
+				bool(false).bool(false).bool(true).uint32(1500).uint32(1500).
+				utf8("DL1ABC").utf8("JO62").utf8("FN42"),
+			expected: Status{ID: "WSJT-X", DialFrequencyHz: 14074000, Mode: "FT8", DXCall: "K1ABC", Report: "-12", TXMode: "FT8",
+				Decoding: true, RXDeltaFrequency: 1500, TXDeltaFrequency: 1500, DECall: "DL1ABC", DEGrid: "JO62", DXGrid: "FN42"},
+		},
+		{
+			name: "decode",
+			data: decodeDatagram("CQ K1ABC FN42"),
+			expected: Decode{ID: "WSJT-X", New: true, Time: 12*time.Hour + 30*time.Minute + 15*time.Second, SNR: -12, DeltaTime: 0.2,
+				DeltaFrequency: 1234, Mode: "~", Message: "CQ K1ABC FN42"},
+		},
+		{
+			name:     "status of an older version without the trailing fields",
+			data:     newDatagram(statusMessageType, "WSJT-X").uint64(7074000).utf8("FT8"),
+			expected: Status{ID: "WSJT-X", DialFrequencyHz: 7074000, Mode: "FT8"},
+		},
+		{
+			name:     "null string",
+			data:     newDatagram(statusMessageType, "WSJT-X").uint64(7074000).uint32(0xffffffff),
+			expected: Status{ID: "WSJT-X", DialFrequencyHz: 7074000},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := parseMessage(tc.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != tc.expected {
+				t.Errorf("expected\n%+v\ngot\n%+v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseInvalidMessage(t *testing.T) {
+	decode := decodeDatagram("CQ K1ABC FN42")
+	tt := []struct {
+		name string
+		data datagram
+	}{
+		{"empty", nil},
+		{"wrong magic number", datagram(nil).uint32(0x12345678).uint32(2).uint32(decodeMessageType).utf8("WSJT-X")},
+		{"truncated header", decode[:10]},
+		{"truncated number", decode[:len(decode)-len("CQ K1ABC FN42")-9]},
+		{"truncated string", decode[:len(decode)-5]},
+		{"string longer than the message", newDatagram(statusMessageType, "WSJT-X").uint64(7074000).uint32(100).utf8("FT8")},
+		{"string longer than 2^31 bytes", newDatagram(statusMessageType, "WSJT-X").uint64(7074000).uint32(0x80000000).utf8("FT8")},
+		{"string of 2^32-2 bytes", newDatagram(statusMessageType, "WSJT-X").uint64(7074000).uint32(0xfffffffe).utf8("FT8")},
+		{"id longer than the message", datagram(nil).uint32(magic).uint32(2).uint32(decodeMessageType).uint32(0x80000000)},
+		{"unsupported message type", newDatagram(12, "WSJT-X")},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := parseMessage(tc.data)
+			if err == nil {
+				t.Errorf("expected an error, got %+v", actual)
+			}
+		})
+	}
+}
+
+func FuzzParseMessage(f *testing.F) {
+	f.Add([]byte(decodeDatagram("CQ K1ABC FN42")))
+	f.Add([]byte(newDatagram(statusMessageType, "WSJT-X").uint64(14074000).utf8("FT8")))
+	f.Add([]byte(newDatagram(qsoLoggedMessageType, "WSJT-X").uint64(2460974).uint32(0).bool(true).utf8("K1ABC")))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// must not panic
+		parseMessage(data)
+	})
+}
@@ -0,0 +1,206 @@
+// The package wsprnet polls the WSPR reception reports of the WSPRnet database and shows them on the map of a [godxmap.Server],
+// e.g. to watch the propagation footprint of the own beacon.
+package wsprnet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ftl/godxmap"
+)
+
+// DefaultURL is the address of the public query API of wspr.live, which mirrors the WSPRnet database.
+const DefaultURL = "https://db1.wspr.live/"
+
+const (
+	defaultInterval = 2 * time.Minute
+	requestTimeout  = 30 * time.Second
+	// initialWindow is the time span of the reports that are fetched with the first poll.
+	initialWindow = 30 * time.Minute
+	maxReports    = 1000
+)
+
+var (
+	callExpression = regexp.MustCompile(`^[A-Z0-9/]+$`)
+	gridExpression = regexp.MustCompile(`^[A-R]{2}([0-9]{2}([A-X]{2})?)?$`)
+)
+
+// Filter selects the reports of the transmitting stations. At least one field must be set.
+type Filter struct {
+	// Call is the callsign of the transmitting station, e.g. the own beacon.
+	Call string
+	// Grid is the Maidenhead locator of the transmitting stations, a field, square or subsquare.
+	Grid string
+}
+
+func (f Filter) conditions() ([]string, error) {
+	var result []string
+	if f.Call != "" {
+		call := strings.ToUpper(f.Call)
+		if !callExpression.MatchString(call) {
+			return nil, fmt.Errorf("invalid callsign %q", f.Call)
+		}
+		result = append(result, fmt.Sprintf("tx_sign = '%s'", call))
+	}
+	if f.Grid != "" {
+		grid := strings.ToUpper(f.Grid)
+		if !gridExpression.MatchString(grid) {
+			return nil, fmt.Errorf("invalid grid %q", f.Grid)
+		}
+		result = append(result, fmt.Sprintf("upper(tx_loc) LIKE '%s%%'", grid))
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("empty filter")
+	}
+	return result, nil
+}
+
+// Report is a WSPR reception report.
+type Report struct {
+	ID int64 `json:"id"`
+	// Time in Unix seconds.
+	Time            int64  `json:"time"`
+	TransmitCall    string `json:"tx_sign"`
+	TransmitGrid    string `json:"tx_loc"`
+	ReceiverCall    string `json:"rx_sign"`
+	ReceiverGrid    string `json:"rx_loc"`
+	FrequencyHz     int64  `json:"frequency"`
+	SNR             int    `json:"snr"`
+	PowerDBm        int    `json:"power"`
+	DistanceKm      int    `json:"distance"`
+	ReceiverAzimuth int    `json:"rx_azimuth"`
+}
+
+// Poller polls the WSPRnet database and shows every new reception report as spot of the receiving station
+// at its locator, spotted by the transmitting station. This way, the spots draw the footprint of the transmitter.
+type Poller struct {
+	url      string
+	filter   Filter
+	server   *godxmap.Server
+	interval time.Duration
+	client   *http.Client
+}
+
+// Option configures a [Poller] instance.
+type Option func(*Poller)
+
+// WithInterval sets the polling interval. The default is two minutes, the length of a WSPR cycle.
+func WithInterval(interval time.Duration) Option {
+	return func(p *Poller) {
+		p.interval = interval
+	}
+}
+
+// WithURL uses the query API at the given URL instead of [DefaultURL].
+func WithURL(url string) Option {
+	return func(p *Poller) {
+		p.url = url
+	}
+}
+
+// NewPoller creates a new poller for the reports of the transmitting stations that pass the given filter.
+// To actually start polling, use the Run method.
+func NewPoller(filter Filter, server *godxmap.Server, options ...Option) *Poller {
+	result := &Poller{
+		url:      DefaultURL,
+		filter:   filter,
+		server:   server,
+		interval: defaultInterval,
+		client:   &http.Client{Timeout: requestTimeout},
+	}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+// Run polls the WSPRnet database until the given context is done. Failed requests are logged and retried with the next poll.
+func (p *Poller) Run(ctx context.Context) error {
+	conditions, err := p.filter.conditions()
+	if err != nil {
+		return fmt.Errorf("cannot poll WSPRnet: %v", err)
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	var lastID int64
+	for {
+		query := p.query(conditions, lastID)
+		reports, err := p.poll(ctx, query)
+		if err != nil && ctx.Err() == nil {
+			p.server.Logger().Warn("cannot poll WSPRnet", "error", err)
+		}
+		for _, report := range reports {
+			p.show(report)
+			lastID = max(lastID, report.ID)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Poller) query(conditions []string, lastID int64) string {
+	if lastID == 0 {
+		conditions = append(conditions, fmt.Sprintf("time > now() - INTERVAL %d MINUTE", int(initialWindow.Minutes())))
+	} else {
+		conditions = append(conditions, fmt.Sprintf("id > %d", lastID), "time > now() - INTERVAL 1 DAY")
+	}
+	return fmt.Sprintf("SELECT id, toUnixTimestamp(time) AS time, tx_sign, tx_loc, rx_sign, rx_loc, frequency, snr, power, distance, rx_azimuth "+
+		"FROM wspr.rx WHERE %s ORDER BY id LIMIT %d FORMAT JSON", strings.Join(conditions, " AND "), maxReports)
+}
+
+func (p *Poller) poll(ctx context.Context, query string) ([]Report, error) {
+	parameters := url.Values{}
+	parameters.Set("query", query)
+	parameters.Set("output_format_json_quote_64bit_integers", "0")
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url+"?"+parameters.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := p.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", response.Status)
+	}
+
+	var result struct {
+		Data []Report `json:"data"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("invalid response: %v", err)
+	}
+	return result.Data, nil
+}
+
+func (p *Poller) show(report Report) {
+	spot := godxmap.Spot{
+		Time:         time.Unix(report.Time, 0),
+		DX:           report.ReceiverCall,
+		Spotter:      report.TransmitCall,
+		FrequencyKHz: float64(report.FrequencyHz) / 1000,
+		Comments:     fmt.Sprintf("WSPR %+d dB %d dBm %d km", report.SNR, report.PowerDBm, report.DistanceKm),
+		Mode:         godxmap.ModeWSPR,
+	}
+	if godxmap.ValidLocator(report.ReceiverGrid) {
+		spot.Locator = report.ReceiverGrid
+	}
+	err := p.server.ShowSpot(spot)
+	if err != nil {
+		p.server.Logger().Warn("cannot show WSPR report", "call", report.ReceiverCall, "error", err)
+	}
+}